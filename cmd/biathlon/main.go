@@ -2,12 +2,27 @@ package main
 
 import (
 	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"biathlonPrototype/internal/config"
+	"biathlonPrototype/internal/domain"
+	"biathlonPrototype/internal/ingest"
 	"biathlonPrototype/internal/processing"
+	"biathlonPrototype/internal/racegen"
 	"biathlonPrototype/internal/report"
 )
 
@@ -20,6 +35,132 @@ const (
 
 // main serves as the entry point of the program, handling configuration loading, event processing, and report generation
 func main() {
+	keepComments := flag.Bool("keep-comments", false, "echo '#' comments from the event file into the output log")
+	annotations := flag.Bool("annotations", false, "append an annotations section with equipment incidents to the report")
+	splits := flag.Bool("splits", false, "append a section with intermediate split times per lap to the report")
+	byCategory := flag.Bool("by-category", false, "append a classification within every age category to the report")
+	byStartGroup := flag.Bool("by-start-group", false, "append a classification within every start group to the report")
+	teamSize := flag.Int("team-size", 0, "append team standings summing the times of the best N finishers of every configured team")
+	history := flag.Bool("history", false, "append a section with every status change of every competitor to the report")
+	details := flag.Bool("details", false, "append a section with the registration and start times of every competitor to the report")
+	penaltySessions := flag.Bool("penalty-sessions", false, "append a section with every pass through the penalty loops to the report")
+	rangeDetails := flag.Bool("range-details", false, "append a section with the shooting result of every firing range to the report")
+	standings := flag.Bool("standings", false, "append the standings at the end of the events, with projected finish times, to the report")
+	lapPositions := flag.Bool("lap-positions", false, "append the cumulative time and position of every competitor after each lap to the report")
+	rangeStandings := flag.Bool("range-standings", false, "append the arrival ranking and deficit to the leader at every firing range to the report")
+	reorderBufferSize := flag.Int("reorder-buffer", 0, "number of events held back to tolerate out-of-order input")
+	reorderWindow := flag.Duration("reorder-window", 0, "time window held back to tolerate out-of-order input, e.g. 2s")
+	listenTCP := flag.String("listen-tcp", "", "receive live events over TCP on this address instead of reading the event file")
+	listenUDP := flag.String("listen-udp", "", "receive live events over UDP on this address instead of reading the event file, e.g. :4040")
+	eventsPath := flag.String("events", eventsFile, "path of the event file to process")
+	eventFormat := flag.String("event-format", "text", "format of the event files read and written: text or pb")
+	eventsOut := flag.String("events-out", "", "write all processed events, including generated ones, to this file")
+	eventsOutFormat := flag.String("events-out-format", "", "format of the file written by --events-out (defaults to --event-format)")
+	dedupHistory := flag.Int("dedup-history", 0, "drop exact duplicates among this many recent events per competitor")
+	dedupWindow := flag.Duration("dedup-window", 0, "drop exact duplicates among events within this time window per competitor")
+	onlyCompetitors := flag.String("only", "", "comma-separated list of competitor IDs to process, e.g. 1,3")
+	logPath := flag.String("log", outputLogFile, "path of the output log; a .gz suffix compresses it")
+	lapStandingsDir := flag.String("lap-standings", "", "directory to rewrite standings_after_lapN.txt in whenever a competitor completes lap N")
+	reportPath := flag.String("report", outputReportFile, "path of the final report; a .gz suffix compresses it")
+	statusFilter := flag.String("status", "", "comma-separated statuses of the competitors to include in the report, e.g. NotFinished,Disqualified")
+	sortBy := flag.String("sort", "", "comma-separated order of the classification lines: classification, id, name, accuracy or misses")
+	legacyFormat := flag.Bool("legacy-format", false, "write the classification in the original layout without places, gaps and penalty loop counts")
+	templatePath := flag.String("template", "", "text/template file that formats each line of the classification, with optional header and footer templates")
+	startTimes := flag.Bool("start-times", false, "end every line of the classification with the scheduled start, actual start and start diff")
+	podium := flag.Bool("podium", false, "start the report with the top three finishers and their times and gaps")
+	aligned := flag.Bool("aligned", false, "write the classification with a header line and columns padded to a fixed width")
+	speedDecimals := flag.Int("speed-decimals", report.DefaultFormat.SpeedDecimals, "number of decimals of the speeds in the classification")
+	timeDecimals := flag.Int("time-decimals", report.DefaultFormat.TimeDecimals, "number of decimals of the seconds of the times in the classification (0-9)")
+	truncateTimes := flag.Bool("truncate-times", false, "truncate the times in the classification to --time-decimals instead of rounding them")
+	dropHours := flag.Bool("drop-hours", false, "write the times in the classification as MM:SS.sss if every time is under an hour")
+	reportFormat := flag.String("format", "text", "format of the final report: text, json, csv, html, markdown or xml")
+	stationPolicy := flag.String("station-policy", "", "resolve events reported by several timing stations: primary or earliest")
+	primaryStation := flag.String("primary-station", "", "name of the primary timing station for --station-policy primary")
+	sortEvents := flag.Bool("sort-events", false, "sort the whole event file by timestamp before processing")
+	resumePath := flag.String("resume", "", "checkpoint file to resume from and to update while processing the event file")
+	checkpointEvery := flag.Int("checkpoint-every", 100, "number of events between checkpoints when --resume is set")
+	customEvents := flag.String("custom-events", "", "range of custom event IDs accepted by the parser, e.g. 40-49")
+	pursuitFrom := flag.String("pursuit-from", "", "final report of the previous race that seeds the start times of a pursuit")
+	finalEvents := flag.String("final-events", "", "event file of a super-sprint final; the best finalQualifiers of --events advance")
+	keepInProgress := flag.Bool("keep-in-progress", false, "keep competitors without a finish in progress instead of classifying them NotFinished")
+	debugState := flag.Bool("debug-state", false, "check the consistency of the competitor's state after every event")
+	strict := flag.Bool("strict", false, "stop with an error on events that are not allowed in the current status of the competitor")
+	generatePath := flag.String("generate", "", "write the events of a synthetic race to this file and exit")
+	generateCompetitors := flag.Int("competitors", 30, "number of competitors in the race written by --generate")
+	seed := flag.Int64("seed", 1, "seed of the race written by --generate; the same seed gives the same race")
+	flag.Parse()
+
+	if *eventsOutFormat == "" {
+		*eventsOutFormat = *eventFormat
+	}
+	for _, format := range []string{*eventFormat, *eventsOutFormat} {
+		if format != "text" && format != "pb" {
+			fmt.Fprintf(os.Stderr, "Unknown event format '%s' (expected text or pb)\n", format)
+			os.Exit(1)
+		}
+	}
+
+	var reportOptions report.Options
+	if *sortBy != "" {
+		keys, sortErr := report.ParseSortKeys(*sortBy)
+		if sortErr != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing sort order: %v\n", sortErr)
+			os.Exit(1)
+		}
+		reportOptions.SortBy = keys
+	}
+	if *statusFilter != "" {
+		statuses, statusErr := report.ParseStatuses(*statusFilter)
+		if statusErr != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing status filter: %v\n", statusErr)
+			os.Exit(1)
+		}
+		reportOptions.Statuses = statuses
+	}
+	if *speedDecimals < 0 || *timeDecimals < 0 || *timeDecimals > 9 {
+		fmt.Fprintln(os.Stderr, "--speed-decimals must not be negative and --time-decimals must be between 0 and 9")
+		os.Exit(1)
+	}
+	reportOptions.Format = &report.Format{SpeedDecimals: *speedDecimals, TimeDecimals: *timeDecimals, Round: !*truncateTimes, DropHours: *dropHours}
+	reportOptions.Podium = *podium
+	if *legacyFormat && *aligned {
+		fmt.Fprintln(os.Stderr, "--legacy-format and --aligned cannot be combined")
+		os.Exit(1)
+	}
+	if *templatePath != "" && (*legacyFormat || *aligned) {
+		fmt.Fprintln(os.Stderr, "--template cannot be combined with --legacy-format or --aligned")
+		os.Exit(1)
+	}
+	if *startTimes && (*templatePath != "" || *legacyFormat) {
+		fmt.Fprintln(os.Stderr, "--start-times cannot be combined with --template or --legacy-format")
+		os.Exit(1)
+	}
+	if *startTimes {
+		reportOptions.Template = report.StartTimesTemplate
+	}
+	if *legacyFormat {
+		reportOptions.Template = report.LegacyTemplate
+	}
+	if *templatePath != "" {
+		reportTemplate, templateErr := loadTemplate(*templatePath)
+		if templateErr != nil {
+			fmt.Fprintf(os.Stderr, "Error loading report template: %v\n", templateErr)
+			os.Exit(1)
+		}
+		reportOptions.Template = reportTemplate
+	}
+	if !slices.Contains([]string{"text", "json", "csv", "html", "markdown", "xml"}, *reportFormat) {
+		fmt.Fprintf(os.Stderr, "Unknown report format '%s' (expected text, json, csv, html, markdown or xml)\n", *reportFormat)
+		os.Exit(1)
+	}
+
+	if *customEvents != "" {
+		if err := allowCustomEvents(*customEvents); err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing custom event range: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	cfg, err := config.LoadConfiguration(configFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
@@ -27,19 +168,117 @@ func main() {
 	}
 	fmt.Println("Configuration loaded.")
 
+	if *generatePath != "" {
+		if err = generateRace(cfg, *generatePath, *generateCompetitors, *seed); err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating race: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Synthetic race with %d competitors written to %s\n", *generateCompetitors, *generatePath)
+		return
+	}
+
 	simulator := processing.NewSimulator(cfg)
+	simulator.KeepComments = *keepComments
+	simulator.ReorderBufferSize = *reorderBufferSize
+	simulator.ReorderWindow = *reorderWindow
+	simulator.SortEvents = *sortEvents
+	simulator.StrictTransitions = *strict
+	simulator.ValidateState = *debugState
+	simulator.CloseUnfinished = !*keepInProgress
+	simulator.DedupHistory = *dedupHistory
+	simulator.DedupWindow = *dedupWindow
+	simulator.StationPolicy = processing.StationPolicy(*stationPolicy)
+	if simulator.StationPolicy != processing.StationPolicyNone && simulator.StationPolicy != processing.StationPolicyPrimary && simulator.StationPolicy != processing.StationPolicyEarliest {
+		fmt.Fprintf(os.Stderr, "Unknown station policy '%s' (expected primary or earliest)\n", *stationPolicy)
+		os.Exit(1)
+	}
+	simulator.PrimaryStation = *primaryStation
+	if *lapStandingsDir != "" {
+		simulator.Callbacks.OnLapComplete = func(_ *domain.Competitor, lap int, _ *domain.Event) {
+			writeLapStandings(*lapStandingsDir, simulator, lap)
+		}
+	}
+	simulator.OnlyCompetitors, err = parseCompetitorIDs(*onlyCompetitors)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing competitor filter: %v\n", err)
+		os.Exit(1)
+	}
+	if *finalEvents != "" && cfg.FinalQualifiers <= 0 {
+		fmt.Fprintf(os.Stderr, "--final-events requires \"finalQualifiers\" in the configuration\n")
+		os.Exit(1)
+	}
+	if *pursuitFrom != "" {
+		if !cfg.IsPursuit() {
+			fmt.Fprintf(os.Stderr, "--pursuit-from requires \"format\": \"pursuit\" in the configuration\n")
+			os.Exit(1)
+		}
+		simulator.PursuitGaps, err = readStartGaps(*pursuitFrom)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading previous results: %v\n", err)
+			os.Exit(1)
+		}
+	}
 	fmt.Println("Simulator created.")
 
-	fmt.Printf("Loading events from %s...\n", eventsFile)
-	err = simulator.LoadEventsFromFile(eventsFile)
+	if *listenTCP != "" || *listenUDP != "" {
+		err = runListener(simulator, *listenTCP, *listenUDP)
+	} else {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		fmt.Printf("Loading events from %s...\n", *eventsPath)
+		switch {
+		case *eventFormat == "pb":
+			err = simulator.LoadEventsFromBinaryFileCtx(ctx, *eventsPath)
+		case *resumePath != "":
+			err = resumeEventsFromFile(ctx, simulator, *eventsPath, *resumePath, *checkpointEvery)
+		default:
+			err = simulator.LoadEventsFromFileCtx(ctx, *eventsPath)
+		}
+		stop()
+	}
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintf(os.Stderr, "Event processing interrupted, writing partial results: %v\n", err)
+		err = nil
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error processing events: %v\n", err)
 		os.Exit(1)
 	}
 	fmt.Println("Event processing completed.")
+	if simulator.DuplicatesDropped > 0 {
+		fmt.Printf("Dropped %d duplicate events.\n", simulator.DuplicatesDropped)
+	}
+	for _, conflict := range simulator.StationConflicts {
+		fmt.Printf("Station conflict: kept '%s', discarded '%s'\n", conflict.Chosen.MarshalLine(), conflict.Discarded.MarshalLine())
+	}
 
-	fmt.Printf("Writing log to %s...\n", outputLogFile)
-	err = writeLinesToFile(outputLogFile, simulator.OutputLog)
+	if *eventsOut != "" {
+		fmt.Printf("Writing events to %s...\n", *eventsOut)
+		if *eventsOutFormat == "pb" {
+			err = simulator.WriteEventsBinaryFile(*eventsOut)
+		} else {
+			err = simulator.WriteEventsFile(*eventsOut)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing events: %v\n", err)
+		} else {
+			fmt.Println("Events written.")
+		}
+	}
+
+	logLines := simulator.OutputLog
+	var superSprintLines []string
+	if *finalEvents != "" {
+		finalLog, finalReport, finalErr := runSuperSprintFinal(cfg, simulator, *finalEvents)
+		if finalErr != nil {
+			fmt.Fprintf(os.Stderr, "Error processing final events: %v\n", finalErr)
+			os.Exit(1)
+		}
+		logLines = append(append(slices.Clone(logLines), "", "Final:"), finalLog...)
+		superSprintLines = finalReport
+	}
+
+	fmt.Printf("Writing log to %s...\n", *logPath)
+	err = writeLinesToFile(*logPath, logLines)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error writing log: %v\n", err)
 	} else {
@@ -48,20 +287,413 @@ func main() {
 
 	fmt.Println("Generating report...")
 	sortedCompetitors := simulator.GetSortedCompetitors()
-	reportLines := report.GenerateReport(sortedCompetitors)
+	if *lapStandingsDir != "" {
+		// Rewrite the standings once more with the final statuses of the competitors who did not complete a lap
+		lastLap := 0
+		for _, competitor := range sortedCompetitors {
+			lastLap = max(lastLap, len(competitor.LapDetails))
+		}
+		for lap := 1; lap <= lastLap; lap++ {
+			writeLapStandings(*lapStandingsDir, simulator, lap)
+		}
+	}
+	classify := func(w io.Writer, competitors []*domain.Competitor) error {
+		return report.WriteReport(w, competitors, reportOptions)
+	}
+	if *aligned {
+		classify = func(w io.Writer, competitors []*domain.Competitor) error {
+			lines := report.GenerateAlignedReport(competitors, cfg.TotalLaps(), *reportOptions.Format, *startTimes)
+			return writeLines(w, report.ReorderLines(lines, competitors, reportOptions.Select(competitors)))
+		}
+	}
+
+	writeReport := func(w io.Writer) error {
+		if *reportFormat == "markdown" {
+			return writeLines(w, report.GenerateMarkdown(sortedCompetitors, reportOptions))
+		}
+		if *reportFormat != "text" {
+			var data []byte
+			var err error
+			switch *reportFormat {
+			case "csv":
+				data, err = report.GenerateCSV(sortedCompetitors, cfg.TotalLaps(), reportOptions)
+			case "html":
+				data, err = report.GenerateHTML(sortedCompetitors, raceParameters(cfg, simulator.RaceInfo), reportOptions)
+			case "xml":
+				data, err = report.GenerateXML(sortedCompetitors, raceParameters(cfg, simulator.RaceInfo), reportOptions)
+			default:
+				data, err = report.GenerateJSON(sortedCompetitors, reportOptions)
+			}
+			if err != nil {
+				return err
+			}
+			return writeLines(w, []string{strings.TrimSuffix(string(data), "\n")})
+		}
 
-	fmt.Printf("Writing report to %s...\n", outputReportFile)
-	err = writeLinesToFile(outputReportFile, reportLines)
+		if simulator.RaceInfo != nil {
+			if err := writeLines(w, []string{simulator.RaceInfo.String()}); err != nil {
+				return err
+			}
+		}
+		if *podium {
+			if err := writeLines(w, report.GeneratePodium(sortedCompetitors)); err != nil {
+				return err
+			}
+		}
+		if err := writeGroupReports(w, simulator, classify); err != nil {
+			return err
+		}
+		reportLines := make([]string, 0)
+		if cfg.IsRelay() {
+			reportLines = append(reportLines, report.GenerateLegs(sortedCompetitors, cfg.Laps)...)
+		}
+		reportLines = append(reportLines, superSprintLines...)
+		if *byStartGroup {
+			for _, group := range simulator.StartGroups() {
+				reportLines = append(reportLines, "", fmt.Sprintf("Start group %s:", group))
+				if err := writeLines(w, reportLines); err != nil {
+					return err
+				}
+				reportLines = reportLines[:0]
+				if err := classify(w, simulator.GetSortedCompetitorsInStartGroup(group)); err != nil {
+					return err
+				}
+			}
+		}
+		if *byCategory {
+			reportLines = append(reportLines, report.GenerateCategories(sortedCompetitors, slices.Sorted(maps.Keys(cfg.Categories)))...)
+		}
+		if *teamSize > 0 {
+			reportLines = append(reportLines, report.GenerateTeamStandings(sortedCompetitors, *teamSize)...)
+		}
+		if *splits {
+			reportLines = append(reportLines, report.GenerateSplits(sortedCompetitors)...)
+		}
+		if *details {
+			reportLines = append(reportLines, report.GenerateDetails(sortedCompetitors)...)
+			reportLines = append(reportLines, report.GenerateFastestLap(sortedCompetitors)...)
+		}
+		if *history {
+			reportLines = append(reportLines, report.GenerateHistory(sortedCompetitors)...)
+		}
+		if *rangeDetails {
+			reportLines = append(reportLines, report.GenerateRangeDetails(sortedCompetitors)...)
+		}
+		if *standings {
+			reportLines = append(reportLines, generateStandings(simulator.Standings())...)
+		}
+		if *lapPositions {
+			reportLines = append(reportLines, report.GenerateLapPositions(sortedCompetitors)...)
+		}
+		if *rangeStandings {
+			reportLines = append(reportLines, report.GenerateRangeStandings(sortedCompetitors)...)
+		}
+		if *penaltySessions {
+			reportLines = append(reportLines, report.GeneratePenaltySessions(sortedCompetitors)...)
+		}
+		if *annotations {
+			reportLines = append(reportLines, report.GenerateAnnotations(sortedCompetitors)...)
+		}
+		if !*legacyFormat {
+			reportLines = append(reportLines, report.GenerateSummary(report.Summarize(sortedCompetitors, reportOptions))...)
+		}
+		return writeLines(w, reportLines)
+	}
+
+	fmt.Printf("Writing report to %s...\n", *reportPath)
+	err = writeToFile(*reportPath, writeReport)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
 		os.Exit(1)
 	}
 	fmt.Println("Report written.")
+
+	printWarningSummary(simulator.WarningCounts())
+	printStats(simulator.Stats())
 	fmt.Println("Program completed successfully.")
 }
 
+// raceParameters describes the configured race for the header of the HTML report
+func raceParameters(cfg *config.Config, info *domain.RaceInfo) report.RaceParameters {
+	race := report.RaceParameters{
+		Laps:        cfg.TotalLaps(),
+		LapLen:      cfg.LapLen,
+		PenaltyLen:  cfg.PenaltyLen,
+		FiringLines: cfg.TotalFiringLines(),
+		Start:       cfg.Start,
+		StartDelta:  cfg.StartDelta,
+	}
+	if info != nil {
+		race.Title = info.String()
+	}
+	return race
+}
+
+// loadTemplate reads and parses the report template in the file
+func loadTemplate(path string) (*report.ReportTemplate, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading template file: %w", err)
+	}
+	return report.ParseTemplate(filepath.Base(path), string(text))
+}
+
+// resumeEventsFromFile continues processing the event file from the checkpoint, if one exists, and keeps it updated
+func resumeEventsFromFile(ctx context.Context, simulator *processing.Simulator, eventsPath, checkpointPath string, checkpointEvery int) error {
+	simulator.CheckpointPath = checkpointPath
+	simulator.CheckpointEvery = checkpointEvery
+
+	position := processing.FilePosition{}
+	if _, statErr := os.Stat(checkpointPath); statErr == nil {
+		var err error
+		position, err = simulator.LoadCheckpoint(checkpointPath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Resuming from line %d (offset %d) of %s...\n", position.LineNumber, position.Offset, eventsPath)
+	}
+
+	_, err := simulator.LoadEventsFromFileAtCtx(ctx, eventsPath, position)
+	return err
+}
+
+// runListener feeds live events into the simulator until interrupted or a fatal processing error occurs
+func runListener(simulator *processing.Simulator, tcpAddress, udpAddress string) error {
+	listener := ingest.NewListener(tcpAddress, udpAddress, simulator.Logger())
+	if err := listener.Start(); err != nil {
+		return err
+	}
+	fmt.Println("Listening for live events, press Ctrl+C to stop...")
+
+	type runResult struct {
+		processed int
+		err       error
+	}
+	simulator.Clock = processing.WallClock{}
+	deadlines := time.NewTicker(time.Second)
+	defer deadlines.Stop()
+	source := processing.NewChannelEventSource(listener.Events())
+	source.OnTick(deadlines.C, simulator.CheckDeadlines)
+
+	done := make(chan runResult, 1)
+	go func() {
+		processed, err := simulator.Run(source)
+		done <- runResult{processed: processed, err: err}
+	}()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	var result runResult
+	select {
+	case <-interrupt:
+		fmt.Println("Stopping listener...")
+		if err := listener.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing listener: %v\n", err)
+		}
+		result = <-done
+	case result = <-done:
+		if err := listener.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing listener: %v\n", err)
+		}
+	}
+
+	fmt.Printf("Processed %d live events (%d malformed).\n", result.processed, listener.MalformedCount())
+	return result.err
+}
+
+// writeGroupReports writes the classification, separately for every race group when groups are used
+func writeGroupReports(w io.Writer, simulator *processing.Simulator, classify func(io.Writer, []*domain.Competitor) error) error {
+	groups := simulator.Groups()
+	if len(groups) == 0 || (len(groups) == 1 && groups[0] == "") {
+		return classify(w, simulator.GetSortedCompetitors())
+	}
+
+	for _, group := range groups {
+		title := fmt.Sprintf("Group %s:", group)
+		if group == "" {
+			title = "Without group:"
+		}
+		if err := writeLines(w, []string{title}); err != nil {
+			return err
+		}
+		if err := classify(w, simulator.GetSortedCompetitorsInGroup(group)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateStandings returns a section with the standings, marking the projected finish of competitors still racing
+// as an estimate
+func generateStandings(standings []processing.Standing) []string {
+	lines := make([]string, 0, len(standings))
+	for _, standing := range standings {
+		place := "-"
+		if standing.Place > 0 {
+			place = strconv.Itoa(standing.Place) + "."
+		}
+		line := fmt.Sprintf("%s competitor(%d) %s, %d laps, %s", place, standing.Competitor.ID, standing.Competitor.Status,
+			standing.LapsCompleted, domain.FormatDuration(standing.Elapsed))
+		if state := standing.Competitor.CurrentState(); state.OnRange() {
+			line += fmt.Sprintf(", on range %d (%d/%d)", state.Range, state.RangeHits, state.RangeShots)
+		} else if state.InPenaltyLoop {
+			line += fmt.Sprintf(", in the penalty loop (%d loops)", state.PenaltyLoops)
+		}
+		if !standing.Finished && standing.Projected {
+			line += fmt.Sprintf(", projected finish ~%s (estimate)", domain.FormatTime(standing.ProjectedFinish))
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		return lines
+	}
+	return append([]string{"", "Standings:"}, lines...)
+}
+
+// writeLapStandings rewrites the file with the standings after the lap; errors are reported without stopping the race
+func writeLapStandings(dir string, simulator *processing.Simulator, lap int) {
+	filePath := filepath.Join(dir, fmt.Sprintf("standings_after_lap%d.txt", lap))
+	if err := writeLinesToFile(filePath, report.GenerateLapStandings(simulator.GetSortedCompetitors(), lap)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing lap standings: %v\n", err)
+	}
+}
+
+// runSuperSprintFinal processes the final of a super sprint for the competitors who advanced from the qualification
+// and returns the log of the final and the combined report section
+func runSuperSprintFinal(cfg *config.Config, qualification *processing.Simulator, eventsPath string) ([]string, []string, error) {
+	qualificationRanking := qualification.GetSortedCompetitors()
+	entries := report.RankSuperSprint(qualificationRanking, nil, cfg.FinalQualifiers)
+	finalists := make([]int, 0, cfg.FinalQualifiers)
+	for _, entry := range entries {
+		if entry.Advanced {
+			finalists = append(finalists, entry.CompetitorID)
+		}
+	}
+	if len(finalists) == 0 {
+		return nil, nil, fmt.Errorf("no competitor finished the qualification")
+	}
+
+	final := processing.NewSimulator(cfg)
+	final.OnlyCompetitors = finalists
+	final.CloseUnfinished = qualification.CloseUnfinished
+	fmt.Printf("Loading final events from %s...\n", eventsPath)
+	if err := final.LoadEventsFromFile(eventsPath); err != nil {
+		return nil, nil, err
+	}
+
+	entries = report.RankSuperSprint(qualificationRanking, final.GetSortedCompetitors(), cfg.FinalQualifiers)
+	return final.OutputLog, report.GenerateSuperSprint(entries), nil
+}
+
+// readStartGaps reads the gaps to the winner from the final report of a previous race
+func readStartGaps(reportPath string) (map[int]time.Duration, error) {
+	file, err := os.Open(reportPath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening report %s: %w", reportPath, err)
+	}
+	defer file.Close()
+
+	results, err := report.ReadResults(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading report %s: %w", reportPath, err)
+	}
+	return report.StartGaps(results), nil
+}
+
+// allowCustomEvents parses a range like 40-49 and lets the parser accept those event IDs
+func allowCustomEvents(idRange string) error {
+	fromStr, toStr, found := strings.Cut(idRange, "-")
+	if !found {
+		toStr = fromStr
+	}
+	from, err := strconv.Atoi(strings.TrimSpace(fromStr))
+	if err != nil {
+		return fmt.Errorf("invalid event ID '%s': %w", fromStr, err)
+	}
+	to, err := strconv.Atoi(strings.TrimSpace(toStr))
+	if err != nil {
+		return fmt.Errorf("invalid event ID '%s': %w", toStr, err)
+	}
+	return domain.AllowCustomEventIDs(domain.EventID(from), domain.EventID(to))
+}
+
+// printWarningSummary prints the number of warnings per code
+func printWarningSummary(counts map[processing.WarningCode]int) {
+	if len(counts) == 0 {
+		return
+	}
+
+	codes := make([]string, 0, len(counts))
+	for code := range counts {
+		codes = append(codes, string(code))
+	}
+	sort.Strings(codes)
+
+	fmt.Println("Warning summary:")
+	for _, code := range codes {
+		fmt.Printf("  %s: %d\n", code, counts[processing.WarningCode(code)])
+	}
+}
+
+// printStats prints a one-paragraph summary of the processing statistics
+func printStats(stats processing.Stats) {
+	incoming := 0
+	for _, count := range stats.IncomingEvents {
+		incoming += count
+	}
+	statuses := make([]string, 0, len(stats.CompetitorsByStatus))
+	for status, count := range stats.CompetitorsByStatus {
+		statuses = append(statuses, fmt.Sprintf("%d %s", count, status))
+	}
+	sort.Strings(statuses)
+
+	fmt.Printf("Processed %d lines with %d events from %s to %s, generated %d events and recorded %d warnings. Competitors: %s.\n",
+		stats.LinesProcessed, incoming, domain.FormatTime(stats.FirstEventTime), domain.FormatTime(stats.LastEventTime),
+		stats.OutgoingEvents, stats.Warnings, strings.Join(statuses, ", "))
+}
+
+// parseCompetitorIDs parses a comma-separated list of competitor IDs
+func parseCompetitorIDs(list string) ([]int, error) {
+	if list == "" {
+		return nil, nil
+	}
+
+	ids := make([]int, 0)
+	for _, part := range strings.Split(list, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid competitor ID '%s': %w", part, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// generateRace writes the events of a synthetic race in the input format to a file
+func generateRace(cfg *config.Config, filePath string, competitors int, seed int64) error {
+	events, err := racegen.Generate(cfg, competitors, seed)
+	if err != nil {
+		return err
+	}
+	lines := make([]string, len(events))
+	for i, event := range events {
+		lines[i] = event.MarshalLine()
+	}
+	return writeLinesToFile(filePath, lines)
+}
+
 // writeLinesToFile writes a slice of lines to a file
-func writeLinesToFile(filePath string, lines []string) (err error) {
+func writeLinesToFile(filePath string, lines []string) error {
+	return writeToFile(filePath, func(w io.Writer) error {
+		return writeLines(w, lines)
+	})
+}
+
+// writeToFile creates the file, or a gzip-compressed file for a .gz suffix, and lets write fill it through a buffer
+func writeToFile(filePath string, write func(io.Writer) error) (err error) {
 	dir := filepath.Dir(filePath)
 	if mkdirErr := os.MkdirAll(dir, 0755); mkdirErr != nil {
 		return fmt.Errorf("failed to create directory %s: %w", dir, mkdirErr)
@@ -81,15 +713,35 @@ func writeLinesToFile(filePath string, lines []string) (err error) {
 		}
 	}()
 
-	writer := bufio.NewWriter(file)
-	for _, line := range lines {
-		if _, err = writer.WriteString(line + "\n"); err != nil {
-			return fmt.Errorf("error writing line to file %s: %w", filePath, err)
-		}
+	var output io.Writer = file
+	var gzipWriter *gzip.Writer
+	if strings.HasSuffix(filePath, ".gz") {
+		gzipWriter = gzip.NewWriter(file)
+		output = gzipWriter
+	}
+
+	writer := bufio.NewWriter(output)
+	if err = write(writer); err != nil {
+		return fmt.Errorf("error writing to file %s: %w", filePath, err)
 	}
 
 	if err = writer.Flush(); err != nil {
 		return fmt.Errorf("error flushing buffer to file %s: %w", filePath, err)
 	}
+	if gzipWriter != nil {
+		if err = gzipWriter.Close(); err != nil {
+			return fmt.Errorf("error finishing compressed file %s: %w", filePath, err)
+		}
+	}
+	return nil
+}
+
+// writeLines writes every line followed by a newline, stopping at the first error
+func writeLines(w io.Writer, lines []string) error {
+	for _, line := range lines {
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
 	return nil
 }
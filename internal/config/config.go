@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"biathlonPrototype/internal/domain"
@@ -18,10 +20,353 @@ type Config struct {
 	Start       string  `json:"start"`
 	StartDelta  string  `json:"startDelta"`
 
-	ParsedStart      time.Time     `json:"-"`
+	// Format selects the race format; Laps and FiringLines are per leg in relays
+	Format RaceFormat `json:"format,omitempty"`
+	Legs   int        `json:"legs,omitempty"`
+
+	// SpareRounds is the number of spare rounds that may be loaded by hand at each firing range (3 by default in relays)
+	SpareRounds int `json:"spareRounds,omitempty"`
+
+	// FiringSchedule lists the shooting position of each firing range, e.g. "PSPS" for prone, standing, prone, standing
+	FiringSchedule string `json:"firingSchedule,omitempty"`
+
+	// FalseStartPolicy decides what happens to a competitor who starts before the scheduled time
+	FalseStartPolicy  FalseStartPolicy `json:"falseStartPolicy,omitempty"`
+	FalseStartPenalty string           `json:"falseStartPenalty,omitempty"`
+
+	// MissedRangePolicy decides the result of a competitor who finishes without completing all firing ranges
+	MissedRangePolicy MissedRangePolicy `json:"missedRangePolicy,omitempty"`
+
+	// Cutoff is the time of day the course closes; competitors still on the course then do not finish
+	Cutoff string `json:"cutoff,omitempty"`
+
+	// ExpectedShootingStop and ExpectedPenaltyLoop are the times a finish projection expects on each remaining firing
+	// range and per penalty loop
+	ExpectedShootingStop string `json:"expectedShootingStop,omitempty"`
+	ExpectedPenaltyLoop  string `json:"expectedPenaltyLoop,omitempty"`
+
+	// SplitPoints is the distance in metres from the lap start of split point 1, 2, ...; competitors who do not finish
+	// are credited with the last split point they passed on the unfinished lap
+	SplitPoints []float64 `json:"splitPoints,omitempty"`
+
+	// PullLapped takes competitors lapped by the leader off the course; leave it off for training formats
+	PullLapped bool `json:"pullLapped,omitempty"`
+
+	// FinalQualifiers is the number of best qualification finishers who advance to a super-sprint final
+	FinalQualifiers int `json:"finalQualifiers,omitempty"`
+
+	// StartGroups seed competitors into blocks that start with their own start delta, e.g. red, green and blue
+	StartGroups []StartGroupConfig `json:"startGroups,omitempty"`
+
+	// Teams lists the competitor IDs of each team for the team standings, e.g. {"NOR": [1, 4, 7]}
+	Teams map[string][]int `json:"teams,omitempty"`
+
+	// Nations lists the competitor IDs of each nation by its three-letter code, e.g. {"NOR": [1, 4, 7]}
+	Nations map[string][]int `json:"nations,omitempty"`
+
+	// Categories lists the competitor IDs of each age category, e.g. {"M17": [1, 4], "W19": [2, 3]}
+	Categories map[string][]int `json:"categories,omitempty"`
+
+	// Groups describe races held simultaneously on the same course, e.g. men and women
+	Groups []GroupConfig `json:"groups,omitempty"`
+
+	ParsedStart             time.Time     `json:"-"`
+	ParsedStartDelta        time.Duration `json:"-"`
+	ParsedFalseStartPenalty time.Duration `json:"-"`
+	ParsedCutoff            time.Time     `json:"-"`
+
+	ParsedExpectedShootingStop time.Duration `json:"-"`
+	ParsedExpectedPenaltyLoop  time.Duration `json:"-"`
+}
+
+// RaceFormat identifies the race format
+type RaceFormat string
+
+const (
+	FormatIndividual RaceFormat = ""
+	FormatRelay      RaceFormat = "relay"
+	FormatPursuit    RaceFormat = "pursuit"
+	FormatMassStart  RaceFormat = "mass-start"
+)
+
+// FalseStartPolicy is the handling of starts before the scheduled time
+type FalseStartPolicy string
+
+const (
+	// FalseStartWarn only reports the false start; the time is measured from the actual start
+	FalseStartWarn FalseStartPolicy = ""
+	// FalseStartPenalty adds FalseStartPenalty to the total time
+	FalseStartPenalty FalseStartPolicy = "penalty"
+	// FalseStartDisqualify disqualifies the competitor
+	FalseStartDisqualify FalseStartPolicy = "disqualify"
+)
+
+// MissedRangePolicy is the handling of a finish without all firing ranges completed
+type MissedRangePolicy string
+
+const (
+	// MissedRangeFinish records the finish with a warning
+	MissedRangeFinish MissedRangePolicy = "finish"
+	// MissedRangeDisqualify disqualifies the competitor
+	MissedRangeDisqualify MissedRangePolicy = "dq"
+	// MissedRangeNotFinished classifies the competitor as NotFinished
+	MissedRangeNotFinished MissedRangePolicy = "notfinished"
+)
+
+// DefaultExpectedShootingStop and DefaultExpectedPenaltyLoop are used by finish projections when the configuration
+// does not set them
+const (
+	DefaultExpectedShootingStop = 30 * time.Second
+	DefaultExpectedPenaltyLoop  = 25 * time.Second
+)
+
+// RelaySpareRounds is the default number of spare rounds a relay competitor may load by hand at each firing range
+const RelaySpareRounds = 3
+
+// PositionForRange returns the scheduled shooting position of the firing range; unknown without a schedule
+func (cfg *Config) PositionForRange(rangeNum int) domain.ShootingPosition {
+	if rangeNum <= 0 || rangeNum > len(cfg.FiringSchedule) {
+		return domain.PositionUnknown
+	}
+	position, _ := domain.ParseShootingPosition(cfg.FiringSchedule[rangeNum-1 : rangeNum])
+	return position
+}
+
+// MaxSpareRounds returns the number of spare rounds allowed per firing range; zero if spare rounds are not used
+func (cfg *Config) MaxSpareRounds() int {
+	if cfg.SpareRounds > 0 {
+		return cfg.SpareRounds
+	}
+	if cfg.IsRelay() {
+		return RelaySpareRounds
+	}
+	return 0
+}
+
+// IsRelay reports whether the race is a relay
+func (cfg *Config) IsRelay() bool {
+	return cfg.Format == FormatRelay
+}
+
+// IsPursuit reports whether the race is a pursuit, where the first competitor across the line wins
+func (cfg *Config) IsPursuit() bool {
+	return cfg.Format == FormatPursuit
+}
+
+// IsMassStart reports whether all competitors start together at the configured start time
+func (cfg *Config) IsMassStart() bool {
+	return cfg.Format == FormatMassStart
+}
+
+// TotalFiringLines returns the number of firing ranges to the finish, over all legs in relays
+func (cfg *Config) TotalFiringLines() int {
+	if cfg.IsRelay() {
+		return cfg.FiringLines * cfg.Legs
+	}
+	return cfg.FiringLines
+}
+
+// TotalLaps returns the number of main laps to the finish, over all legs in relays
+func (cfg *Config) TotalLaps() int {
+	if cfg.IsRelay() {
+		return cfg.Laps * cfg.Legs
+	}
+	return cfg.Laps
+}
+
+// GroupConfig describes one race group: the bib range of its competitors and the course values that differ
+// from the main configuration (zero values are inherited)
+type GroupConfig struct {
+	Name        string  `json:"name"`
+	FirstBib    int     `json:"firstBib"`
+	LastBib     int     `json:"lastBib"`
+	Laps        int     `json:"laps,omitempty"`
+	LapLen      float64 `json:"lapLen,omitempty"`
+	PenaltyLen  float64 `json:"penaltyLen,omitempty"`
+	FiringLines int     `json:"firingLines,omitempty"`
+}
+
+// StartGroupConfig describes a start group: its competitors and the start delta used for their NotStarted deadline
+type StartGroupConfig struct {
+	Name        string `json:"name"`
+	StartDelta  string `json:"startDelta"`
+	Competitors []int  `json:"competitors,omitempty"`
+
 	ParsedStartDelta time.Duration `json:"-"`
 }
 
+// StartGroupForBib returns the name of the start group the competitor ID is listed in, or "" if there is none
+func (cfg *Config) StartGroupForBib(competitorID int) string {
+	for _, group := range cfg.StartGroups {
+		if slices.Contains(group.Competitors, competitorID) {
+			return group.Name
+		}
+	}
+	return ""
+}
+
+// StartGroupDelta returns the start delta of the named start group
+func (cfg *Config) StartGroupDelta(name string) (time.Duration, bool) {
+	for _, group := range cfg.StartGroups {
+		if group.Name == name {
+			return group.ParsedStartDelta, true
+		}
+	}
+	return 0, false
+}
+
+// parseStartGroups parses the start deltas and checks that names are unique and no competitor is in two start groups
+func (cfg *Config) parseStartGroups() error {
+	groupOf := make(map[int]string)
+	for i := range cfg.StartGroups {
+		group := &cfg.StartGroups[i]
+		if group.Name == "" || strings.ContainsAny(group.Name, " \t") {
+			return fmt.Errorf("start group %d needs a name without spaces", i+1)
+		}
+		for _, other := range cfg.StartGroups[:i] {
+			if other.Name == group.Name {
+				return fmt.Errorf("duplicate start group '%s'", group.Name)
+			}
+		}
+		var err error
+		group.ParsedStartDelta, err = domain.ParseDurationFromString(group.StartDelta)
+		if err != nil {
+			return fmt.Errorf("error parsing start delta '%s' of start group '%s': %v", group.StartDelta, group.Name, err)
+		}
+		for _, id := range group.Competitors {
+			if other, ok := groupOf[id]; ok {
+				return fmt.Errorf("competitor %d is listed in start groups '%s' and '%s'", id, other, group.Name)
+			}
+			groupOf[id] = group.Name
+		}
+	}
+	return nil
+}
+
+// GroupForBib returns the name of the group whose bib range contains the competitor ID, or "" if there is none
+func (cfg *Config) GroupForBib(competitorID int) string {
+	for _, group := range cfg.Groups {
+		if competitorID >= group.FirstBib && competitorID <= group.LastBib {
+			return group.Name
+		}
+	}
+	return ""
+}
+
+// TeamForBib returns the name of the team the competitor ID is listed in, or "" if there is none
+func (cfg *Config) TeamForBib(competitorID int) string {
+	return listFor(cfg.Teams, competitorID)
+}
+
+// NationForBib returns the code of the nation the competitor ID is listed in, or "" if there is none
+func (cfg *Config) NationForBib(competitorID int) string {
+	return listFor(cfg.Nations, competitorID)
+}
+
+// listFor returns the name of the list the competitor ID is in, or "" if there is none
+func listFor(lists map[string][]int, competitorID int) string {
+	for name, members := range lists {
+		if slices.Contains(members, competitorID) {
+			return name
+		}
+	}
+	return ""
+}
+
+// CategoryForBib returns the age category the competitor ID is listed in, or "" if there is none
+func (cfg *Config) CategoryForBib(competitorID int) string {
+	return listFor(cfg.Categories, competitorID)
+}
+
+// validateTeams checks that no competitor is listed in two teams, two nations or two categories
+func (cfg *Config) validateTeams() error {
+	if err := validateLists("team", cfg.Teams); err != nil {
+		return err
+	}
+	if err := validateLists("nation", cfg.Nations); err != nil {
+		return err
+	}
+	return validateLists("category", cfg.Categories)
+}
+
+// validateLists checks that every list has a name and no competitor is listed twice
+func validateLists(kind string, lists map[string][]int) error {
+	listOf := make(map[int]string)
+	for name, members := range lists {
+		if name == "" {
+			return fmt.Errorf("%s without a name", kind)
+		}
+		for _, id := range members {
+			if other, ok := listOf[id]; ok && other != name {
+				return fmt.Errorf("competitor %d is listed in %ss '%s' and '%s'", id, kind, other, name)
+			}
+			listOf[id] = name
+		}
+	}
+	return nil
+}
+
+// HasGroup reports whether a group with the name is configured
+func (cfg *Config) HasGroup(name string) bool {
+	for _, group := range cfg.Groups {
+		if group.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ForGroup returns the configuration of a group with its overrides applied; the main configuration for unknown groups
+func (cfg *Config) ForGroup(name string) *Config {
+	for _, group := range cfg.Groups {
+		if group.Name != name {
+			continue
+		}
+		groupCfg := *cfg
+		groupCfg.Groups = nil
+		if group.Laps > 0 {
+			groupCfg.Laps = group.Laps
+		}
+		if group.LapLen > 0 {
+			// The split points lie on the main course
+			groupCfg.LapLen = group.LapLen
+			groupCfg.SplitPoints = nil
+		}
+		if group.PenaltyLen > 0 {
+			groupCfg.PenaltyLen = group.PenaltyLen
+		}
+		if group.FiringLines > 0 {
+			groupCfg.FiringLines = group.FiringLines
+		}
+		return &groupCfg
+	}
+	return cfg
+}
+
+// validateGroups checks that group names are unique and bib ranges valid and disjoint
+func (cfg *Config) validateGroups() error {
+	for i, group := range cfg.Groups {
+		if group.Name == "" || strings.ContainsAny(group.Name, " \t") {
+			return fmt.Errorf("group %d needs a name without spaces", i+1)
+		}
+		if group.FirstBib > group.LastBib || group.LastBib < 0 {
+			return fmt.Errorf("invalid bib range %d-%d of group '%s'", group.FirstBib, group.LastBib, group.Name)
+		}
+		if group.Laps < 0 || group.LapLen < 0 || group.PenaltyLen < 0 || group.FiringLines < 0 {
+			return fmt.Errorf("incorrect values in configuration of group '%s': overrides must not be negative", group.Name)
+		}
+		for _, other := range cfg.Groups[:i] {
+			if other.Name == group.Name {
+				return fmt.Errorf("duplicate group '%s'", group.Name)
+			}
+			if group.FirstBib > 0 && other.FirstBib > 0 && group.FirstBib <= other.LastBib && other.FirstBib <= group.LastBib {
+				return fmt.Errorf("bib ranges of groups '%s' and '%s' overlap", other.Name, group.Name)
+			}
+		}
+	}
+	return nil
+}
+
 // LoadConfiguration loads the configuration from a JSON file
 func LoadConfiguration(filePath string) (*Config, error) {
 	data, err := os.ReadFile(filePath)
@@ -44,9 +389,89 @@ func LoadConfiguration(filePath string) (*Config, error) {
 		return nil, fmt.Errorf("error parsing start delta '%s': %v", cfg.StartDelta, err)
 	}
 
+	if cfg.Cutoff != "" {
+		cfg.ParsedCutoff, err = domain.ParseTimeFromString(fmt.Sprintf("[%s]", cfg.Cutoff))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing cutoff '%s': %v", cfg.Cutoff, err)
+		}
+		if !cfg.ParsedCutoff.After(cfg.ParsedStart) {
+			return nil, fmt.Errorf("cutoff %s is not after the start %s", cfg.Cutoff, cfg.Start)
+		}
+	}
+
+	cfg.ParsedExpectedShootingStop = DefaultExpectedShootingStop
+	if cfg.ExpectedShootingStop != "" {
+		cfg.ParsedExpectedShootingStop, err = domain.ParseDurationFromString(cfg.ExpectedShootingStop)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing expected shooting stop '%s': %v", cfg.ExpectedShootingStop, err)
+		}
+	}
+	cfg.ParsedExpectedPenaltyLoop = DefaultExpectedPenaltyLoop
+	if cfg.ExpectedPenaltyLoop != "" {
+		cfg.ParsedExpectedPenaltyLoop, err = domain.ParseDurationFromString(cfg.ExpectedPenaltyLoop)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing expected penalty loop '%s': %v", cfg.ExpectedPenaltyLoop, err)
+		}
+	}
+
 	if cfg.Laps <= 0 || cfg.LapLen <= 0 || cfg.PenaltyLen <= 0 || cfg.FiringLines <= 0 {
 		return nil, fmt.Errorf("incorrect values in configuration: Laps, LapLen should be > 0, PenaltyLen > 0, FiringLines > 0")
 	}
 
+	for _, position := range cfg.FiringSchedule {
+		if _, err = domain.ParseShootingPosition(string(position)); err != nil {
+			return nil, fmt.Errorf("invalid firing schedule '%s': %v", cfg.FiringSchedule, err)
+		}
+	}
+
+	switch cfg.MissedRangePolicy {
+	case "", MissedRangeFinish, MissedRangeDisqualify, MissedRangeNotFinished:
+	default:
+		return nil, fmt.Errorf("unknown missed range policy '%s' in configuration %s", cfg.MissedRangePolicy, filePath)
+	}
+
+	switch cfg.FalseStartPolicy {
+	case FalseStartWarn, FalseStartDisqualify:
+	case FalseStartPenalty:
+		cfg.ParsedFalseStartPenalty, err = domain.ParseDurationFromString(cfg.FalseStartPenalty)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing false start penalty '%s': %v", cfg.FalseStartPenalty, err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown false start policy '%s' in configuration %s", cfg.FalseStartPolicy, filePath)
+	}
+
+	switch cfg.Format {
+	case FormatIndividual, FormatPursuit, FormatMassStart:
+	case FormatRelay:
+		if cfg.Legs <= 0 {
+			return nil, fmt.Errorf("incorrect values in configuration: Legs should be > 0 for relays")
+		}
+	default:
+		return nil, fmt.Errorf("unknown race format '%s' in configuration %s", cfg.Format, filePath)
+	}
+
+	if cfg.FinalQualifiers < 0 || cfg.SpareRounds < 0 {
+		return nil, fmt.Errorf("incorrect values in configuration: FinalQualifiers and SpareRounds should not be negative")
+	}
+
+	for i, distance := range cfg.SplitPoints {
+		if distance <= 0 || distance >= cfg.LapLen || i > 0 && distance <= cfg.SplitPoints[i-1] {
+			return nil, fmt.Errorf("incorrect values in configuration: SplitPoints should increase within the lap (0 to %g)", cfg.LapLen)
+		}
+	}
+
+	if err = cfg.validateGroups(); err != nil {
+		return nil, fmt.Errorf("error in groups of configuration %s: %v", filePath, err)
+	}
+
+	if err = cfg.parseStartGroups(); err != nil {
+		return nil, fmt.Errorf("error in start groups of configuration %s: %v", filePath, err)
+	}
+
+	if err = cfg.validateTeams(); err != nil {
+		return nil, fmt.Errorf("error in teams, nations or categories of configuration %s: %v", filePath, err)
+	}
+
 	return &cfg, nil
 }
@@ -0,0 +1,191 @@
+package domain
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Field numbers of the binary event encoding, compatible with the protobuf wire format:
+//
+//	message Event {
+//	  uint64 timestamp_ms = 1;   // milliseconds since midnight
+//	  uint64 id = 2;
+//	  uint64 competitor_id = 3;
+//	  repeated string extra_parameters = 4;
+//	  string station = 5;
+//	  string group = 6;
+//	  string nation = 7;
+//	  string team = 8;
+//	  string category = 9;
+//	}
+const (
+	fieldTimestamp       = 1
+	fieldID              = 2
+	fieldCompetitorID    = 3
+	fieldExtraParameters = 4
+	fieldStation         = 5
+	fieldGroup           = 6
+	fieldNation          = 7
+	fieldTeam            = 8
+	fieldCategory        = 9
+
+	wireVarint          = 0
+	wireFixed64         = 1
+	wireLengthDelimited = 2
+	wireFixed32         = 5
+)
+
+// maxEventFrameSize limits the size of a single encoded event in a stream
+const maxEventFrameSize = 1 << 20
+
+// EncodeEvent encodes an event into its binary representation
+func EncodeEvent(event *Event) []byte {
+	buffer := make([]byte, 0, 32)
+	buffer = appendVarintField(buffer, fieldTimestamp, uint64(timeOfDay(event.Timestamp).Milliseconds()))
+	buffer = appendVarintField(buffer, fieldID, uint64(event.ID))
+	buffer = appendVarintField(buffer, fieldCompetitorID, uint64(event.CompetitorID))
+	for _, parameter := range event.ExtraParameters {
+		buffer = appendStringField(buffer, fieldExtraParameters, parameter)
+	}
+	if event.Station != "" {
+		buffer = appendStringField(buffer, fieldStation, event.Station)
+	}
+	if event.Group != "" {
+		buffer = appendStringField(buffer, fieldGroup, event.Group)
+	}
+	if event.Nation != "" {
+		buffer = appendStringField(buffer, fieldNation, event.Nation)
+	}
+	if event.Team != "" {
+		buffer = appendStringField(buffer, fieldTeam, event.Team)
+	}
+	if event.Category != "" {
+		buffer = appendStringField(buffer, fieldCategory, event.Category)
+	}
+	return buffer
+}
+
+// DecodeEvent decodes an event from its binary representation
+func DecodeEvent(data []byte) (*Event, error) {
+	event := &Event{
+		Timestamp:       time.Date(0, time.January, 1, 0, 0, 0, 0, time.UTC),
+		ExtraParameters: make([]string, 0),
+	}
+
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, fmt.Errorf("invalid field key in encoded event")
+		}
+		data = data[n:]
+		fieldNumber, wireType := key>>3, key&7
+
+		switch wireType {
+		case wireVarint:
+			value, n := binary.Uvarint(data)
+			if n <= 0 {
+				return nil, fmt.Errorf("invalid varint for field %d in encoded event", fieldNumber)
+			}
+			data = data[n:]
+			switch fieldNumber {
+			case fieldTimestamp:
+				event.Timestamp = event.Timestamp.Add(time.Duration(value) * time.Millisecond)
+			case fieldID:
+				event.ID = EventID(value)
+			case fieldCompetitorID:
+				event.CompetitorID = int(value)
+			}
+		case wireLengthDelimited:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < length {
+				return nil, fmt.Errorf("invalid length for field %d in encoded event", fieldNumber)
+			}
+			value := string(data[n : n+int(length)])
+			data = data[n+int(length):]
+			switch fieldNumber {
+			case fieldExtraParameters:
+				event.ExtraParameters = append(event.ExtraParameters, value)
+			case fieldStation:
+				event.Station = value
+			case fieldGroup:
+				event.Group = value
+			case fieldNation:
+				event.Nation = value
+			case fieldTeam:
+				event.Team = value
+			case fieldCategory:
+				event.Category = value
+			}
+		case wireFixed64:
+			if len(data) < 8 {
+				return nil, fmt.Errorf("truncated field %d in encoded event", fieldNumber)
+			}
+			data = data[8:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return nil, fmt.Errorf("truncated field %d in encoded event", fieldNumber)
+			}
+			data = data[4:]
+		default:
+			return nil, fmt.Errorf("unsupported wire type %d for field %d in encoded event", wireType, fieldNumber)
+		}
+	}
+
+	event.IsIncoming = isIncomingEventID(event.ID)
+	event.RawLine = event.MarshalLine()
+	if err := ValidateEvent(event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// WriteEventFrame writes a length-prefixed encoded event to a stream
+func WriteEventFrame(writer io.Writer, event *Event) error {
+	encoded := EncodeEvent(event)
+	frame := binary.AppendUvarint(make([]byte, 0, len(encoded)+binary.MaxVarintLen32), uint64(len(encoded)))
+	frame = append(frame, encoded...)
+	_, err := writer.Write(frame)
+	return err
+}
+
+// ReadEventFrame reads a length-prefixed encoded event from a stream; it returns io.EOF at the end of the stream
+func ReadEventFrame(reader *bufio.Reader) (*Event, error) {
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("error reading event frame length: %w", err)
+	}
+	if length > maxEventFrameSize {
+		return nil, fmt.Errorf("event frame of %d bytes exceeds the limit of %d bytes", length, maxEventFrameSize)
+	}
+
+	data := make([]byte, length)
+	if _, err = io.ReadFull(reader, data); err != nil {
+		return nil, fmt.Errorf("error reading event frame of %d bytes: %w", length, err)
+	}
+	return DecodeEvent(data)
+}
+
+// appendVarintField appends a varint field with its key
+func appendVarintField(buffer []byte, fieldNumber int, value uint64) []byte {
+	buffer = binary.AppendUvarint(buffer, uint64(fieldNumber<<3|wireVarint))
+	return binary.AppendUvarint(buffer, value)
+}
+
+// appendStringField appends a length-delimited string field with its key
+func appendStringField(buffer []byte, fieldNumber int, value string) []byte {
+	buffer = binary.AppendUvarint(buffer, uint64(fieldNumber<<3|wireLengthDelimited))
+	buffer = binary.AppendUvarint(buffer, uint64(len(value)))
+	return append(buffer, value...)
+}
+
+// timeOfDay returns the time elapsed since midnight
+func timeOfDay(t time.Time) time.Duration {
+	return t.Sub(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()))
+}
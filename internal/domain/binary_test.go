@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestEncodeEventRoundTrip(t *testing.T) {
+	for _, event := range sampleEvents(t) {
+		decoded, err := DecodeEvent(EncodeEvent(event))
+		if err != nil {
+			t.Errorf("event %d: %v", event.ID, err)
+			continue
+		}
+		if !reflect.DeepEqual(decoded, event) {
+			t.Errorf("event %d: decode(encode(e)) = %+v, want %+v", event.ID, decoded, event)
+		}
+	}
+}
+
+func TestEventFrameStreamRoundTrip(t *testing.T) {
+	events := sampleEvents(t)
+	var stream bytes.Buffer
+	for _, event := range events {
+		if err := WriteEventFrame(&stream, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reader := bufio.NewReader(&stream)
+	for _, event := range events {
+		decoded, err := ReadEventFrame(reader)
+		if err != nil {
+			t.Fatalf("event %d: %v", event.ID, err)
+		}
+		if !reflect.DeepEqual(decoded, event) {
+			t.Errorf("event %d: read %+v, want %+v", event.ID, decoded, event)
+		}
+	}
+	if _, err := ReadEventFrame(reader); !errors.Is(err, io.EOF) {
+		t.Errorf("read past the last frame: %v, want io.EOF", err)
+	}
+}
+
+func TestReadEventFrameRejectsTruncatedFrames(t *testing.T) {
+	var stream bytes.Buffer
+	if err := WriteEventFrame(&stream, sampleEvents(t)[0]); err != nil {
+		t.Fatal(err)
+	}
+	truncated := stream.Bytes()[:stream.Len()-2]
+	if _, err := ReadEventFrame(bufio.NewReader(bytes.NewReader(truncated))); err == nil || errors.Is(err, io.EOF) {
+		t.Errorf("truncated frame: %v, want an error other than io.EOF", err)
+	}
+}
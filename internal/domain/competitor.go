@@ -2,6 +2,7 @@ package domain
 
 import (
 	"fmt"
+	"slices"
 	"time"
 )
 
@@ -15,15 +16,20 @@ const (
 	StatusFiring       CompetitorStatus = "Firing"
 	StatusPenalized    CompetitorStatus = "Penalized"
 	StatusFinished     CompetitorStatus = "Finished"
+	StatusLapped       CompetitorStatus = "Lapped"
 	StatusNotFinished  CompetitorStatus = "NotFinished"
 	StatusNotStarted   CompetitorStatus = "NotStarted"
 	StatusDisqualified CompetitorStatus = "Disqualified"
 )
 
-// LapDetail stores information about the passage of the main lap
+// LapDetail stores information about the passage of the main lap. Elapsed is the race time at the end of the
+// lap, measured like the total time; it, Duration and the timestamps are zero for laps without a recorded end
 type LapDetail struct {
-	Duration time.Duration
-	Speed    float64
+	Duration  time.Duration
+	Speed     float64
+	Elapsed   time.Duration
+	StartTime time.Time
+	EndTime   time.Time
 }
 
 // PenaltyDetail stores information about penalty laps
@@ -32,60 +38,289 @@ type PenaltyDetail struct {
 	AverageSpeed  float64
 }
 
+// SplitDetail stores an intermediate split time relative to the lap start
+type SplitDetail struct {
+	Index    int
+	Duration time.Duration
+}
+
+// LegDetail stores the result of one relay leg
+type LegDetail struct {
+	Leg         int
+	StartTime   time.Time
+	EndTime     time.Time
+	Hits        int
+	Shots       int
+	SpareRounds int
+	PenaltyLaps int
+	PenaltyTime time.Duration
+}
+
+// Duration returns the time from the start of the leg to the exchange or finish; zero while the leg is running
+func (leg LegDetail) Duration() time.Duration {
+	if leg.EndTime.IsZero() {
+		return 0
+	}
+	return leg.EndTime.Sub(leg.StartTime)
+}
+
+// StatusChange records a change of the competitor's status and the ID of the event that caused it
+type StatusChange struct {
+	Time    time.Time
+	From    CompetitorStatus
+	To      CompetitorStatus
+	EventID EventID
+}
+
+// Incident stores an equipment incident reported during the race
+type Incident struct {
+	Time        time.Time
+	Description string
+}
+
 // Competitor represents the athlete's state
 type Competitor struct {
-	ID                  int
-	Status              CompetitorStatus
-	ScheduledStartTime  time.Time
-	ActualStartTime     time.Time
+	ID                 int
+	Name               string
+	Nation             string
+	Group              string
+	Team               string
+	Category           string
+	StartGroup         string
+	Status             CompetitorStatus
+	RegistrationTime   time.Time
+	ScheduledStartTime time.Time
+	ActualStartTime    time.Time
+	// FalseStartMargin is how long before the scheduled start the competitor started
+	FalseStartMargin time.Duration
+	// TimePenalty is added to the total time, e.g. for a false start
+	TimePenalty time.Duration
+	// RaceStartTime is the common start of pursuit and mass start races; times are measured from it when set
+	RaceStartTime       time.Time
 	FinishTime          time.Time
 	LastEventTime       time.Time
 	CurrentLap          int
 	CurrentLapStartTime time.Time
 	LapDetails          []LapDetail
+	LapSplits           [][]SplitDetail
+	// IncompleteLapTime is the time spent on the lap a competitor was stopped on by a terminal event
+	IncompleteLapTime time.Duration
 
 	// Shooting
 	LastFiringRangeEntered     int
 	TotalFiringRangesCompleted int
 	HitsThisRange              int
+	ShotsThisRange             int
+	SpareRoundsThisRange       int
+	TargetsHitThisRange        []int
+	ShotTimesThisRange         []time.Time
 	TotalHits                  int
 	TotalShots                 int
+	TotalSpareRounds           int
+	PositionThisRange          ShootingPosition
+	RangeEnterTime             time.Time
+	TotalRangeTime             time.Duration
+	RangeDetails               []RangeDetail
 
 	// Fines
 	MissesToPenalize       int
 	PenaltyStartTime       time.Time
+	PenaltyTransition      time.Duration
 	TotalPenaltyTime       time.Duration
 	TotalPenaltyLaps       int
+	PenaltySessions        []PenaltySession
 	PenaltyDetails         PenaltyDetail
-	DisqualificationReason string
+	DisqualificationReason DisqualificationReason
+
+	// Disqualification records when and by which event a disqualification was decided, and the jury's rule reference
+	Disqualification Disqualification
+
+	// AverageSpeed is the speed up to the end of the last completed lap and Distance the total CoveredDistance, both
+	// set when the competitor reaches a final status; OverallSpeed is the CalculateOverallSpeed of a finisher
+	AverageSpeed float64
+	Distance     float64
+	OverallSpeed float64
+
+	// Relay legs; empty in individual races
+	Legs []LegDetail
+
+	// Annotations
+	Incidents []Incident
+
+	// History lists every status change; change the status with SetStatus to keep it complete
+	History []StatusChange
 }
 
 // NewCompetitor creates a new athlete
 func NewCompetitor(id int, registrationTime time.Time) *Competitor {
 	return &Competitor{
-		ID:            id,
-		Status:        StatusRegistered,
-		LastEventTime: registrationTime,
-		LapDetails:    make([]LapDetail, 0),
+		ID:               id,
+		Status:           StatusRegistered,
+		RegistrationTime: registrationTime,
+		LastEventTime:    registrationTime,
+		LapDetails:       make([]LapDetail, 0),
+	}
+}
+
+// Clone returns a deep copy of the competitor
+func (competitor *Competitor) Clone() *Competitor {
+	clone := *competitor
+	clone.LapDetails = slices.Clone(competitor.LapDetails)
+	clone.LapSplits = make([][]SplitDetail, len(competitor.LapSplits))
+	for i, splits := range competitor.LapSplits {
+		clone.LapSplits[i] = slices.Clone(splits)
+	}
+	clone.Incidents = slices.Clone(competitor.Incidents)
+	clone.Legs = slices.Clone(competitor.Legs)
+	clone.TargetsHitThisRange = slices.Clone(competitor.TargetsHitThisRange)
+	clone.ShotTimesThisRange = slices.Clone(competitor.ShotTimesThisRange)
+	clone.RangeDetails = slices.Clone(competitor.RangeDetails)
+	clone.PenaltySessions = slices.Clone(competitor.PenaltySessions)
+	clone.History = slices.Clone(competitor.History)
+	return &clone
+}
+
+// SetStatus changes the status of the competitor and records the change in the history
+func (competitor *Competitor) SetStatus(status CompetitorStatus, at time.Time, eventID EventID) {
+	if status == competitor.Status {
+		return
+	}
+	competitor.History = append(competitor.History, StatusChange{Time: at, From: competitor.Status, To: status, EventID: eventID})
+	competitor.Status = status
+}
+
+// CurrentLeg returns the relay leg in progress or the last one, nil in individual races
+func (competitor *Competitor) CurrentLeg() *LegDetail {
+	if len(competitor.Legs) == 0 {
+		return nil
+	}
+	return &competitor.Legs[len(competitor.Legs)-1]
+}
+
+// Splits returns the split times recorded for a lap (numbered from 1)
+func (competitor *Competitor) Splits(lap int) []SplitDetail {
+	if lap < 1 || lap > len(competitor.LapSplits) {
+		return nil
+	}
+	return competitor.LapSplits[lap-1]
+}
+
+// ElapsedAfterLap returns the race time at the end of a lap (numbered from 1); false if the lap has no recorded end
+func (competitor *Competitor) ElapsedAfterLap(lap int) (time.Duration, bool) {
+	if lap < 1 || lap > len(competitor.LapDetails) {
+		return 0, false
+	}
+	detail := competitor.LapDetails[lap-1]
+	if detail.Duration <= 0 || detail.Elapsed <= 0 {
+		return 0, false
+	}
+	return detail.Elapsed, true
+}
+
+// BestLap returns the fastest lap (numbered from 1) of the competitor; laps without a recorded duration are skipped
+// and the earlier lap wins a tie. False if no lap has a duration
+func (competitor *Competitor) BestLap() (int, LapDetail, bool) {
+	best := 0
+	for i, detail := range competitor.LapDetails {
+		if detail.Duration > 0 && (best == 0 || detail.Duration < competitor.LapDetails[best-1].Duration) {
+			best = i + 1
+		}
+	}
+	if best == 0 {
+		return 0, LapDetail{}, false
+	}
+	return best, competitor.LapDetails[best-1], true
+}
+
+// CoveredDistance returns the distance a competitor covered: the laps up to the last one with a recorded end, all
+// completed penalty loops and, on the lap after it, the last split point passed, with splitPoints the distance of
+// split point 1, 2, ... from the lap start. AtLapEnd counts only the penalty loops completed by the end of that lap,
+// and elapsed is the race time then; together they give the average speed
+func (competitor *Competitor) CoveredDistance(lapLen, penaltyLen float64, splitPoints []float64) (total, atLapEnd float64, elapsed time.Duration) {
+	laps := 0
+	var lapEnd time.Time
+	for lap := len(competitor.LapDetails); lap >= 1; lap-- {
+		if lapElapsed, ok := competitor.ElapsedAfterLap(lap); ok {
+			laps, elapsed, lapEnd = lap, lapElapsed, competitor.LapDetails[lap-1].EndTime
+			break
+		}
+	}
+
+	loops, loopsAtLapEnd := 0, 0
+	for _, session := range competitor.PenaltySessions {
+		if session.Incomplete {
+			continue
+		}
+		loops += session.Loops
+		if laps > 0 && !session.EndTime.After(lapEnd) {
+			loopsAtLapEnd += session.Loops
+		}
 	}
+	atLapEnd = float64(laps)*lapLen + float64(loopsAtLapEnd)*penaltyLen
+	total = float64(laps)*lapLen + float64(loops)*penaltyLen
+
+	if splits := competitor.Splits(laps + 1); len(splits) > 0 {
+		if index := splits[len(splits)-1].Index; index >= 1 && index <= len(splitPoints) {
+			total += splitPoints[index-1]
+		}
+	}
+	return total, atLapEnd, elapsed
 }
 
-// CalculateTotalTime calculates the total time of the race
+// CalculateOverallSpeed returns the average speed of a finisher over the full course, all laps and penalty loops in
+// the total time, or false for competitors without a total time
+func (competitor *Competitor) CalculateOverallSpeed(laps int, lapLen, penaltyLen float64) (float64, bool) {
+	totalTime, ok := competitor.CalculateTotalTime()
+	if !ok {
+		return 0, false
+	}
+	return CalculateSpeed(float64(laps)*lapLen+float64(competitor.TotalPenaltyLaps)*penaltyLen, totalTime), true
+}
+
+// CalculateTotalTime calculates the total time of the race: the time from the actual start to the finish plus the
+// StartDiff and any time penalty
 func (competitor *Competitor) CalculateTotalTime() (time.Duration, bool) {
 	if competitor.Status != StatusFinished {
 		return 0, false
 	}
-	if competitor.ActualStartTime.IsZero() {
+	startDiff, ok := competitor.StartDiff()
+	if !ok {
 		return 0, false
 	}
+	raceDuration := competitor.FinishTime.Sub(competitor.ActualStartTime)
 
-	startDiff := competitor.ActualStartTime.Sub(competitor.ScheduledStartTime)
-	if startDiff < 0 {
-		startDiff = 0
+	return raceDuration + startDiff + competitor.TimePenalty, true
+}
+
+// StartDiff returns the time added to the total time for the start: the delay of the actual start after the
+// scheduled start (zero for an early start) or, in races with a common start, the time from the race start to the
+// actual start, which may be negative. False if the competitor never started
+func (competitor *Competitor) StartDiff() (time.Duration, bool) {
+	if competitor.ActualStartTime.IsZero() {
+		return 0, false
 	}
-	raceDuration := competitor.FinishTime.Sub(competitor.ActualStartTime)
+	if !competitor.RaceStartTime.IsZero() {
+		return competitor.ActualStartTime.Sub(competitor.RaceStartTime), true
+	}
+	return max(competitor.ActualStartTime.Sub(competitor.ScheduledStartTime), 0), true
+}
 
-	return raceDuration + startDiff, true
+// ElapsedTime returns the race time at the given moment, counted like the total time from the common race start,
+// the scheduled start or the actual start if the competitor started early; zero if the competitor has not started
+func (competitor *Competitor) ElapsedTime(at time.Time) time.Duration {
+	if competitor.ActualStartTime.IsZero() {
+		return 0
+	}
+	start := competitor.ActualStartTime
+	if !competitor.RaceStartTime.IsZero() {
+		start = competitor.RaceStartTime
+	} else if !competitor.ScheduledStartTime.IsZero() && competitor.ScheduledStartTime.Before(start) {
+		start = competitor.ScheduledStartTime
+	}
+	if at.Before(start) {
+		return 0
+	}
+	return at.Sub(start)
 }
 
 // FinalStatusString returns a string representation of the final status
@@ -97,14 +332,19 @@ func (competitor *Competitor) FinalStatusString() string {
 			return FormatDuration(totalTime)
 		}
 		return "[Error Calculating Time]"
+	case StatusLapped:
+		return "[Lapped]"
 	case StatusNotFinished:
 		return "[NotFinished]"
 	case StatusNotStarted:
+		if competitor.DisqualificationReason.Detail != "" {
+			return fmt.Sprintf("[NotStarted: %s]", competitor.DisqualificationReason.Detail)
+		}
 		return "[NotStarted]"
 	case StatusDisqualified:
 		reason := ""
-		if competitor.DisqualificationReason != "" {
-			reason = ": " + competitor.DisqualificationReason
+		if !competitor.DisqualificationReason.IsZero() {
+			reason = ": " + competitor.DisqualificationReason.String()
 		}
 		return fmt.Sprintf("[Disqualified%s]", reason)
 	default:
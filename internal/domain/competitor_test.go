@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStartDiffIsPartOfTheTotalTime(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := ParseTimeFromString(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	tests := []struct {
+		name          string
+		scheduled     string
+		raceStart     string
+		actual        string
+		wantStartDiff time.Duration
+	}{
+		{"late start", "10:00:00.000", "", "10:00:01.744", 1744 * time.Millisecond},
+		{"on time", "10:00:00.000", "", "10:00:00.000", 0},
+		{"early start", "10:00:00.000", "", "09:59:59.997", 0},
+		{"common start", "10:00:00.000", "10:00:00.000", "10:00:02.500", 2500 * time.Millisecond},
+		{"before the common start", "10:00:00.000", "10:00:00.000", "09:59:59.900", -100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			competitor := &Competitor{
+				ID:                 1,
+				Status:             StatusFinished,
+				ScheduledStartTime: at(tt.scheduled),
+				ActualStartTime:    at(tt.actual),
+				FinishTime:         at("10:30:00.000"),
+				TimePenalty:        time.Minute,
+			}
+			if tt.raceStart != "" {
+				competitor.RaceStartTime = at(tt.raceStart)
+			}
+			startDiff, ok := competitor.StartDiff()
+			if !ok || startDiff != tt.wantStartDiff {
+				t.Fatalf("start diff %v, %t, want %v", startDiff, ok, tt.wantStartDiff)
+			}
+			totalTime, ok := competitor.CalculateTotalTime()
+			if !ok {
+				t.Fatal("finisher has no total time")
+			}
+			if want := competitor.FinishTime.Sub(competitor.ActualStartTime) + startDiff + competitor.TimePenalty; totalTime != want {
+				t.Errorf("total time %v, want the race time plus the start diff and time penalty %v", totalTime, want)
+			}
+		})
+	}
+}
+
+func TestStartDiffOfACompetitorWhoNeverStarted(t *testing.T) {
+	competitor := &Competitor{ID: 1, Status: StatusNotStarted, ScheduledStartTime: time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)}
+	if startDiff, ok := competitor.StartDiff(); ok || startDiff != 0 {
+		t.Errorf("start diff %v, %t, want none", startDiff, ok)
+	}
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// DisqualificationCode is the stable, machine-readable part of a disqualification or non-finish reason
+type DisqualificationCode string
+
+const (
+	ReasonNotStarted        DisqualificationCode = "NotStarted"
+	ReasonExtraFiringLine   DisqualificationCode = "ExtraFiringLine"
+	ReasonMissedFiringRange DisqualificationCode = "MissedFiringRange"
+	ReasonFalseStart        DisqualificationCode = "FalseStart"
+	ReasonCutoff            DisqualificationCode = "Cutoff"
+	ReasonOther             DisqualificationCode = "Other"
+)
+
+// reasonTexts are the human-readable texts of reasons without a detail
+var reasonTexts = map[DisqualificationCode]string{
+	ReasonNotStarted:        "Not started",
+	ReasonExtraFiringLine:   "Extra firing line",
+	ReasonMissedFiringRange: "Missed firing range",
+	ReasonFalseStart:        "False start",
+	ReasonCutoff:            "Cutoff",
+	ReasonOther:             "Reason not specified",
+}
+
+// DisqualificationReason explains why a competitor ended the race without finishing: a code and an optional
+// human-readable detail
+type DisqualificationReason struct {
+	Code   DisqualificationCode
+	Detail string
+}
+
+// NewReason creates a reason with the given code and detail
+func NewReason(code DisqualificationCode, detail string) DisqualificationReason {
+	return DisqualificationReason{Code: code, Detail: detail}
+}
+
+// OtherReason creates a reason that has no code of its own, described by the text
+func OtherReason(text string) DisqualificationReason {
+	return DisqualificationReason{Code: ReasonOther, Detail: text}
+}
+
+// IsZero reports whether no reason is set
+func (reason DisqualificationReason) IsZero() bool {
+	return reason.Code == ""
+}
+
+// String returns the detail of the reason, or the text of its code if there is no detail
+func (reason DisqualificationReason) String() string {
+	if reason.Detail != "" {
+		return reason.Detail
+	}
+	if text, ok := reasonTexts[reason.Code]; ok {
+		return text
+	}
+	return string(reason.Code)
+}
+
+// Parameters returns the parameters of an outgoing event carrying the reason: the code followed by the words of
+// the detail
+func (reason DisqualificationReason) Parameters() []string {
+	return append([]string{string(reason.Code)}, strings.Fields(reason.Detail)...)
+}
+
+// ParseReasonParameters reads a reason from the parameters of a Disqualified or NotFinished event; parameters that
+// do not start with a known code are treated as the detail of an Other reason
+func ParseReasonParameters(parameters []string) DisqualificationReason {
+	if len(parameters) == 0 {
+		return DisqualificationReason{Code: ReasonOther}
+	}
+	code := DisqualificationCode(parameters[0])
+	if _, known := reasonTexts[code]; !known {
+		return OtherReason(strings.Join(parameters, " "))
+	}
+	return NewReason(code, strings.Join(parameters[1:], " "))
+}
+
+// describeReasonParameters formats the parameters of a Disqualified or NotFinished event as "Code: detail"
+func describeReasonParameters(parameters []string) string {
+	if len(parameters) == 0 {
+		return reasonTexts[ReasonOther]
+	}
+	if _, known := reasonTexts[DisqualificationCode(parameters[0])]; !known || len(parameters) == 1 {
+		return strings.Join(parameters, " ")
+	}
+	return parameters[0] + ": " + strings.Join(parameters[1:], " ")
+}
+
+// Disqualification records how a disqualification was decided: when, the input line of the event that triggered it
+// (empty for deadlines checked without an event) and the rule reference entered by the jury
+type Disqualification struct {
+	Time    time.Time
+	Trigger string
+	Rule    string
+}
+
+// IsZero reports whether no disqualification was recorded
+func (disqualification Disqualification) IsZero() bool {
+	return disqualification.Time.IsZero()
+}
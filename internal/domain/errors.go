@@ -0,0 +1,61 @@
+package domain
+
+import "fmt"
+
+// ParseErrorKind identifies which part of an event line could not be parsed
+type ParseErrorKind int
+
+const (
+	ParseErrorFormat ParseErrorKind = iota
+	ParseErrorTimestamp
+	ParseErrorEventID
+	ParseErrorCompetitorID
+	ParseErrorParameter
+)
+
+// String returns a readable description of the error kind
+func (kind ParseErrorKind) String() string {
+	switch kind {
+	case ParseErrorFormat:
+		return "invalid event string format"
+	case ParseErrorTimestamp:
+		return "error parsing time"
+	case ParseErrorEventID:
+		return "error parsing event ID"
+	case ParseErrorCompetitorID:
+		return "error parsing athlete ID"
+	case ParseErrorParameter:
+		return "invalid event parameter"
+	default:
+		return fmt.Sprintf("parse error(%d)", int(kind))
+	}
+}
+
+// ParseError describes a failure to parse an event line
+type ParseError struct {
+	Kind    ParseErrorKind
+	Line    int
+	Field   int
+	RawLine string
+	Err     error
+}
+
+// Error returns a human-readable description including the position when known
+func (parseError *ParseError) Error() string {
+	message := fmt.Sprintf("%s in string '%s'", parseError.Kind, parseError.RawLine)
+	if parseError.Field > 0 {
+		message = fmt.Sprintf("%s (field %d)", message, parseError.Field)
+	}
+	if parseError.Line > 0 {
+		message = fmt.Sprintf("line %d: %s", parseError.Line, message)
+	}
+	if parseError.Err != nil {
+		message = fmt.Sprintf("%s: %v", message, parseError.Err)
+	}
+	return message
+}
+
+// Unwrap returns the underlying error
+func (parseError *ParseError) Unwrap() error {
+	return parseError.Err
+}
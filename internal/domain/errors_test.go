@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseErrorKinds(t *testing.T) {
+	for _, test := range []struct {
+		line  string
+		kind  ParseErrorKind
+		field int
+	}{
+		{"[10:00:00.000] 1", ParseErrorFormat, 3},
+		{"[10:61:00.000] 1 1", ParseErrorTimestamp, 1},
+		{"[10:00:00.000] x 1", ParseErrorEventID, 2},
+		{"[10:00:00.000] 99 1", ParseErrorEventID, 2},
+		{"[10:00:00.000] 1 abc", ParseErrorCompetitorID, 3},
+		{"[10:00:00.000] 1 0", ParseErrorCompetitorID, 3},
+		{"[10:00:00.000] 2 1", ParseErrorParameter, 4},
+		{"[10:00:00.000] 5 1 1 X", ParseErrorParameter, 5},
+		{"[10:00:00.000] 4 1 extra", ParseErrorParameter, 4},
+	} {
+		_, err := ParseEventFromString(test.line)
+		var parseError *ParseError
+		if !errors.As(err, &parseError) {
+			t.Errorf("%q: error %v is not a ParseError", test.line, err)
+			continue
+		}
+		if parseError.Kind != test.kind || parseError.Field != test.field || parseError.RawLine != test.line {
+			t.Errorf("%q: kind %v, field %d, raw line %q, want %v and field %d", test.line,
+				parseError.Kind, parseError.Field, parseError.RawLine, test.kind, test.field)
+		}
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	parseError := &ParseError{Kind: ParseErrorEventID, Line: 7, Field: 2, RawLine: "[10:00:00.000] x 1", Err: errors.New("bad")}
+	want := "line 7: error parsing event ID in string '[10:00:00.000] x 1' (field 2): bad"
+	if got := parseError.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !strings.Contains((&ParseError{Kind: ParseErrorFormat, RawLine: "x"}).Error(), "invalid event string format in string 'x'") {
+		t.Error("message without position")
+	}
+}
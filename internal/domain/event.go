@@ -22,11 +22,74 @@ const (
 	LeavePenaltyLaps EventID = 9
 	EndLap           EventID = 10
 	CannotContinue   EventID = 11
+	ShotFired        EventID = 12
+	EquipmentIssue   EventID = 13
+	SplitPoint       EventID = 14
+	Withdrawn        EventID = 15
+	Exchange         EventID = 16
+	SpareRound       EventID = 17
+	JuryDecision     EventID = 18
 
 	Disqualified EventID = 32
 	Finished     EventID = 33
+	Lapped       EventID = 34
+	NotFinished  EventID = 35
 )
 
+// IsBuiltin reports whether the event ID is one of the predefined incoming or outgoing events
+func (id EventID) IsBuiltin() bool {
+	switch id {
+	case Register, SetStartTime, OnStartLine, Started, EnterFiringRange, HitTarget, LeaveFiringRange,
+		EnterPenaltyLaps, LeavePenaltyLaps, EndLap, CannotContinue, ShotFired, EquipmentIssue, SplitPoint, Withdrawn, Exchange, SpareRound, JuryDecision, Disqualified, Finished, Lapped, NotFinished:
+		return true
+	default:
+		return false
+	}
+}
+
+// EventIDRange is an inclusive range of event IDs
+type EventIDRange struct {
+	From EventID
+	To   EventID
+}
+
+// Contains reports whether the event ID lies in the range
+func (idRange EventIDRange) Contains(id EventID) bool {
+	return idRange.From > 0 && id >= idRange.From && id <= idRange.To
+}
+
+// customEventIDs is the range of custom event IDs accepted by the parser in addition to the built-in ones
+var customEventIDs EventIDRange
+
+// AllowCustomEventIDs makes the parser accept event IDs in the inclusive range, e.g. for pluggable handlers
+func AllowCustomEventIDs(from, to EventID) error {
+	if from <= 0 || to < from {
+		return fmt.Errorf("invalid custom event ID range %d-%d", from, to)
+	}
+	for id := from; id <= to; id++ {
+		if id.IsBuiltin() {
+			return fmt.Errorf("custom event ID range %d-%d overlaps built-in event ID %d", from, to, id)
+		}
+	}
+	customEventIDs = EventIDRange{From: from, To: to}
+	return nil
+}
+
+// stationPrefix, groupPrefix, nationPrefix, teamPrefix and categoryPrefix mark the optional trailing timing-station,
+// race group, nation, team and age category tokens of an event line
+const (
+	stationPrefix  = "station="
+	groupPrefix    = "group="
+	nationPrefix   = "nation="
+	teamPrefix     = "team="
+	categoryPrefix = "category="
+)
+
+// isIncomingEventID reports whether the event ID belongs to the incoming events
+func isIncomingEventID(id EventID) bool {
+	return id >= Register && id <= JuryDecision
+}
+
 // Event structure to represent an event
 type Event struct {
 	Timestamp       time.Time
@@ -35,43 +98,119 @@ type Event struct {
 	ExtraParameters []string
 	RawLine         string
 	IsIncoming      bool
+	Station         string
+	Group           string
+	Nation          string
+	Team            string
+	Category        string
+
+	// LineNumber and Comment are filled in by line-based event sources
+	LineNumber int
+	Comment    string
 }
 
 // ParseEventFromString parses an event from a log string
 func ParseEventFromString(line string) (*Event, error) {
 	parts := strings.Fields(line)
 	if len(parts) < 3 {
-		return nil, fmt.Errorf("invalid event string format: %s", line)
+		return nil, &ParseError{Kind: ParseErrorFormat, Field: len(parts) + 1, RawLine: line}
 	}
 
 	timestamp, err := ParseTimeFromString(parts[0])
 	if err != nil {
-		return nil, fmt.Errorf("error parsing time in string '%s': %v", line, err)
+		return nil, &ParseError{Kind: ParseErrorTimestamp, Field: 1, RawLine: line,
+			Err: fmt.Errorf("expected [HH:MM:SS.sss] with HH 00-23, MM and SS 00-59: %w", err)}
 	}
 
 	eventIDInt, err := strconv.Atoi(parts[1])
 	if err != nil {
-		return nil, fmt.Errorf("error parsing event ID in string '%s': %v", line, err)
+		return nil, &ParseError{Kind: ParseErrorEventID, Field: 2, RawLine: line, Err: err}
 	}
 	eventID := EventID(eventIDInt)
+	if !eventID.IsBuiltin() && !customEventIDs.Contains(eventID) {
+		return nil, &ParseError{Kind: ParseErrorEventID, Field: 2, RawLine: line, Err: unknownEventIDError(eventID)}
+	}
 
 	competitorID, err := strconv.Atoi(parts[2])
 	if err != nil {
-		return nil, fmt.Errorf("error parsing athlete ID in line '%s': %v", line, err)
+		return nil, &ParseError{Kind: ParseErrorCompetitorID, Field: 3, RawLine: line, Err: err}
+	}
+	if competitorID <= 0 {
+		return nil, &ParseError{Kind: ParseErrorCompetitorID, Field: 3, RawLine: line,
+			Err: fmt.Errorf("competitor ID must be a positive number, got %d", competitorID)}
 	}
 
 	extraParameters := parts[3:]
 
-	isIncoming := eventID >= Register && eventID <= CannotContinue
+	station, group, nation, team, category := "", "", "", "", ""
+	for len(extraParameters) > 0 {
+		last := extraParameters[len(extraParameters)-1]
+		if strings.HasPrefix(last, stationPrefix) && station == "" {
+			station = strings.TrimPrefix(last, stationPrefix)
+		} else if strings.HasPrefix(last, groupPrefix) && group == "" {
+			group = strings.TrimPrefix(last, groupPrefix)
+		} else if strings.HasPrefix(last, nationPrefix) && nation == "" {
+			nation = strings.TrimPrefix(last, nationPrefix)
+		} else if strings.HasPrefix(last, teamPrefix) && team == "" {
+			team = strings.TrimPrefix(last, teamPrefix)
+		} else if strings.HasPrefix(last, categoryPrefix) && category == "" {
+			category = strings.TrimPrefix(last, categoryPrefix)
+		} else {
+			break
+		}
+		extraParameters = extraParameters[:len(extraParameters)-1]
+	}
+
+	isIncoming := isIncomingEventID(eventID)
 
-	return &Event{
+	event := &Event{
 		Timestamp:       timestamp,
 		ID:              eventID,
 		CompetitorID:    competitorID,
 		ExtraParameters: extraParameters,
 		RawLine:         line,
 		IsIncoming:      isIncoming,
-	}, nil
+		Station:         station,
+		Group:           group,
+		Nation:          nation,
+		Team:            team,
+		Category:        category,
+	}
+	if err = ValidateEvent(event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// unknownEventIDError describes the accepted event IDs for an unknown one
+func unknownEventIDError(id EventID) error {
+	accepted := fmt.Sprintf("%d-%d, %d-%d", Register, JuryDecision, Disqualified, NotFinished)
+	if customEventIDs.From > 0 {
+		accepted = fmt.Sprintf("%s or custom %d-%d", accepted, customEventIDs.From, customEventIDs.To)
+	}
+	return fmt.Errorf("unknown event ID %d, accepted IDs are %s", id, accepted)
+}
+
+// MarshalLine returns the event in the input log format accepted by ParseEventFromString
+func (event *Event) MarshalLine() string {
+	parts := []string{FormatTime(event.Timestamp), strconv.Itoa(int(event.ID)), strconv.Itoa(event.CompetitorID)}
+	parts = append(parts, event.ExtraParameters...)
+	if event.Nation != "" {
+		parts = append(parts, nationPrefix+event.Nation)
+	}
+	if event.Team != "" {
+		parts = append(parts, teamPrefix+event.Team)
+	}
+	if event.Category != "" {
+		parts = append(parts, categoryPrefix+event.Category)
+	}
+	if event.Group != "" {
+		parts = append(parts, groupPrefix+event.Group)
+	}
+	if event.Station != "" {
+		parts = append(parts, stationPrefix+event.Station)
+	}
+	return strings.Join(parts, " ")
 }
 
 // String returns a string representation of the event for the log
@@ -82,18 +221,22 @@ func (event *Event) String() string {
 	switch event.ID {
 	case Register:
 		details = fmt.Sprintf("The %s registered", competitorStr)
+		if len(event.ExtraParameters) > 0 {
+			details = fmt.Sprintf("%s as %s", details, strings.Join(event.ExtraParameters, " "))
+		}
 	case SetStartTime:
 		startTimeStr := "N/A"
 		if len(event.ExtraParameters) > 0 {
-			parsedTime, err := time.Parse(TimeLayout, event.ExtraParameters[0])
-			if err == nil {
+			// an unparsable start time is shown as given; the simulator rejects it when processing the event
+			startTimeStr = event.ExtraParameters[0]
+			if parsedTime, err := time.Parse(TimeLayout, event.ExtraParameters[0]); err == nil {
 				startTimeStr = parsedTime.Format(TimeLayout)
-			} else {
-				fmt.Printf("Warning: Unable to parse start time '%s' from SetStartTime event for output. Error: %v\n", event.ExtraParameters[0], err)
-				startTimeStr = event.ExtraParameters[0]
 			}
 		}
 		details = fmt.Sprintf("The start time for the %s was set by a draw to %s", competitorStr, startTimeStr)
+		if len(event.ExtraParameters) > 1 {
+			details = fmt.Sprintf("%s in start group %s", details, event.ExtraParameters[1])
+		}
 	case OnStartLine:
 		details = fmt.Sprintf("The %s is on the start line", competitorStr)
 	case Started:
@@ -104,6 +247,10 @@ func (event *Event) String() string {
 			rangeNum = event.ExtraParameters[0]
 		}
 		details = fmt.Sprintf("The %s is on the firing range(%s)", competitorStr, rangeNum)
+		if len(event.ExtraParameters) > 1 {
+			position, _ := ParseShootingPosition(event.ExtraParameters[1])
+			details = fmt.Sprintf("%s, %s position", details, position)
+		}
 	case HitTarget:
 		targetNum := "?"
 		if len(event.ExtraParameters) > 0 {
@@ -124,14 +271,48 @@ func (event *Event) String() string {
 			comment = ": " + strings.Join(event.ExtraParameters, " ")
 		}
 		details = fmt.Sprintf("The %s can`t continue%s", competitorStr, comment)
-	case Disqualified:
+	case ShotFired:
+		if len(event.ExtraParameters) > 0 {
+			details = fmt.Sprintf("The %s fired a shot at target(%s)", competitorStr, event.ExtraParameters[0])
+		} else {
+			details = fmt.Sprintf("The %s fired a shot", competitorStr)
+		}
+	case EquipmentIssue:
+		description := "no description"
+		if len(event.ExtraParameters) > 0 {
+			description = strings.Join(event.ExtraParameters, " ")
+		}
+		details = fmt.Sprintf("The %s had an equipment incident (%s)", competitorStr, description)
+	case SplitPoint:
+		splitNum := "?"
+		if len(event.ExtraParameters) > 0 {
+			splitNum = event.ExtraParameters[0]
+		}
+		details = fmt.Sprintf("The %s passed the split point(%s)", competitorStr, splitNum)
+	case Withdrawn:
 		reason := "Reason not specified"
 		if len(event.ExtraParameters) > 0 {
 			reason = strings.Join(event.ExtraParameters, " ")
 		}
-		details = fmt.Sprintf("The %s is disqualified (%s)", competitorStr, reason)
+		details = fmt.Sprintf("The %s withdrew before the start (%s)", competitorStr, reason)
+	case Exchange:
+		if len(event.ExtraParameters) >= 2 {
+			details = fmt.Sprintf("The %s handed over from leg %s to leg %s", competitorStr, event.ExtraParameters[0], event.ExtraParameters[1])
+		} else {
+			details = fmt.Sprintf("The %s handed over to the next leg", competitorStr)
+		}
+	case SpareRound:
+		details = fmt.Sprintf("The %s loaded a spare round", competitorStr)
+	case JuryDecision:
+		details = fmt.Sprintf("The jury decided the disqualification of the %s under rule %s", competitorStr, strings.Join(event.ExtraParameters, " "))
+	case Disqualified:
+		details = fmt.Sprintf("The %s is disqualified (%s)", competitorStr, describeReasonParameters(event.ExtraParameters))
 	case Finished:
 		details = fmt.Sprintf("The %s has finished", competitorStr)
+	case Lapped:
+		details = fmt.Sprintf("The %s was lapped and pulled from the course", competitorStr)
+	case NotFinished:
+		details = fmt.Sprintf("The %s did not finish (%s)", competitorStr, describeReasonParameters(event.ExtraParameters))
 	default:
 		details = fmt.Sprintf("Unknown event ID(%d) for %s", event.ID, competitorStr)
 	}
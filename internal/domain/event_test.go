@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// sampleEvents returns an event with every parameter of its schema filled for each built-in event ID, the variadic
+// parameters with two words. Every other event also carries the optional trailing tokens
+func sampleEvents(t *testing.T) []*Event {
+	t.Helper()
+	timestamp, err := ParseTimeFromString("[09:30:01.250]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []*Event
+	for id := EventID(1); id <= NotFinished; id++ {
+		if !id.IsBuiltin() {
+			continue
+		}
+		parameters := []string{}
+		for _, spec := range EventSchemas[id] {
+			switch {
+			case spec.Variadic:
+				parameters = append(parameters, "two", "words")
+			case spec.Type == ParameterTime:
+				parameters = append(parameters, "10:00:00.000")
+			case spec.Type == ParameterPositiveInt:
+				parameters = append(parameters, "2")
+			case spec.Type == ParameterShootingPosition:
+				parameters = append(parameters, "S")
+			default:
+				parameters = append(parameters, "A")
+			}
+		}
+		event := &Event{Timestamp: timestamp, ID: id, CompetitorID: int(id) + 100, ExtraParameters: parameters, IsIncoming: isIncomingEventID(id)}
+		if len(events)%2 == 1 {
+			event.Station, event.Group, event.Nation, event.Team, event.Category = "S1", "A", "NOR", "Blue", "M20"
+		}
+		event.RawLine = event.MarshalLine()
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestMarshalLineRoundTrip(t *testing.T) {
+	events := sampleEvents(t)
+	ids := make([]EventID, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+		line := event.MarshalLine()
+		parsed, err := ParseEventFromString(line)
+		if err != nil {
+			t.Errorf("event %d: parsing %q: %v", event.ID, line, err)
+			continue
+		}
+		if !reflect.DeepEqual(parsed, event) {
+			t.Errorf("event %d: parse(marshal(e)) = %+v, want %+v", event.ID, parsed, event)
+		}
+		if again := parsed.MarshalLine(); again != line {
+			t.Errorf("event %d: marshalled again as %q, want %q", event.ID, again, line)
+		}
+	}
+	for _, id := range []EventID{Register, JuryDecision, Disqualified, Finished} {
+		if !slices.Contains(ids, id) {
+			t.Errorf("no sample event with ID %d", id)
+		}
+	}
+}
+
+func TestMarshalLineFormat(t *testing.T) {
+	event, err := ParseEventFromString("[10:08:49.289]   5 1\t1  station=S2 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Station != "S2" || len(event.ExtraParameters) != 1 {
+		t.Errorf("station %q and parameters %q, want S2 and the range number", event.Station, event.ExtraParameters)
+	}
+	if line := event.MarshalLine(); line != "[10:08:49.289] 5 1 1 station=S2" {
+		t.Errorf("MarshalLine() = %q", line)
+	}
+}
+
+func TestStringShowsUnparsableStartTimeAsGiven(t *testing.T) {
+	event := &Event{ID: SetStartTime, CompetitorID: 1, ExtraParameters: []string{"10:61:00"}}
+
+	if got := event.String(); !strings.HasSuffix(got, "The start time for the competitor(1) was set by a draw to 10:61:00") {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -0,0 +1,305 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CompetitorJSON is the stable JSON form of a competitor for external consumers: times of day as "HH:MM:SS.sss",
+// durations as "HH:MM:SS.sss", unknown values omitted and the bookkeeping of the current range and lap left out.
+// Reports embed it to add their own fields
+type CompetitorJSON struct {
+	ID                     int                `json:"id"`
+	Name                   string             `json:"name,omitempty"`
+	Nation                 string             `json:"nation,omitempty"`
+	Team                   string             `json:"team,omitempty"`
+	Category               string             `json:"category,omitempty"`
+	Group                  string             `json:"group,omitempty"`
+	StartGroup             string             `json:"startGroup,omitempty"`
+	Status                 CompetitorStatus   `json:"status"`
+	Result                 string             `json:"result"`
+	TotalTime              string             `json:"totalTime,omitempty"`
+	DisqualificationReason *reasonJSON        `json:"disqualificationReason,omitempty"`
+	RegistrationTime       string             `json:"registrationTime,omitempty"`
+	ScheduledStartTime     string             `json:"scheduledStartTime,omitempty"`
+	ActualStartTime        string             `json:"actualStartTime,omitempty"`
+	FinishTime             string             `json:"finishTime,omitempty"`
+	FalseStartMargin       string             `json:"falseStartMargin,omitempty"`
+	TimePenalty            string             `json:"timePenalty,omitempty"`
+	Laps                   []LapJSON          `json:"laps"`
+	Hits                   int                `json:"hits"`
+	Shots                  int                `json:"shots"`
+	SpareRounds            int                `json:"spareRounds,omitempty"`
+	Accuracy               *float64           `json:"accuracy,omitempty"`
+	Prone                  *positionJSON      `json:"prone,omitempty"`
+	Standing               *positionJSON      `json:"standing,omitempty"`
+	RangeTime              string             `json:"rangeTime,omitempty"`
+	ShootingTime           string             `json:"shootingTime,omitempty"`
+	AverageShotInterval    string             `json:"averageShotInterval,omitempty"`
+	SlowestShotInterval    string             `json:"slowestShotInterval,omitempty"`
+	Ranges                 []rangeJSON        `json:"ranges"`
+	PenaltyLaps            int                `json:"penaltyLaps"`
+	PenaltyTime            string             `json:"penaltyTime,omitempty"`
+	PenaltySpeed           float64            `json:"penaltySpeed"`
+	PenaltySessions        []penaltyJSON      `json:"penaltySessions"`
+	AverageSpeed           float64            `json:"averageSpeed"`
+	Distance               float64            `json:"distance,omitempty"`
+	OverallSpeed           float64            `json:"overallSpeed,omitempty"`
+	Legs                   []legJSON          `json:"legs,omitempty"`
+	Incidents              []incidentJSON     `json:"incidents,omitempty"`
+	History                []statusChangeJSON `json:"history"`
+}
+
+// reasonJSON is the JSON form of a disqualification reason
+type reasonJSON struct {
+	Code    DisqualificationCode `json:"code"`
+	Detail  string               `json:"detail,omitempty"`
+	Time    string               `json:"time,omitempty"`
+	Trigger string               `json:"trigger,omitempty"`
+	Rule    string               `json:"rule,omitempty"`
+}
+
+// LapJSON is the JSON form of a main lap with its split times, measured from the start of the lap
+type LapJSON struct {
+	Lap       int         `json:"lap"`
+	Duration  string      `json:"duration,omitempty"`
+	Speed     float64     `json:"speed"`
+	Elapsed   string      `json:"elapsed,omitempty"`
+	StartTime string      `json:"startTime,omitempty"`
+	EndTime   string      `json:"endTime,omitempty"`
+	Splits    []splitJSON `json:"splits,omitempty"`
+}
+
+// splitJSON is the JSON form of a split time
+type splitJSON struct {
+	Split int    `json:"split"`
+	Time  string `json:"time"`
+}
+
+// positionJSON is the JSON form of the shooting result in one position over the completed firing ranges
+type positionJSON struct {
+	Hits     int      `json:"hits"`
+	Shots    int      `json:"shots"`
+	Accuracy *float64 `json:"accuracy,omitempty"`
+}
+
+// rangeJSON is the JSON form of a firing range
+type rangeJSON struct {
+	Range         int              `json:"range"`
+	Position      ShootingPosition `json:"position,omitempty"`
+	Hits          int              `json:"hits"`
+	Shots         int              `json:"shots"`
+	SpareRounds   int              `json:"spareRounds,omitempty"`
+	Misses        int              `json:"misses"`
+	Accuracy      *float64         `json:"accuracy,omitempty"`
+	EnterTime     string           `json:"enterTime,omitempty"`
+	LeaveTime     string           `json:"leaveTime,omitempty"`
+	Duration      string           `json:"duration,omitempty"`
+	ShootingTime  string           `json:"shootingTime,omitempty"`
+	ShotIntervals []string         `json:"shotIntervals,omitempty"`
+	Incomplete    bool             `json:"incomplete,omitempty"`
+}
+
+// penaltyJSON is the JSON form of a penalty session
+type penaltyJSON struct {
+	Lap        int     `json:"lap"`
+	Range      int     `json:"range,omitempty"`
+	Loops      int     `json:"loops"`
+	StartTime  string  `json:"startTime"`
+	EndTime    string  `json:"endTime"`
+	Duration   string  `json:"duration"`
+	Speed      float64 `json:"speed"`
+	Incomplete bool    `json:"incomplete,omitempty"`
+	Transition string  `json:"transition,omitempty"`
+}
+
+// legJSON is the JSON form of a relay leg
+type legJSON struct {
+	Leg         int    `json:"leg"`
+	StartTime   string `json:"startTime,omitempty"`
+	EndTime     string `json:"endTime,omitempty"`
+	Duration    string `json:"duration,omitempty"`
+	Hits        int    `json:"hits"`
+	Shots       int    `json:"shots"`
+	SpareRounds int    `json:"spareRounds,omitempty"`
+	PenaltyLaps int    `json:"penaltyLaps"`
+	PenaltyTime string `json:"penaltyTime,omitempty"`
+}
+
+// incidentJSON is the JSON form of an equipment incident
+type incidentJSON struct {
+	Time        string `json:"time"`
+	Description string `json:"description"`
+}
+
+// statusChangeJSON is the JSON form of a status change
+type statusChangeJSON struct {
+	Time    string           `json:"time"`
+	From    CompetitorStatus `json:"from"`
+	To      CompetitorStatus `json:"to"`
+	EventID EventID          `json:"eventId"`
+}
+
+// MarshalJSON encodes the competitor in the stable form of JSON
+func (competitor *Competitor) MarshalJSON() ([]byte, error) {
+	return json.Marshal(competitor.JSON())
+}
+
+// JSON returns the competitor in the stable form described by CompetitorJSON, with the derived total time, result,
+// accuracy and shot interval statistics
+func (competitor *Competitor) JSON() CompetitorJSON {
+	out := CompetitorJSON{
+		ID:                 competitor.ID,
+		Name:               competitor.Name,
+		Nation:             competitor.Nation,
+		Team:               competitor.Team,
+		Category:           competitor.Category,
+		Group:              competitor.Group,
+		StartGroup:         competitor.StartGroup,
+		Status:             competitor.Status,
+		Result:             competitor.FinalStatusString(),
+		RegistrationTime:   jsonTime(competitor.RegistrationTime),
+		ScheduledStartTime: jsonTime(competitor.ScheduledStartTime),
+		ActualStartTime:    jsonTime(competitor.ActualStartTime),
+		FinishTime:         jsonTime(competitor.FinishTime),
+		FalseStartMargin:   jsonDuration(competitor.FalseStartMargin),
+		TimePenalty:        jsonDuration(competitor.TimePenalty),
+		Laps:               make([]LapJSON, 0, len(competitor.LapDetails)),
+		Hits:               competitor.TotalHits,
+		Shots:              competitor.TotalShots,
+		SpareRounds:        competitor.TotalSpareRounds,
+		Accuracy:           jsonAccuracy(competitor.Accuracy()),
+		Prone:              competitor.positionJSON(PositionProne),
+		Standing:           competitor.positionJSON(PositionStanding),
+		RangeTime:          jsonDuration(competitor.TotalRangeTime),
+		Ranges:             make([]rangeJSON, 0, len(competitor.RangeDetails)),
+		PenaltyLaps:        competitor.TotalPenaltyLaps,
+		PenaltyTime:        jsonDuration(competitor.TotalPenaltyTime),
+		PenaltySpeed:       competitor.PenaltyDetails.AverageSpeed,
+		PenaltySessions:    make([]penaltyJSON, 0, len(competitor.PenaltySessions)),
+		AverageSpeed:       competitor.AverageSpeed,
+		Distance:           competitor.Distance,
+		OverallSpeed:       competitor.OverallSpeed,
+		History:            make([]statusChangeJSON, 0, len(competitor.History)),
+	}
+	if totalTime, ok := competitor.CalculateTotalTime(); ok {
+		out.TotalTime = FormatDuration(totalTime)
+	}
+	if average, slowest, ok := competitor.ShotIntervalStats(); ok {
+		out.AverageShotInterval, out.SlowestShotInterval = FormatDuration(average), FormatDuration(slowest)
+	}
+	if !competitor.DisqualificationReason.IsZero() {
+		out.DisqualificationReason = &reasonJSON{
+			Code:    competitor.DisqualificationReason.Code,
+			Detail:  competitor.DisqualificationReason.Detail,
+			Time:    jsonTime(competitor.Disqualification.Time),
+			Trigger: competitor.Disqualification.Trigger,
+			Rule:    competitor.Disqualification.Rule,
+		}
+	}
+
+	for i, lap := range competitor.LapDetails {
+		entry := LapJSON{
+			Lap:       i + 1,
+			Duration:  jsonDuration(lap.Duration),
+			Speed:     lap.Speed,
+			Elapsed:   jsonDuration(lap.Elapsed),
+			StartTime: jsonTime(lap.StartTime),
+			EndTime:   jsonTime(lap.EndTime),
+		}
+		for _, split := range competitor.Splits(i + 1) {
+			entry.Splits = append(entry.Splits, splitJSON{Split: split.Index, Time: FormatDuration(split.Duration)})
+		}
+		out.Laps = append(out.Laps, entry)
+	}
+	var shootingTime time.Duration
+	for _, detail := range competitor.RangeDetails {
+		shootingTime += detail.ShootingTime
+		intervals := make([]string, 0, len(detail.ShotIntervals))
+		for _, interval := range detail.ShotIntervals {
+			intervals = append(intervals, FormatDuration(interval))
+		}
+		out.Ranges = append(out.Ranges, rangeJSON{
+			Range:         detail.Range,
+			Position:      detail.Position,
+			Hits:          detail.Hits,
+			Shots:         detail.Shots,
+			SpareRounds:   detail.SpareRounds,
+			Misses:        detail.Misses,
+			Accuracy:      jsonAccuracy(detail.Accuracy()),
+			EnterTime:     jsonTime(detail.EnterTime),
+			LeaveTime:     jsonTime(detail.LeaveTime),
+			Duration:      jsonDuration(detail.Duration()),
+			ShootingTime:  jsonDuration(detail.ShootingTime),
+			ShotIntervals: intervals,
+			Incomplete:    detail.Incomplete,
+		})
+	}
+	out.ShootingTime = jsonDuration(shootingTime)
+	for _, session := range competitor.PenaltySessions {
+		out.PenaltySessions = append(out.PenaltySessions, penaltyJSON{
+			Lap:        session.Lap,
+			Range:      session.Range,
+			Loops:      session.Loops,
+			StartTime:  jsonTime(session.StartTime),
+			EndTime:    jsonTime(session.EndTime),
+			Duration:   FormatDuration(session.Duration()),
+			Speed:      session.Speed,
+			Incomplete: session.Incomplete,
+			Transition: jsonDuration(session.Transition),
+		})
+	}
+	for _, leg := range competitor.Legs {
+		out.Legs = append(out.Legs, legJSON{
+			Leg:         leg.Leg,
+			StartTime:   jsonTime(leg.StartTime),
+			EndTime:     jsonTime(leg.EndTime),
+			Duration:    jsonDuration(leg.Duration()),
+			Hits:        leg.Hits,
+			Shots:       leg.Shots,
+			SpareRounds: leg.SpareRounds,
+			PenaltyLaps: leg.PenaltyLaps,
+			PenaltyTime: jsonDuration(leg.PenaltyTime),
+		})
+	}
+	for _, incident := range competitor.Incidents {
+		out.Incidents = append(out.Incidents, incidentJSON{Time: jsonTime(incident.Time), Description: incident.Description})
+	}
+	for _, change := range competitor.History {
+		out.History = append(out.History, statusChangeJSON{Time: jsonTime(change.Time), From: change.From, To: change.To, EventID: change.EventID})
+	}
+	return out
+}
+
+// positionJSON returns the shooting result of the competitor in a position, or nil if no shots were taken in it
+func (competitor *Competitor) positionJSON(position ShootingPosition) *positionJSON {
+	hits, shots := competitor.ShootingResult(position)
+	if shots == 0 {
+		return nil
+	}
+	return &positionJSON{Hits: hits, Shots: shots, Accuracy: jsonAccuracy(accuracy(hits, shots))}
+}
+
+// jsonTime formats a time of day without brackets, or "" if it is not set
+func jsonTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(TimeLayout)
+}
+
+// jsonDuration formats a positive duration, or "" if it is zero or unknown
+func jsonDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return FormatDuration(d)
+}
+
+// jsonAccuracy returns the accuracy, or nil if no shots were recorded
+func jsonAccuracy(value float64, ok bool) *float64 {
+	if !ok {
+		return nil
+	}
+	return &value
+}
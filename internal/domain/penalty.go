@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// PenaltySession stores one pass through the penalty loops; the sessions add up to TotalPenaltyTime and the completed
+// ones to TotalPenaltyLaps. Range is the firing range whose misses the session serves, 0 if none was completed before
+type PenaltySession struct {
+	Lap       int
+	Range     int
+	Loops     int
+	StartTime time.Time
+	EndTime   time.Time
+	Speed     float64
+	// Incomplete marks a session closed by a terminal event while the competitor was still in the penalty loops
+	Incomplete bool
+	// Transition is the time from leaving the firing range to entering the penalty loops, zero if unknown
+	Transition time.Duration
+}
+
+// Duration returns the time spent in the penalty loops
+func (session PenaltySession) Duration() time.Duration {
+	return session.EndTime.Sub(session.StartTime)
+}
+
+// AggregatePenaltySessions returns the total time of the sessions, including the partial time of incomplete ones,
+// and the average speed of the completed sessions over their own time, like the PenaltyDetail of a competitor
+func AggregatePenaltySessions(sessions []PenaltySession, penaltyLen float64) PenaltyDetail {
+	var total, completedTime time.Duration
+	completedLoops := 0
+	for _, session := range sessions {
+		total += session.Duration()
+		if !session.Incomplete {
+			completedLoops += session.Loops
+			completedTime += session.Duration()
+		}
+	}
+	return PenaltyDetail{TotalDuration: total, AverageSpeed: CalculateSpeed(float64(completedLoops)*penaltyLen, completedTime)}
+}
+
+// RangeTransition returns the time from leaving the last firing range to entering the penalty loops at the given
+// moment; false unless the competitor left that range and has not served its penalty loops yet
+func (competitor *Competitor) RangeTransition(at time.Time) (time.Duration, bool) {
+	if competitor.LastFiringRangeEntered != 0 || len(competitor.RangeDetails) == 0 {
+		return 0, false
+	}
+	detail := competitor.RangeDetails[len(competitor.RangeDetails)-1]
+	if detail.Incomplete || detail.LeaveTime.IsZero() {
+		return 0, false
+	}
+	if n := len(competitor.PenaltySessions); n > 0 && competitor.PenaltySessions[n-1].StartTime.After(detail.LeaveTime) {
+		return 0, false
+	}
+	return at.Sub(detail.LeaveTime), true
+}
+
+// LastCompletedRange returns the firing range the competitor completed last, or 0 if none
+func (competitor *Competitor) LastCompletedRange() int {
+	for i := len(competitor.RangeDetails) - 1; i >= 0; i-- {
+		if !competitor.RangeDetails[i].Incomplete {
+			return competitor.RangeDetails[i].Range
+		}
+	}
+	return 0
+}
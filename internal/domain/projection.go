@@ -0,0 +1,70 @@
+package domain
+
+import "time"
+
+// ProjectionSettings are the race values a finish projection extrapolates over
+type ProjectionSettings struct {
+	Laps          int
+	FiringRanges  int
+	ShotsPerRange int
+	// ShootingStop is the expected time on each remaining firing range and PenaltyLoop the expected time per penalty loop
+	ShootingStop time.Duration
+	PenaltyLoop  time.Duration
+}
+
+// ProjectedFinish estimates when a competitor still racing will finish: the average ski time of the completed laps
+// (without the time on the ranges and in the penalty loops) over the remaining laps, the expected shooting stop at
+// every remaining range and the penalty loops expected from the accuracy so far. False before the first completed
+// lap, when no estimate can be made; a finished competitor's finish time is returned as is
+func (competitor *Competitor) ProjectedFinish(settings ProjectionSettings, now time.Time) (time.Time, bool) {
+	if competitor.Status == StatusFinished {
+		return competitor.FinishTime, true
+	}
+
+	lapsCompleted, lapsTimed := 0, 0
+	var lapEnd time.Time
+	var skiTime time.Duration
+	for i, lap := range competitor.LapDetails {
+		if lap.Duration <= 0 || lap.EndTime.IsZero() {
+			continue
+		}
+		lapsCompleted, lapsTimed = i+1, lapsTimed+1
+		lapEnd = lap.EndTime
+		skiTime += lap.Duration
+	}
+	if lapsTimed == 0 {
+		return time.Time{}, false
+	}
+	for _, detail := range competitor.RangeDetails {
+		if !detail.LeaveTime.After(lapEnd) {
+			skiTime -= detail.Duration()
+		}
+	}
+	for _, session := range competitor.PenaltySessions {
+		if !session.EndTime.After(lapEnd) {
+			skiTime -= session.Duration()
+		}
+	}
+	pace := max(skiTime, 0) / time.Duration(lapsTimed)
+
+	rangesCompleted := 0
+	for _, detail := range competitor.RangeDetails {
+		if !detail.Incomplete {
+			rangesCompleted++
+		}
+	}
+	remainingRanges := max(settings.FiringRanges-rangesCompleted, 0)
+	expectedMisses := float64(competitor.MissesToPenalize)
+	if accuracy, ok := competitor.Accuracy(); ok {
+		expectedMisses += float64(remainingRanges*settings.ShotsPerRange) * (1 - accuracy)
+	}
+
+	remaining := time.Duration(max(settings.Laps-lapsCompleted, 0))*pace +
+		time.Duration(remainingRanges)*settings.ShootingStop +
+		time.Duration(expectedMisses*float64(settings.PenaltyLoop))
+	projected := lapEnd.Add(remaining)
+	if projected.Before(now) {
+		projected = now
+	}
+	return projected, true
+}
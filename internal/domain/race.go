@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RaceHeaderPrefix starts the optional race metadata line of an event file
+const RaceHeaderPrefix = "!race"
+
+// RaceInfo stores race metadata declared in the event file header
+type RaceInfo struct {
+	Name  string
+	Date  string
+	Extra map[string]string
+}
+
+// ParseRaceHeader parses a header line like `!race name="Sprint Men" date=2024-03-12`
+func ParseRaceHeader(line string) (*RaceInfo, error) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, RaceHeaderPrefix) {
+		return nil, fmt.Errorf("race header must start with %s: %s", RaceHeaderPrefix, line)
+	}
+
+	info := &RaceInfo{Extra: make(map[string]string)}
+	rest := strings.TrimSpace(strings.TrimPrefix(trimmed, RaceHeaderPrefix))
+	for rest != "" {
+		key, value, found := strings.Cut(rest, "=")
+		if !found || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("expected key=value in race header at '%s'", rest)
+		}
+		rest = value
+
+		if strings.HasPrefix(rest, `"`) {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted value for '%s' in race header: %v", key, err)
+			}
+			value, _ = strconv.Unquote(quoted)
+			rest = rest[len(quoted):]
+		} else {
+			end := strings.IndexAny(rest, " \t")
+			if end < 0 {
+				end = len(rest)
+			}
+			value, rest = rest[:end], rest[end:]
+		}
+		rest = strings.TrimSpace(rest)
+
+		switch key {
+		case "name":
+			info.Name = value
+		case "date":
+			info.Date = value
+		default:
+			info.Extra[key] = value
+		}
+	}
+
+	return info, nil
+}
+
+// MarshalLine returns the header line in the format accepted by ParseRaceHeader
+func (info *RaceInfo) MarshalLine() string {
+	parts := []string{RaceHeaderPrefix}
+	if info.Name != "" {
+		parts = append(parts, "name="+strconv.Quote(info.Name))
+	}
+	if info.Date != "" {
+		parts = append(parts, "date="+strconv.Quote(info.Date))
+	}
+	for _, key := range info.extraKeys() {
+		parts = append(parts, key+"="+strconv.Quote(info.Extra[key]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// String returns a readable description of the race for the log and report
+func (info *RaceInfo) String() string {
+	description := "Race"
+	if info.Name != "" {
+		description += ": " + info.Name
+	}
+	if info.Date != "" {
+		description += fmt.Sprintf(" (%s)", info.Date)
+	}
+	for _, key := range info.extraKeys() {
+		description += fmt.Sprintf(", %s: %s", key, info.Extra[key])
+	}
+	return description
+}
+
+// extraKeys returns the keys of the extra metadata in sorted order
+func (info *RaceInfo) extraKeys() []string {
+	keys := make([]string, 0, len(info.Extra))
+	for key := range info.Extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ParameterType describes the expected format of an event parameter
+type ParameterType int
+
+const (
+	ParameterText ParameterType = iota
+	ParameterTime
+	ParameterPositiveInt
+	ParameterShootingPosition
+)
+
+// ParameterSpec describes one parameter of an event
+type ParameterSpec struct {
+	Name     string
+	Type     ParameterType
+	Required bool
+	// Variadic parameters consume all remaining words
+	Variadic bool
+}
+
+// EventSchemas lists the parameters of the built-in events; events without an entry take no parameters
+var EventSchemas = map[EventID][]ParameterSpec{
+	Register: {{Name: "name", Type: ParameterText, Variadic: true}},
+	SetStartTime: {
+		{Name: "start time", Type: ParameterTime, Required: true},
+		{Name: "start group", Type: ParameterText},
+	},
+	EnterFiringRange: {
+		{Name: "firing range number", Type: ParameterPositiveInt, Required: true},
+		{Name: "shooting position", Type: ParameterShootingPosition},
+	},
+	HitTarget:      {{Name: "target number", Type: ParameterPositiveInt, Required: true}},
+	CannotContinue: {{Name: "comment", Type: ParameterText, Variadic: true}},
+	ShotFired:      {{Name: "target number", Type: ParameterPositiveInt}},
+	EquipmentIssue: {{Name: "description", Type: ParameterText, Variadic: true}},
+	SplitPoint:     {{Name: "split number", Type: ParameterPositiveInt, Required: true}},
+	Withdrawn:      {{Name: "reason", Type: ParameterText, Variadic: true}},
+	Exchange: {
+		{Name: "outgoing leg", Type: ParameterPositiveInt, Required: true},
+		{Name: "incoming leg", Type: ParameterPositiveInt, Required: true},
+	},
+	JuryDecision: {{Name: "rule reference", Type: ParameterText, Required: true, Variadic: true}},
+	Disqualified: {{Name: "reason", Type: ParameterText, Variadic: true}},
+}
+
+// ValidateEvent checks the parameters of a built-in event against its schema; custom events are not checked
+func ValidateEvent(event *Event) error {
+	if !event.ID.IsBuiltin() {
+		return nil
+	}
+
+	specs := EventSchemas[event.ID]
+	parameters := event.ExtraParameters
+	for i, spec := range specs {
+		field := 4 + i
+		if i >= len(parameters) {
+			if spec.Required {
+				return &ParseError{Kind: ParseErrorParameter, Field: field, RawLine: event.RawLine,
+					Err: fmt.Errorf("missing %s for event %d", spec.Name, event.ID)}
+			}
+			return nil
+		}
+		if spec.Variadic {
+			return nil
+		}
+		if err := validateParameter(spec, parameters[i]); err != nil {
+			return &ParseError{Kind: ParseErrorParameter, Field: field, RawLine: event.RawLine,
+				Err: fmt.Errorf("invalid %s '%s' for event %d: %w", spec.Name, parameters[i], event.ID, err)}
+		}
+	}
+
+	if len(parameters) > len(specs) {
+		return &ParseError{Kind: ParseErrorParameter, Field: 4 + len(specs), RawLine: event.RawLine,
+			Err: fmt.Errorf("event %d takes at most %d parameters, got %d", event.ID, len(specs), len(parameters))}
+	}
+	return nil
+}
+
+// validateParameter checks a single parameter value against its type
+func validateParameter(spec ParameterSpec, value string) error {
+	switch spec.Type {
+	case ParameterTime:
+		_, err := ParseTimeFromString(value)
+		return err
+	case ParameterPositiveInt:
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		if number <= 0 {
+			return fmt.Errorf("must be a positive number")
+		}
+	case ParameterShootingPosition:
+		_, err := ParseShootingPosition(value)
+		return err
+	}
+	return nil
+}
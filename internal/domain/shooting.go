@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ShootingPosition is the position a firing range is shot in
+type ShootingPosition string
+
+const (
+	PositionUnknown  ShootingPosition = ""
+	PositionProne    ShootingPosition = "P"
+	PositionStanding ShootingPosition = "S"
+)
+
+// ParseShootingPosition parses a position indicator, P (prone) or S (standing), ignoring case
+func ParseShootingPosition(value string) (ShootingPosition, error) {
+	switch position := ShootingPosition(strings.ToUpper(value)); position {
+	case PositionProne, PositionStanding:
+		return position, nil
+	default:
+		return PositionUnknown, fmt.Errorf("expected P (prone) or S (standing)")
+	}
+}
+
+// String returns the name of the position
+func (position ShootingPosition) String() string {
+	switch position {
+	case PositionProne:
+		return "prone"
+	case PositionStanding:
+		return "standing"
+	default:
+		return "unknown"
+	}
+}
+
+// RangeDetail stores the shooting result of one firing range
+type RangeDetail struct {
+	Range       int
+	Position    ShootingPosition
+	Hits        int
+	Shots       int
+	SpareRounds int
+	Misses      int
+	EnterTime   time.Time
+	LeaveTime   time.Time
+	// Incomplete marks a range closed by a terminal event while the competitor was still shooting; it has no misses
+	Incomplete bool
+
+	// ShotIntervals are the times between consecutive ShotFired events and ShootingTime the time from the first
+	// to the last one; both are empty if fewer than two shots were reported
+	ShotIntervals []time.Duration
+	ShootingTime  time.Duration
+}
+
+// Duration returns the time spent on the firing range; zero if the entry time is unknown
+func (detail RangeDetail) Duration() time.Duration {
+	if detail.EnterTime.IsZero() {
+		return 0
+	}
+	return detail.LeaveTime.Sub(detail.EnterTime)
+}
+
+// Accuracy returns the share of hits among the shots of the range; false if no shots were recorded
+func (detail RangeDetail) Accuracy() (float64, bool) {
+	return accuracy(detail.Hits, detail.Shots)
+}
+
+// ShotIntervals returns the intervals between consecutive shot times and the time from the first to the last shot
+func ShotIntervals(shotTimes []time.Time) ([]time.Duration, time.Duration) {
+	if len(shotTimes) < 2 {
+		return nil, 0
+	}
+	intervals := make([]time.Duration, 0, len(shotTimes)-1)
+	for i := 1; i < len(shotTimes); i++ {
+		intervals = append(intervals, shotTimes[i].Sub(shotTimes[i-1]))
+	}
+	return intervals, shotTimes[len(shotTimes)-1].Sub(shotTimes[0])
+}
+
+// ShotIntervalStats returns the average and the slowest interval between consecutive shots over all firing ranges;
+// false if no range had two reported shots
+func (competitor *Competitor) ShotIntervalStats() (time.Duration, time.Duration, bool) {
+	var total, slowest time.Duration
+	count := 0
+	for _, detail := range competitor.RangeDetails {
+		for _, interval := range detail.ShotIntervals {
+			total += interval
+			slowest = max(slowest, interval)
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, 0, false
+	}
+	return total / time.Duration(count), slowest, true
+}
+
+// Accuracy returns the share of hits among all shots of the competitor; false if no shots were taken
+func (competitor *Competitor) Accuracy() (float64, bool) {
+	return accuracy(competitor.TotalHits, competitor.TotalShots)
+}
+
+// accuracy returns hits divided by shots, at most 1 even if more hits than shots were recorded
+func accuracy(hits, shots int) (float64, bool) {
+	if shots <= 0 {
+		return 0, false
+	}
+	return min(float64(hits)/float64(shots), 1), true
+}
+
+// FormatAccuracy formats the accuracy of hits among shots as a percentage like "80.0%", "-" without shots. More
+// hits than shots are shown as 100.0% followed by a note
+func FormatAccuracy(hits, shots int) string {
+	share, ok := accuracy(hits, shots)
+	if !ok {
+		return "-"
+	}
+	formatted := fmt.Sprintf("%.1f%%", share*100)
+	if hits > shots {
+		formatted += fmt.Sprintf(" (%d hits recorded for %d shots)", hits, shots)
+	}
+	return formatted
+}
+
+// RangeHits returns the sum of the hits of the competitor's ranges, including an incomplete last one
+func (competitor *Competitor) RangeHits() int {
+	hits := 0
+	for _, detail := range competitor.RangeDetails {
+		hits += detail.Hits
+	}
+	return hits
+}
+
+// ShootingResult returns the hits and shots of the competitor's completed ranges in the given position
+func (competitor *Competitor) ShootingResult(position ShootingPosition) (hits, shots int) {
+	for _, detail := range competitor.RangeDetails {
+		if detail.Position == position && !detail.Incomplete {
+			hits += detail.Hits
+			shots += detail.Shots
+		}
+	}
+	return hits, shots
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// LiveState is a compact, display-friendly summary of where a competitor is right now, e.g. for a scoreboard
+type LiveState struct {
+	Status CompetitorStatus
+	// Lap is the lap the competitor is on, or the last lap after the finish; zero before the start
+	Lap           int
+	LapsCompleted int
+	// Range is the firing range the competitor is shooting on, zero when not on a range
+	Range         int
+	RangeHits     int
+	RangeShots    int
+	InPenaltyLoop bool
+	// PenaltyLoops is the number of penalty loops still to be skied or being skied
+	PenaltyLoops int
+	// Elapsed is the race time at the last recorded event
+	Elapsed time.Duration
+	// Place is the place of a finished competitor, filled in by code that knows the whole field; zero otherwise
+	Place int
+}
+
+// OnRange reports whether the competitor is shooting on a firing range
+func (state LiveState) OnRange() bool {
+	return state.Range > 0
+}
+
+// CurrentState returns the live state of the competitor. The place is left zero as it depends on the other competitors
+func (competitor *Competitor) CurrentState() LiveState {
+	state := LiveState{
+		Status:        competitor.Status,
+		Lap:           competitor.CurrentLap,
+		LapsCompleted: len(competitor.LapDetails),
+		PenaltyLoops:  competitor.MissesToPenalize,
+		Elapsed:       competitor.ElapsedTime(competitor.LastEventTime),
+	}
+	if competitor.Status == StatusFiring && !competitor.RangeEnterTime.IsZero() {
+		state.Range = competitor.LastFiringRangeEntered
+		state.RangeHits = competitor.HitsThisRange
+		state.RangeShots = competitor.ShotsThisRange
+	}
+	if competitor.Status == StatusPenalized && !competitor.PenaltyStartTime.IsZero() {
+		state.InPenaltyLoop = true
+	}
+	return state
+}
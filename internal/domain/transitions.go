@@ -0,0 +1,56 @@
+package domain
+
+import "fmt"
+
+// activeStatuses are the statuses before a final one is reached
+var activeStatuses = []CompetitorStatus{StatusRegistered, StatusReadyToStart, StatusStarted, StatusFiring, StatusPenalized}
+
+// transitionTable lists for every incoming event the competitor statuses in which it is allowed.
+// Register is never allowed for an existing competitor, and the final statuses Finished, Lapped, NotFinished,
+// NotStarted and Disqualified accept no further events except a JuryDecision for a disqualified competitor
+var transitionTable = map[EventID][]CompetitorStatus{
+	SetStartTime:     {StatusRegistered, StatusReadyToStart},
+	OnStartLine:      {StatusRegistered, StatusReadyToStart},
+	Started:          {StatusRegistered, StatusReadyToStart},
+	EnterFiringRange: {StatusStarted, StatusPenalized},
+	HitTarget:        {StatusFiring},
+	ShotFired:        {StatusFiring},
+	LeaveFiringRange: {StatusFiring},
+	EnterPenaltyLaps: {StatusStarted, StatusFiring, StatusPenalized},
+	LeavePenaltyLaps: {StatusPenalized},
+	EndLap:           {StatusStarted},
+	CannotContinue:   activeStatuses,
+	EquipmentIssue:   activeStatuses,
+	SplitPoint:       {StatusStarted, StatusPenalized},
+	Withdrawn:        {StatusRegistered, StatusReadyToStart},
+	Exchange:         {StatusStarted},
+	SpareRound:       {StatusFiring},
+	JuryDecision:     {StatusDisqualified},
+}
+
+// TransitionAllowed reports whether a competitor in the status may receive the event. Custom event IDs are
+// always allowed because their handlers define their own rules
+func TransitionAllowed(status CompetitorStatus, id EventID) bool {
+	if !id.IsBuiltin() {
+		return true
+	}
+	for _, allowed := range transitionTable[id] {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionError reports an event that is not allowed in the current status of the competitor
+type TransitionError struct {
+	CompetitorID int
+	Status       CompetitorStatus
+	Event        *Event
+}
+
+// Error returns a human-readable description of the forbidden transition
+func (transitionError *TransitionError) Error() string {
+	return fmt.Sprintf("event %d at %s is not allowed for competitor %d in status %s",
+		transitionError.Event.ID, FormatTime(transitionError.Event.Timestamp), transitionError.CompetitorID, transitionError.Status)
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// allowedTransitions is the expected transition table: one row per status with a column for each incoming event
+// ID 1 to 18, "x" where the event is allowed
+var allowedTransitions = map[CompetitorStatus]string{
+	//                  1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18
+	StatusRegistered:   ". x x x . . . . . .  x  .  x  .  x  .  .  .",
+	StatusReadyToStart: ". x x x . . . . . .  x  .  x  .  x  .  .  .",
+	StatusStarted:      ". . . . x . . x . x  x  .  x  x  .  x  .  .",
+	StatusFiring:       ". . . . . x x x . .  x  x  x  .  .  .  x  .",
+	StatusPenalized:    ". . . . x . . x x .  x  .  x  x  .  .  .  .",
+	StatusFinished:     ". . . . . . . . . .  .  .  .  .  .  .  .  .",
+	StatusLapped:       ". . . . . . . . . .  .  .  .  .  .  .  .  .",
+	StatusNotFinished:  ". . . . . . . . . .  .  .  .  .  .  .  .  .",
+	StatusNotStarted:   ". . . . . . . . . .  .  .  .  .  .  .  .  .",
+	StatusDisqualified: ". . . . . . . . . .  .  .  .  .  .  .  .  x",
+}
+
+func TestTransitionTable(t *testing.T) {
+	for status, row := range allowedTransitions {
+		cells := strings.Fields(row)
+		if len(cells) != int(JuryDecision) {
+			t.Fatalf("%s: %d columns", status, len(cells))
+		}
+		for i, cell := range cells {
+			id := EventID(i + 1)
+			if got, want := TransitionAllowed(status, id), cell == "x"; got != want {
+				t.Errorf("TransitionAllowed(%s, %d) = %v, want %v", status, id, got, want)
+			}
+		}
+		for _, id := range []EventID{Disqualified, Finished, Lapped, NotFinished} {
+			if TransitionAllowed(status, id) {
+				t.Errorf("outgoing event %d is allowed as input in status %s", id, status)
+			}
+		}
+		if !TransitionAllowed(status, EventID(50)) {
+			t.Errorf("custom event is not allowed in status %s", status)
+		}
+	}
+}
+
+func TestLegalLifecycle(t *testing.T) {
+	status := StatusRegistered
+	for _, step := range []struct {
+		event EventID
+		next  CompetitorStatus
+	}{
+		{SetStartTime, StatusRegistered},
+		{OnStartLine, StatusReadyToStart},
+		{Started, StatusStarted},
+		{SplitPoint, StatusStarted},
+		{EnterFiringRange, StatusFiring},
+		{ShotFired, StatusFiring},
+		{HitTarget, StatusFiring},
+		{SpareRound, StatusFiring},
+		{LeaveFiringRange, StatusStarted},
+		{EnterPenaltyLaps, StatusPenalized},
+		{LeavePenaltyLaps, StatusStarted},
+		{EndLap, StatusFinished},
+	} {
+		if !TransitionAllowed(status, step.event) {
+			t.Fatalf("event %d is not allowed in status %s", step.event, status)
+		}
+		status = step.next
+	}
+}
+
+func TestTransitionError(t *testing.T) {
+	event, err := ParseEventFromString("[10:05:00.000] 6 3 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = fmt.Errorf("processing: %w", &TransitionError{CompetitorID: 3, Status: StatusStarted, Event: event})
+
+	var transitionError *TransitionError
+	if !errors.As(err, &transitionError) {
+		t.Fatalf("%v is not a TransitionError", err)
+	}
+	if transitionError.Status != StatusStarted || transitionError.Event.ID != HitTarget {
+		t.Errorf("status %s and event %d", transitionError.Status, transitionError.Event.ID)
+	}
+	if want := "event 6 at [10:05:00.000] is not allowed for competitor 3 in status Started"; transitionError.Error() != want {
+		t.Errorf("Error() = %q, want %q", transitionError.Error(), want)
+	}
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "fmt"
+
+// Limits are the configured values the state of a competitor is checked against; in relays FiringLines is per leg
+type Limits struct {
+	Laps        int
+	FiringLines int
+}
+
+// Validate checks the internal consistency of the competitor's state and returns every violated invariant
+func (competitor *Competitor) Validate(limits Limits) []error {
+	var violations []error
+	// Shots are only counted when a range is left, so hits on the current and on incomplete ranges have no shots yet
+	completedHits := competitor.TotalHits - competitor.HitsThisRange
+	for _, detail := range competitor.RangeDetails {
+		if detail.Incomplete {
+			completedHits -= detail.Hits
+		}
+	}
+	if completedHits > competitor.TotalShots {
+		violations = append(violations, fmt.Errorf("%d hits on completed firing ranges, but only %d shots", completedHits, competitor.TotalShots))
+	}
+	if len(competitor.LapDetails) > limits.Laps {
+		violations = append(violations, fmt.Errorf("%d laps recorded, but the race has %d", len(competitor.LapDetails), limits.Laps))
+	}
+	if competitor.TotalFiringRangesCompleted > limits.FiringLines {
+		violations = append(violations, fmt.Errorf("%d firing ranges completed, but the race has %d", competitor.TotalFiringRangesCompleted, limits.FiringLines))
+	}
+	if competitor.MissesToPenalize < 0 {
+		violations = append(violations, fmt.Errorf("negative number of outstanding penalty loops %d", competitor.MissesToPenalize))
+	}
+	switch competitor.Status {
+	case StatusFinished, StatusLapped, StatusNotFinished, StatusNotStarted, StatusDisqualified:
+		if competitor.FinishTime.IsZero() {
+			violations = append(violations, fmt.Errorf("final status %s without a finish time", competitor.Status))
+		}
+	}
+	if !competitor.PenaltyStartTime.IsZero() && competitor.Status != StatusPenalized {
+		violations = append(violations, fmt.Errorf("penalty loops entered at %s, but the status is %s", FormatTime(competitor.PenaltyStartTime), competitor.Status))
+	}
+	return violations
+}
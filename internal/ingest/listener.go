@@ -0,0 +1,239 @@
+package ingest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"biathlonPrototype/internal/domain"
+	"biathlonPrototype/internal/processing"
+)
+
+// maxDatagramSize is the largest UDP datagram the listener reads
+const maxDatagramSize = 64 * 1024
+
+// Listener receives event lines over TCP and/or UDP and forwards parsed events to a channel
+type Listener struct {
+	TCPAddress string
+	UDPAddress string
+
+	events      chan *domain.Event
+	logger      *slog.Logger
+	closing     chan struct{}
+	tcpListener net.Listener
+	udpConn     net.PacketConn
+	connections map[net.Conn]struct{}
+	sequences   map[string]int
+	malformed   int
+	mutex       sync.Mutex
+	waitGroup   sync.WaitGroup
+	closeOnce   sync.Once
+}
+
+// NewListener creates a listener that logs received and malformed lines to the logger, the console logger of the
+// processing package if nil; an empty address disables the corresponding protocol
+func NewListener(tcpAddress, udpAddress string, logger *slog.Logger) *Listener {
+	if logger == nil {
+		logger = processing.NewConsoleLogger(os.Stdout, os.Stderr)
+	}
+	return &Listener{
+		TCPAddress:  tcpAddress,
+		UDPAddress:  udpAddress,
+		events:      make(chan *domain.Event, 256),
+		logger:      logger,
+		closing:     make(chan struct{}),
+		connections: make(map[net.Conn]struct{}),
+		sequences:   make(map[string]int),
+	}
+}
+
+// Start binds the configured addresses and begins receiving events
+func (listener *Listener) Start() error {
+	if listener.TCPAddress == "" && listener.UDPAddress == "" {
+		return fmt.Errorf("no TCP or UDP address configured")
+	}
+
+	if listener.TCPAddress != "" {
+		tcpListener, err := net.Listen("tcp", listener.TCPAddress)
+		if err != nil {
+			return fmt.Errorf("error binding TCP address %s: %w", listener.TCPAddress, err)
+		}
+		listener.tcpListener = tcpListener
+		listener.waitGroup.Add(1)
+		go listener.acceptTCP()
+	}
+
+	if listener.UDPAddress != "" {
+		udpConn, err := net.ListenPacket("udp", listener.UDPAddress)
+		if err != nil {
+			if listener.tcpListener != nil {
+				_ = listener.tcpListener.Close()
+			}
+			return fmt.Errorf("error binding UDP address %s: %w", listener.UDPAddress, err)
+		}
+		listener.udpConn = udpConn
+		listener.waitGroup.Add(1)
+		go listener.readUDP()
+	}
+
+	return nil
+}
+
+// Events returns the channel of received events; it is closed after Close
+func (listener *Listener) Events() <-chan *domain.Event {
+	return listener.events
+}
+
+// MalformedCount returns the number of received lines that could not be parsed
+func (listener *Listener) MalformedCount() int {
+	listener.mutex.Lock()
+	defer listener.mutex.Unlock()
+	return listener.malformed
+}
+
+// Close stops receiving, waits for all readers to finish and closes the events channel
+func (listener *Listener) Close() error {
+	var closeErr error
+	listener.closeOnce.Do(func() {
+		close(listener.closing)
+
+		if listener.tcpListener != nil {
+			closeErr = errors.Join(closeErr, listener.tcpListener.Close())
+		}
+		if listener.udpConn != nil {
+			closeErr = errors.Join(closeErr, listener.udpConn.Close())
+		}
+
+		listener.mutex.Lock()
+		for connection := range listener.connections {
+			_ = connection.Close()
+		}
+		listener.mutex.Unlock()
+
+		listener.waitGroup.Wait()
+		close(listener.events)
+	})
+	return closeErr
+}
+
+// acceptTCP accepts TCP connections until the listener is closed
+func (listener *Listener) acceptTCP() {
+	defer listener.waitGroup.Done()
+
+	for {
+		connection, err := listener.tcpListener.Accept()
+		if err != nil {
+			if !listener.isClosing() {
+				listener.logger.Warn(fmt.Sprintf("error accepting TCP connection: %v", err))
+			}
+			return
+		}
+
+		// Close may already have closed the registered connections; a connection accepted since then would keep
+		// readTCP and so Close waiting on an idle client
+		listener.mutex.Lock()
+		if listener.isClosing() {
+			listener.mutex.Unlock()
+			_ = connection.Close()
+			return
+		}
+		listener.connections[connection] = struct{}{}
+		listener.mutex.Unlock()
+
+		listener.waitGroup.Add(1)
+		go listener.readTCP(connection)
+	}
+}
+
+// readTCP reads newline-separated events from a TCP connection
+func (listener *Listener) readTCP(connection net.Conn) {
+	defer listener.waitGroup.Done()
+	defer func() {
+		listener.mutex.Lock()
+		delete(listener.connections, connection)
+		listener.mutex.Unlock()
+		_ = connection.Close()
+	}()
+
+	sourceName := "tcp://" + connection.RemoteAddr().String()
+	scanner := bufio.NewScanner(connection)
+	for scanner.Scan() {
+		if !listener.handleLine(sourceName, scanner.Text()) {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil && !listener.isClosing() {
+		listener.logger.Warn(fmt.Sprintf("error reading from %s: %v", sourceName, err), "source", sourceName)
+	}
+}
+
+// readUDP reads datagrams, each holding one or more event lines
+func (listener *Listener) readUDP() {
+	defer listener.waitGroup.Done()
+
+	buffer := make([]byte, maxDatagramSize)
+	for {
+		n, address, err := listener.udpConn.ReadFrom(buffer)
+		if err != nil {
+			if !listener.isClosing() {
+				listener.logger.Warn(fmt.Sprintf("error reading UDP datagram: %v", err))
+			}
+			return
+		}
+
+		sourceName := "udp://" + address.String()
+		for _, line := range strings.Split(string(buffer[:n]), "\n") {
+			if !listener.handleLine(sourceName, line) {
+				return
+			}
+		}
+	}
+}
+
+// handleLine parses a received line and forwards it; it returns false once the listener is closing
+func (listener *Listener) handleLine(sourceName string, rawLine string) bool {
+	line := strings.TrimSpace(rawLine)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return true
+	}
+
+	listener.mutex.Lock()
+	listener.sequences[sourceName]++
+	sequence := listener.sequences[sourceName]
+	listener.mutex.Unlock()
+
+	event, err := domain.ParseEventFromString(line)
+	if err != nil {
+		listener.mutex.Lock()
+		listener.malformed++
+		listener.mutex.Unlock()
+		listener.logger.Warn(fmt.Sprintf("malformed event #%d from %s ('%s'): %v", sequence, sourceName, line, err),
+			"source", sourceName, "sequence", sequence)
+		return true
+	}
+
+	listener.logger.Info(fmt.Sprintf("received event #%d from %s: %s", sequence, sourceName, line),
+		processing.PrefixKey, "Info: ", "source", sourceName, "sequence", sequence)
+
+	select {
+	case listener.events <- event:
+		return true
+	case <-listener.closing:
+		return false
+	}
+}
+
+// isClosing reports whether Close has been called
+func (listener *Listener) isClosing() bool {
+	select {
+	case <-listener.closing:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,99 @@
+package ingest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"biathlonPrototype/internal/processing"
+)
+
+func TestListenerLogsReceivedAndMalformedLines(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	listener := NewListener("127.0.0.1:0", "", processing.NewConsoleLogger(&stdout, &stderr))
+	if err := listener.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	connection, err := net.Dial("tcp", listener.tcpListener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = fmt.Fprint(connection, "[09:00:00.000] 1 1\nnot an event\n[09:00:01.000] 1 2\n"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if event := <-listener.Events(); event.CompetitorID != i+1 {
+			t.Errorf("event %d is for competitor %d", i, event.CompetitorID)
+		}
+	}
+	_ = connection.Close()
+	if err = listener.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := listener.MalformedCount(); got != 1 {
+		t.Errorf("malformed = %d, want 1", got)
+	}
+	lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), stdout.String())
+	}
+	for i, prefix := range []string{"Info: received event #1 from tcp://", "Warning: malformed event #2 from tcp://", "Info: received event #3 from tcp://"} {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Errorf("line %d = %q, want prefix %q", i+1, lines[i], prefix)
+		}
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("stderr = %q, want empty", stderr.String())
+	}
+}
+
+// closingListener is a net.Listener whose Accept hands out the server side of an idle connection once the listener
+// is closing, and then fails like a closed listener
+type closingListener struct {
+	net.Listener
+	closing <-chan struct{}
+	server  net.Conn
+	done    chan struct{}
+}
+
+func (fake *closingListener) Accept() (net.Conn, error) {
+	<-fake.closing
+	select {
+	case <-fake.done:
+		return nil, net.ErrClosed
+	default:
+		close(fake.done)
+		return fake.server, nil
+	}
+}
+
+func (fake *closingListener) Close() error { return nil }
+
+func TestCloseDoesNotWaitForAConnectionAcceptedWhileClosing(t *testing.T) {
+	listener := NewListener("127.0.0.1:0", "", processing.NewConsoleLogger(io.Discard, io.Discard))
+	server, client := net.Pipe()
+	defer client.Close()
+	listener.tcpListener = &closingListener{closing: listener.closing, server: server, done: make(chan struct{})}
+	listener.waitGroup.Add(1)
+	go listener.acceptTCP()
+
+	closed := make(chan error)
+	go func() { closed <- listener.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close is still waiting for the connection accepted while closing")
+	}
+	if _, err := client.Write([]byte("[09:00:00.000] 1 1\n")); err == nil {
+		t.Error("the connection accepted while closing is still open")
+	}
+}
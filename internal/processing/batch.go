@@ -0,0 +1,84 @@
+package processing
+
+import (
+	"fmt"
+	"slices"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// BatchError reports the first event of a batch that could not be applied
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+// Error returns a description including the index of the failed event
+func (batchError *BatchError) Error() string {
+	return fmt.Sprintf("batch event %d: %v", batchError.Index, batchError.Err)
+}
+
+// Unwrap returns the cause of the failure
+func (batchError *BatchError) Unwrap() error {
+	return batchError.Err
+}
+
+// Clone returns a copy of the simulator whose state can be changed without affecting the original
+func (simulator *Simulator) Clone() *Simulator {
+	clone := *simulator
+
+	clone.Competitors = make(map[int]*domain.Competitor, len(simulator.Competitors))
+	for id, competitor := range simulator.Competitors {
+		clone.Competitors[id] = competitor.Clone()
+	}
+	clone.Events = slices.Clone(simulator.Events)
+	clone.OutputLog = slices.Clone(simulator.OutputLog)
+	clone.outputTimes = slices.Clone(simulator.outputTimes)
+	clone.StationConflicts = slices.Clone(simulator.StationConflicts)
+	clone.OnlyCompetitors = slices.Clone(simulator.OnlyCompetitors)
+	clone.warnings = slices.Clone(simulator.warnings)
+	clone.stats = simulator.Stats()
+	clone.rebuildEventIndex()
+	clone.batching, clone.pending = false, nil
+
+	return &clone
+}
+
+// ProcessEvents applies a batch of events either completely or not at all. The callbacks and logged warnings of the
+// batch are held back until it has been applied, so nothing is reported for a batch that fails
+func (simulator *Simulator) ProcessEvents(events []*domain.Event) error {
+	previousTimestamp := simulator.CurrentTime
+	for i, event := range events {
+		if !previousTimestamp.IsZero() && event.Timestamp.Before(previousTimestamp) {
+			return &BatchError{Index: i, Err: fmt.Errorf("time order of events is broken: %s before %s",
+				domain.FormatTime(event.Timestamp), domain.FormatTime(previousTimestamp))}
+		}
+		previousTimestamp = event.Timestamp
+	}
+
+	work := simulator.Clone()
+	work.batching = true
+	for i, event := range events {
+		if err := work.ProcessEvent(event); err != nil {
+			return &BatchError{Index: i, Err: err}
+		}
+	}
+
+	pending := work.pending
+	work.batching, work.pending = false, nil
+	*simulator = *work
+	for _, output := range pending {
+		output(simulator)
+	}
+	return nil
+}
+
+// whenApplied runs output, a callback or logged warning, right away or, while a batch is processed on a clone, once
+// the batch has been applied to the simulator
+func (simulator *Simulator) whenApplied(output func(*Simulator)) {
+	if simulator.batching {
+		simulator.pending = append(simulator.pending, output)
+		return
+	}
+	output(simulator)
+}
@@ -0,0 +1,64 @@
+package processing
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"biathlonPrototype/internal/domain"
+)
+
+func TestProcessEventsFailedBatchReportsNothing(t *testing.T) {
+	handler := &captureHandler{}
+	var changes int
+	simulator := NewSimulator(loadConfig(t, testConfig), WithLogger(slog.New(handler)), WithCallbacks(Callbacks{
+		OnStatusChange: func(*domain.Competitor, domain.CompetitorStatus, domain.CompetitorStatus, *domain.Event) { changes++ },
+	}))
+	mustProcess(t, simulator, "[09:00:00.000] 1 1")
+
+	err := simulator.ProcessEvents(parseEvents(t, `
+		[09:01:00.000] 1 1
+		[09:02:00.000] 3 1
+		[09:03:00.000] 4 2`))
+	var batchError *BatchError
+	if !errors.As(err, &batchError) || batchError.Index != 2 {
+		t.Fatalf("err = %v, want a BatchError at index 2", err)
+	}
+	if changes != 0 {
+		t.Errorf("%d status changes reported for a failed batch", changes)
+	}
+	if len(handler.records) != 0 {
+		t.Errorf("%d warnings logged for a failed batch", len(handler.records))
+	}
+	if len(simulator.Warnings()) != 0 || simulator.Competitors[1].Status != domain.StatusRegistered {
+		t.Errorf("failed batch changed the simulator: %d warnings, status %s", len(simulator.Warnings()), simulator.Competitors[1].Status)
+	}
+}
+
+func TestProcessEventsReportsAfterTheBatchIsApplied(t *testing.T) {
+	handler := &captureHandler{}
+	var simulator *Simulator
+	var eventsSeen []int
+	simulator = NewSimulator(loadConfig(t, testConfig), WithLogger(slog.New(handler)), WithCallbacks(Callbacks{
+		OnStatusChange: func(*domain.Competitor, domain.CompetitorStatus, domain.CompetitorStatus, *domain.Event) {
+			eventsSeen = append(eventsSeen, len(simulator.Events))
+			if len(handler.records) != 1 {
+				t.Errorf("callback ran before the warning of the batch was logged")
+			}
+		},
+	}))
+	mustProcess(t, simulator, "[09:00:00.000] 1 1")
+
+	err := simulator.ProcessEvents(parseEvents(t, `
+		[09:01:00.000] 1 1
+		[09:02:00.000] 3 1`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eventsSeen) != 1 || eventsSeen[0] != 3 {
+		t.Errorf("callbacks saw %v events, want one callback after all 3 events were applied", eventsSeen)
+	}
+	if len(handler.records) != 1 || len(simulator.Warnings()) != 1 {
+		t.Errorf("got %d logged and %d recorded warnings, want 1 each", len(handler.records), len(simulator.Warnings()))
+	}
+}
@@ -0,0 +1,88 @@
+package processing
+
+import (
+	"slices"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// Callbacks are invoked synchronously while events are processed. They receive copies of the competitor and the
+// triggering event, so they cannot change the simulator state; a panic inside a callback is recorded as a warning.
+// For finishes and disqualifications the triggering event is the incoming event that caused them, or the generated
+// outgoing event when FinishCompetitor, DisqualifyCompetitor or WithdrawCompetitor is called directly
+type Callbacks struct {
+	// OnFinish is called when a competitor finishes
+	OnFinish func(competitor *domain.Competitor, event *domain.Event)
+	// OnDisqualify is called when a competitor is disqualified, misses the start or withdraws; the status tells which
+	OnDisqualify func(competitor *domain.Competitor, event *domain.Event)
+	// OnLapComplete is called when a competitor completes a main lap (numbered from 1)
+	OnLapComplete func(competitor *domain.Competitor, lap int, event *domain.Event)
+	// OnStatusChange is called whenever the status of a competitor changes
+	OnStatusChange func(competitor *domain.Competitor, oldStatus, newStatus domain.CompetitorStatus, event *domain.Event)
+}
+
+// WithCallbacks sets the lifecycle callbacks of the simulator
+func WithCallbacks(callbacks Callbacks) Option {
+	return func(simulator *Simulator) {
+		simulator.Callbacks = callbacks
+	}
+}
+
+// competitorProgress is the part of the competitor state compared to detect lifecycle changes
+type competitorProgress struct {
+	status        domain.CompetitorStatus
+	lapsCompleted int
+}
+
+// progressOf returns the current progress of a competitor
+func progressOf(competitor *domain.Competitor) competitorProgress {
+	return competitorProgress{status: competitor.Status, lapsCompleted: len(competitor.LapDetails)}
+}
+
+// notifyChanges invokes the callbacks for the changes of a competitor since the given progress
+func (simulator *Simulator) notifyChanges(competitor *domain.Competitor, before competitorProgress, event *domain.Event) {
+	after := progressOf(competitor)
+	callbacks := simulator.Callbacks
+
+	if callbacks.OnLapComplete != nil {
+		for lap := before.lapsCompleted + 1; lap <= after.lapsCompleted; lap++ {
+			simulator.invokeCallback("OnLapComplete", competitor, event, func(competitor *domain.Competitor, event *domain.Event) {
+				callbacks.OnLapComplete(competitor, lap, event)
+			})
+		}
+	}
+	if after.status == before.status {
+		return
+	}
+	simulator.countStatusChange(before.status, after.status)
+	if callbacks.OnStatusChange != nil {
+		simulator.invokeCallback("OnStatusChange", competitor, event, func(competitor *domain.Competitor, event *domain.Event) {
+			callbacks.OnStatusChange(competitor, before.status, after.status, event)
+		})
+	}
+	switch after.status {
+	case domain.StatusFinished:
+		if callbacks.OnFinish != nil {
+			simulator.invokeCallback("OnFinish", competitor, event, callbacks.OnFinish)
+		}
+	case domain.StatusDisqualified, domain.StatusNotStarted:
+		if callbacks.OnDisqualify != nil {
+			simulator.invokeCallback("OnDisqualify", competitor, event, callbacks.OnDisqualify)
+		}
+	}
+}
+
+// invokeCallback calls a callback with copies of the competitor and the event and turns a panic into a warning
+func (simulator *Simulator) invokeCallback(name string, competitor *domain.Competitor, event *domain.Event, callback func(*domain.Competitor, *domain.Event)) {
+	competitorCopy, eventCopy := competitor.Clone(), *event
+	eventCopy.ExtraParameters = slices.Clone(event.ExtraParameters)
+
+	simulator.whenApplied(func(simulator *Simulator) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				simulator.warn(WarningCallbackPanic, event, competitor.ID, "%s callback for competitor %d panicked: %v", name, competitor.ID, recovered)
+			}
+		}()
+		callback(competitorCopy, &eventCopy)
+	})
+}
@@ -0,0 +1,53 @@
+package processing
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// checkpoint is the persisted simulator state together with the position in the event file
+type checkpoint struct {
+	Position FilePosition `json:"position"`
+	snapshot
+}
+
+// WriteCheckpoint saves the simulator state and the event file position to a JSON file
+func (simulator *Simulator) WriteCheckpoint(filePath string, position FilePosition) error {
+	data, err := marshalSnapshot(checkpoint{Position: position, snapshot: simulator.snapshot()})
+	if err != nil {
+		return fmt.Errorf("error encoding checkpoint: %w", err)
+	}
+
+	dir := filepath.Dir(filePath)
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	temporaryPath := filePath + ".tmp"
+	if err = os.WriteFile(temporaryPath, data, 0644); err != nil {
+		return fmt.Errorf("error writing checkpoint %s: %w", temporaryPath, err)
+	}
+	if err = os.Rename(temporaryPath, filePath); err != nil {
+		return fmt.Errorf("error replacing checkpoint %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// LoadCheckpoint restores the simulator state from a checkpoint file and returns the event file position to resume from
+func (simulator *Simulator) LoadCheckpoint(filePath string) (FilePosition, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return FilePosition{}, fmt.Errorf("error reading checkpoint %s: %w", filePath, err)
+	}
+
+	var saved checkpoint
+	if err = unmarshalSnapshot(data, &saved); err != nil {
+		return FilePosition{}, fmt.Errorf("error parsing checkpoint %s: %w", filePath, err)
+	}
+
+	if err = simulator.restore(saved.snapshot); err != nil {
+		return FilePosition{}, fmt.Errorf("error restoring checkpoint %s: %w", filePath, err)
+	}
+	return saved.Position, nil
+}
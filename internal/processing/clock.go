@@ -0,0 +1,39 @@
+package processing
+
+import "time"
+
+// Clock tells the simulator the current race time for deadline checks
+type Clock interface {
+	Now() time.Time
+}
+
+// WallClock reads the time of day from the system clock, for live feeds where deadlines pass between events
+type WallClock struct{}
+
+// Now returns the current time of day in the representation of parsed event timestamps
+func (WallClock) Now() time.Time {
+	now := time.Now()
+	return time.Date(0, time.January, 1, now.Hour(), now.Minute(), now.Second(), now.Nanosecond(), time.UTC)
+}
+
+// WithClock sets the clock used for deadline checks; by default the time of the last processed event is used
+func WithClock(clock Clock) Option {
+	return func(simulator *Simulator) {
+		simulator.Clock = clock
+	}
+}
+
+// now returns the current race time from the clock, or the time of the last processed event without one
+func (simulator *Simulator) now() time.Time {
+	if simulator.Clock == nil {
+		return simulator.CurrentTime
+	}
+	return simulator.Clock.Now()
+}
+
+// CheckDeadlines applies the deadlines that have passed by the current time of the clock, e.g. missed starts and
+// the course cutoff
+func (simulator *Simulator) CheckDeadlines() {
+	simulator.CheckForNotStarted()
+	simulator.applyCutoff(simulator.now())
+}
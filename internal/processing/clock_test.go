@@ -0,0 +1,126 @@
+package processing
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// fakeClock is a clock whose time only moves when the test sets it
+type fakeClock struct {
+	now time.Time
+}
+
+func (clock *fakeClock) Now() time.Time {
+	return clock.now
+}
+
+// at parses a time of day for the fake clock
+func at(t *testing.T, clock string) time.Time {
+	t.Helper()
+	parsed, err := domain.ParseTimeFromString("[" + clock + "]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+// registered is competitor 1 with the scheduled start 10:00:00.000, so the start deadline is 10:01:30.000
+const registered = `
+	[09:00:00.000] 1 1
+	[09:00:01.000] 2 1 10:00:00.000`
+
+func TestFakeClockDeadlineDeclaresNotStarted(t *testing.T) {
+	clock := &fakeClock{}
+	simulator := newTestSimulator(t, WithClock(clock))
+	mustProcess(t, simulator, registered)
+
+	clock.now = at(t, "10:01:30.000")
+	simulator.CheckDeadlines()
+	if status := simulator.Competitors[1].Status; status != domain.StatusRegistered {
+		t.Fatalf("status %s at the deadline, want Registered", status)
+	}
+
+	clock.now = at(t, "10:01:30.001")
+	simulator.CheckDeadlines()
+	competitor := simulator.Competitors[1]
+	if competitor.Status != domain.StatusNotStarted || !competitor.FinishTime.Equal(at(t, "10:01:30.000")) {
+		t.Fatalf("status %s at %s, want NotStarted at the deadline", competitor.Status, domain.FormatTime(competitor.FinishTime))
+	}
+	if last := simulator.OutputLog[len(simulator.OutputLog)-1]; !strings.HasPrefix(last, "[10:01:30.000]") {
+		t.Errorf("last output line %q, want the disqualification at the deadline", last)
+	}
+}
+
+func TestEventTimeIsTheDefaultClock(t *testing.T) {
+	simulator := newTestSimulator(t)
+	mustProcess(t, simulator, registered)
+	simulator.CheckDeadlines()
+	if status := simulator.Competitors[1].Status; status != domain.StatusRegistered {
+		t.Fatalf("status %s before any later event, want Registered", status)
+	}
+	mustProcess(t, simulator, "[10:02:00.000] 1 2")
+	simulator.CheckDeadlines()
+	if status := simulator.Competitors[1].Status; status != domain.StatusNotStarted {
+		t.Errorf("status %s after a later event, want NotStarted", status)
+	}
+}
+
+func TestFakeClockCutoff(t *testing.T) {
+	clock := &fakeClock{}
+	cfg := loadConfig(t, strings.Replace(testConfig, "{", `{"cutoff": "10:30:00.000",`, 1))
+	simulator := NewSimulator(cfg, WithLogger(quietLogger()), WithClock(clock))
+	mustProcess(t, simulator, registered+`
+		[09:59:00.000] 3 1
+		[10:00:00.000] 4 1`)
+
+	clock.now = at(t, "10:30:00.000")
+	simulator.CheckDeadlines()
+	if status := simulator.Competitors[1].Status; status != domain.StatusStarted {
+		t.Fatalf("status %s at the cutoff, want Started", status)
+	}
+	clock.now = at(t, "10:30:00.001")
+	simulator.CheckDeadlines()
+	if status := simulator.Competitors[1].Status; status != domain.StatusNotFinished {
+		t.Errorf("status %s after the cutoff, want NotFinished", status)
+	}
+}
+
+func TestChannelSourceTicksCheckDeadlinesBetweenEvents(t *testing.T) {
+	clock := &fakeClock{}
+	simulator := newTestSimulator(t, WithClock(clock))
+	events := make(chan *domain.Event)
+	ticks := make(chan time.Time)
+	statuses := make(chan domain.CompetitorStatus, 1)
+	source := NewChannelEventSource(events)
+	// The tick runs on the goroutine running the simulator, so the status can be read there
+	source.OnTick(ticks, func() {
+		simulator.CheckDeadlines()
+		statuses <- simulator.Competitors[1].Status
+	})
+
+	done := make(chan error)
+	go func() {
+		_, err := simulator.Run(source)
+		done <- err
+	}()
+	for _, event := range parseEvents(t, registered) {
+		events <- event
+	}
+	clock.now = at(t, "10:01:00.000")
+	ticks <- clock.now
+	if status := <-statuses; status != domain.StatusRegistered {
+		t.Errorf("status %s before the deadline, want Registered", status)
+	}
+	clock.now = at(t, "10:05:00.000")
+	ticks <- clock.now
+	if status := <-statuses; status != domain.StatusNotStarted {
+		t.Errorf("status %s after the deadline passed without events, want NotStarted", status)
+	}
+	close(events)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
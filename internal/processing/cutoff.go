@@ -0,0 +1,34 @@
+package processing
+
+import (
+	"sort"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// applyCutoff stops the competitors still on the course once the time passes the configured cutoff. They become
+// NotFinished at the cutoff itself, so the log stays in time order, and later events cannot stop them again
+func (simulator *Simulator) applyCutoff(at time.Time) {
+	cutoff := simulator.Config.ParsedCutoff
+	if cutoff.IsZero() || !at.After(cutoff) {
+		return
+	}
+
+	ids := make([]int, 0)
+	for id, competitor := range simulator.Competitors {
+		if competitor.Status == domain.StatusStarted || competitor.Status == domain.StatusFiring || competitor.Status == domain.StatusPenalized {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		competitor := simulator.Competitors[id]
+		before := progressOf(competitor)
+		simulator.warn(WarningCutoff, nil, competitor.ID, "competitor %d was still on the course on lap %d at the cutoff %s. Status: NotFinished.",
+			competitor.ID, competitor.CurrentLap, domain.FormatTime(cutoff))
+		notFinishedEvent := simulator.markNotFinished(competitor, cutoff, domain.NewReason(domain.ReasonCutoff, ""))
+		simulator.notifyChanges(competitor, before, notFinishedEvent)
+	}
+}
@@ -0,0 +1,69 @@
+package processing
+
+import (
+	"slices"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// NearDuplicateThreshold is the largest time difference at which otherwise identical events are reported as near-duplicates
+const NearDuplicateThreshold = 100 * time.Millisecond
+
+// duplicateFilter remembers recent events per competitor to detect duplicates
+type duplicateFilter struct {
+	history int
+	window  time.Duration
+	recent  map[int][]*domain.Event
+}
+
+// newDuplicateFilter creates a filter remembering up to history events and/or events within the window per competitor
+func newDuplicateFilter(history int, window time.Duration) *duplicateFilter {
+	return &duplicateFilter{
+		history: history,
+		window:  window,
+		recent:  make(map[int][]*domain.Event),
+	}
+}
+
+// enabled reports whether duplicate detection is configured
+func (filter *duplicateFilter) enabled() bool {
+	return filter.history > 0 || filter.window > 0
+}
+
+// check remembers the event and reports whether it is an exact duplicate, along with a near-duplicate if one was seen
+func (filter *duplicateFilter) check(event *domain.Event) (bool, *domain.Event) {
+	recent := filter.recent[event.CompetitorID]
+
+	if filter.window > 0 {
+		kept := recent[:0]
+		for _, previous := range recent {
+			if event.Timestamp.Sub(previous.Timestamp) <= filter.window {
+				kept = append(kept, previous)
+			}
+		}
+		recent = kept
+	}
+
+	var nearDuplicate *domain.Event
+	for _, previous := range recent {
+		if previous.ID != event.ID || !slices.Equal(previous.ExtraParameters, event.ExtraParameters) {
+			continue
+		}
+		difference := event.Timestamp.Sub(previous.Timestamp)
+		if difference == 0 {
+			filter.recent[event.CompetitorID] = recent
+			return true, nil
+		}
+		if difference.Abs() <= NearDuplicateThreshold {
+			nearDuplicate = previous
+		}
+	}
+
+	recent = append(recent, event)
+	if filter.history > 0 && len(recent) > filter.history {
+		recent = recent[len(recent)-filter.history:]
+	}
+	filter.recent[event.CompetitorID] = recent
+	return false, nearDuplicate
+}
@@ -0,0 +1,88 @@
+package processing
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"biathlonPrototype/internal/report"
+)
+
+// notFinishedRace lets four competitors complete the first lap and stop on the second: competitors 1 and 2 after
+// split points 1 and 2, competitor 3 after split point 2 later than competitor 2, and competitor 4 after two penalty
+// loops
+const notFinishedRace = `
+	[09:00:00.000] 1 1
+	[09:00:00.000] 1 2
+	[09:00:00.000] 1 3
+	[09:00:00.000] 1 4
+	[09:00:01.000] 2 1 10:00:00.000
+	[09:00:01.000] 2 2 10:01:30.000
+	[09:00:01.000] 2 3 10:03:00.000
+	[09:00:01.000] 2 4 10:04:30.000
+	[10:00:00.000] 4 1
+	[10:01:30.000] 4 2
+	[10:03:00.000] 4 3
+	[10:04:30.000] 4 4
+	[10:12:00.000] 10 1
+	[10:13:30.000] 10 2
+	[10:15:00.000] 10 3
+	[10:16:30.000] 10 4
+	[10:16:40.000] 14 1 1
+	[10:17:00.000] 11 1 fell
+	[10:18:00.000] 14 2 1
+	[10:20:00.000] 14 2 2
+	[10:20:30.000] 11 2 fell
+	[10:19:00.000] 14 3 1
+	[10:21:00.000] 14 3 2
+	[10:22:00.000] 11 3 fell
+	[10:21:00.000] 5 4 1
+	[10:21:10.000] 6 4 1
+	[10:21:20.000] 6 4 2
+	[10:21:30.000] 6 4 3
+	[10:21:40.000] 7 4
+	[10:21:50.000] 8 4
+	[10:22:50.000] 9 4
+	[10:23:00.000] 11 4 fell`
+
+func TestNotFinishedCompetitorsAreRankedByDistance(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       string
+		wantOrder []int
+		distances map[int]float64
+	}{
+		{"split points", strings.Replace(testConfig, `"laps": 2`, `"laps": 2, "splitPoints": [1200, 2400]`, 1),
+			[]int{3, 2, 1, 4}, map[int]float64{1: 4700, 2: 5900, 3: 5900, 4: 3800}},
+		{"no split points", testConfig,
+			[]int{4, 3, 2, 1}, map[int]float64{1: 3500, 2: 3500, 3: 3500, 4: 3800}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			simulator := NewSimulator(loadConfig(t, tt.cfg), WithLogger(quietLogger()))
+			mustProcess(t, simulator, notFinishedRace)
+
+			competitors := simulator.GetSortedCompetitors()
+			ids := make([]int, 0, len(competitors))
+			for _, competitor := range competitors {
+				ids = append(ids, competitor.ID)
+				if competitor.Distance != tt.distances[competitor.ID] {
+					t.Errorf("competitor %d covered %.0f m, want %.0f", competitor.ID, competitor.Distance, tt.distances[competitor.ID])
+				}
+			}
+			if !slices.Equal(ids, tt.wantOrder) {
+				t.Errorf("order %v, want %v", ids, tt.wantOrder)
+			}
+
+			details := report.GenerateDetails(competitors)
+			for id, distance := range tt.distances {
+				prefix, suffix := fmt.Sprintf("competitor(%d): ", id), fmt.Sprintf("}], distance %.0f", distance)
+				i := slices.IndexFunc(details, func(line string) bool { return strings.HasPrefix(line, prefix) })
+				if i < 0 || !strings.HasSuffix(details[i], suffix) {
+					t.Errorf("no detail line of competitor %d ending with %q in %q", id, suffix, details)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package processing
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+	"biathlonPrototype/internal/report"
+)
+
+// startAt registers competitor 1 with the scheduled start 10:00:00.000 and starts them at the given time
+func startAt(t *testing.T, simulator *Simulator, start string) *domain.Competitor {
+	t.Helper()
+	mustProcess(t, simulator, `
+		[09:00:00.000] 1 1
+		[09:00:01.000] 2 1 10:00:00.000
+		[09:59:00.000] 3 1
+		[`+start+`] 4 1`)
+	return simulator.Competitors[1]
+}
+
+func TestStartAtTheScheduledMillisecondIsNoFalseStart(t *testing.T) {
+	simulator := newTestSimulator(t)
+	competitor := startAt(t, simulator, "10:00:00.000")
+	if competitor.FalseStartMargin != 0 || slices.Contains(warningCodes(simulator), WarningFalseStart) {
+		t.Errorf("margin %v and warnings %v, want no false start", competitor.FalseStartMargin, warningCodes(simulator))
+	}
+	if competitor.Status != domain.StatusStarted {
+		t.Errorf("status %s, want Started", competitor.Status)
+	}
+	if lines := report.GenerateAnnotations([]*domain.Competitor{competitor}); len(lines) != 0 {
+		t.Errorf("annotations %q", lines)
+	}
+}
+
+func TestStartAFewMillisecondsEarlyIsAFalseStart(t *testing.T) {
+	simulator := newTestSimulator(t)
+	competitor := startAt(t, simulator, "09:59:59.997")
+	if competitor.FalseStartMargin != 3*time.Millisecond {
+		t.Errorf("margin %v, want 3ms", competitor.FalseStartMargin)
+	}
+	if !slices.Equal(warningCodes(simulator), []WarningCode{WarningFalseStart}) {
+		t.Errorf("warnings %v, want a false start", warningCodes(simulator))
+	}
+	if competitor.Status != domain.StatusStarted || competitor.TimePenalty != 0 {
+		t.Errorf("status %s and time penalty %v, want Started without a penalty", competitor.Status, competitor.TimePenalty)
+	}
+	annotations := strings.Join(report.GenerateAnnotations([]*domain.Competitor{competitor}), "\n")
+	if !strings.Contains(annotations, "[09:59:59.997] competitor(1): false start by 0.003 s") {
+		t.Errorf("annotations %q", annotations)
+	}
+}
+
+func TestFalseStartPolicies(t *testing.T) {
+	penalty := NewSimulator(loadConfig(t, strings.Replace(testConfig, "{", `{"falseStartPolicy": "penalty", "falseStartPenalty": "00:00:10",`, 1)),
+		WithLogger(quietLogger()))
+	if competitor := startAt(t, penalty, "09:59:59.999"); competitor.TimePenalty != 10*time.Second || competitor.Status != domain.StatusStarted {
+		t.Errorf("penalty policy: status %s and time penalty %v, want Started and 10s", competitor.Status, competitor.TimePenalty)
+	}
+
+	disqualify := NewSimulator(loadConfig(t, strings.Replace(testConfig, "{", `{"falseStartPolicy": "disqualify",`, 1)),
+		WithLogger(quietLogger()))
+	if competitor := startAt(t, disqualify, "09:59:59.999"); competitor.Status != domain.StatusDisqualified ||
+		competitor.DisqualificationReason.Code != domain.ReasonFalseStart {
+		t.Errorf("disqualify policy: status %s and reason %v, want Disqualified for a false start", competitor.Status, competitor.DisqualificationReason)
+	}
+	if competitor := startAt(t, NewSimulator(loadConfig(t, strings.Replace(testConfig, "{", `{"falseStartPolicy": "disqualify",`, 1)),
+		WithLogger(quietLogger())), "10:00:00.000"); competitor.Status != domain.StatusStarted {
+		t.Errorf("disqualify policy: start on time has status %s", competitor.Status)
+	}
+}
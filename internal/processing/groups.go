@@ -0,0 +1,74 @@
+package processing
+
+import (
+	"sort"
+
+	"biathlonPrototype/internal/config"
+	"biathlonPrototype/internal/domain"
+)
+
+// configFor returns the configuration of the competitor's race group
+func (simulator *Simulator) configFor(competitor *domain.Competitor) *config.Config {
+	if competitor.Group == "" {
+		return simulator.Config
+	}
+	return simulator.Config.ForGroup(competitor.Group)
+}
+
+// groupFor determines the race group of a registering competitor from the group token of the event or the
+// configured bib ranges, and warns when they disagree or the group is not configured
+func (simulator *Simulator) groupFor(event *domain.Event) string {
+	bibGroup := simulator.Config.GroupForBib(event.CompetitorID)
+	if event.Group == "" {
+		return bibGroup
+	}
+	if bibGroup != "" && bibGroup != event.Group {
+		simulator.warn(WarningGroupConflict, event, event.CompetitorID, "competitor %d registered for group '%s', but bib range belongs to group '%s'",
+			event.CompetitorID, event.Group, bibGroup)
+	} else if len(simulator.Config.Groups) > 0 && !simulator.Config.HasGroup(event.Group) {
+		simulator.warn(WarningGroupConflict, event, event.CompetitorID, "competitor %d registered for group '%s', which is not configured",
+			event.CompetitorID, event.Group)
+	}
+	return event.Group
+}
+
+// Groups returns the names of the race groups of all competitors in configuration order, followed by
+// unconfigured groups in alphabetical order and "" for competitors without a group
+func (simulator *Simulator) Groups() []string {
+	present := make(map[string]bool)
+	for _, competitor := range simulator.Competitors {
+		present[competitor.Group] = true
+	}
+
+	groups := make([]string, 0, len(present))
+	for _, group := range simulator.Config.Groups {
+		if present[group.Name] {
+			groups = append(groups, group.Name)
+			delete(present, group.Name)
+		}
+	}
+	_, ungrouped := present[""]
+	delete(present, "")
+	others := make([]string, 0, len(present))
+	for group := range present {
+		others = append(others, group)
+	}
+	sort.Strings(others)
+	groups = append(groups, others...)
+	if ungrouped {
+		groups = append(groups, "")
+	}
+	return groups
+}
+
+// GetSortedCompetitorsInGroup returns the classification of one race group in report order
+func (simulator *Simulator) GetSortedCompetitorsInGroup(group string) []*domain.Competitor {
+	sorted := simulator.GetSortedCompetitors()
+	inGroup := make([]*domain.Competitor, 0, len(sorted))
+	for _, competitor := range sorted {
+		if competitor.Group == group {
+			inGroup = append(inGroup, competitor)
+		}
+	}
+	return inGroup
+}
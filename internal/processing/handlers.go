@@ -0,0 +1,45 @@
+package processing
+
+import (
+	"fmt"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// EventHandler processes a custom event for a registered competitor
+type EventHandler func(simulator *Simulator, competitor *domain.Competitor, event *domain.Event) error
+
+// EventFormatter returns the output log line for a custom event
+type EventFormatter func(event *domain.Event) string
+
+// RegisterHandler registers a handler for a custom event ID; built-in IDs cannot be overridden
+func (simulator *Simulator) RegisterHandler(id domain.EventID, handler EventHandler) error {
+	if id.IsBuiltin() {
+		return fmt.Errorf("event ID %d is built-in and cannot be overridden", id)
+	}
+	if handler == nil {
+		return fmt.Errorf("nil handler for event ID %d", id)
+	}
+	simulator.handlers[id] = handler
+	return nil
+}
+
+// RegisterFormatter registers an output log formatter for a custom event ID
+func (simulator *Simulator) RegisterFormatter(id domain.EventID, formatter EventFormatter) error {
+	if id.IsBuiltin() {
+		return fmt.Errorf("event ID %d is built-in and its format cannot be overridden", id)
+	}
+	if formatter == nil {
+		return fmt.Errorf("nil formatter for event ID %d", id)
+	}
+	simulator.formatters[id] = formatter
+	return nil
+}
+
+// formatEvent returns the output log line for an event, using a registered formatter when available
+func (simulator *Simulator) formatEvent(event *domain.Event) string {
+	if formatter, ok := simulator.formatters[event.ID]; ok {
+		return formatter(event)
+	}
+	return event.String()
+}
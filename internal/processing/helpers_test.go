@@ -0,0 +1,114 @@
+package processing
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"biathlonPrototype/internal/config"
+	"biathlonPrototype/internal/domain"
+)
+
+// testConfig is the configuration of the example race: two laps with two firing lines, starts every 90 seconds
+const testConfig = `{
+  "laps": 2,
+  "lapLen": 3500,
+  "penaltyLen": 150,
+  "firingLines": 2,
+  "start": "10:00:00.000",
+  "startDelta": "00:01:30"
+}`
+
+// finishedRace registers competitor 1 and lets them ski both laps and shoot both ranges clean
+const finishedRace = `
+	[09:00:00.000] 1 1
+	[09:00:01.000] 2 1 10:00:00.000
+	[09:59:00.000] 3 1
+	[10:00:00.000] 4 1
+	[10:05:00.000] 5 1 1
+	[10:05:10.000] 6 1 1
+	[10:05:20.000] 6 1 2
+	[10:05:30.000] 6 1 3
+	[10:05:40.000] 6 1 4
+	[10:05:50.000] 6 1 5
+	[10:06:00.000] 7 1
+	[10:10:00.000] 10 1
+	[10:15:00.000] 5 1 2
+	[10:15:10.000] 6 1 1
+	[10:15:20.000] 6 1 2
+	[10:15:30.000] 6 1 3
+	[10:15:40.000] 6 1 4
+	[10:15:50.000] 6 1 5
+	[10:16:00.000] 7 1
+	[10:20:00.000] 10 1`
+
+// loadConfig loads a configuration through config.LoadConfiguration so that the parsed fields are set
+func loadConfig(t *testing.T, text string) *config.Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfiguration(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+// quietLogger discards all output of a simulator
+func quietLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// newTestSimulator creates a simulator for the example configuration that logs nothing
+func newTestSimulator(t *testing.T, options ...Option) *Simulator {
+	t.Helper()
+	return NewSimulator(loadConfig(t, testConfig), append([]Option{WithLogger(quietLogger())}, options...)...)
+}
+
+// parseEvents parses event lines, one per line of the text
+func parseEvents(t *testing.T, text string) []*domain.Event {
+	t.Helper()
+	var events []*domain.Event
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		event, err := domain.ParseEventFromString(strings.TrimSpace(line))
+		if err != nil {
+			t.Fatalf("parsing %q: %v", line, err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// mustProcess processes event lines and fails the test on the first error
+func mustProcess(t *testing.T, simulator *Simulator, text string) {
+	t.Helper()
+	for _, event := range parseEvents(t, text) {
+		if err := simulator.ProcessEvent(event); err != nil {
+			t.Fatalf("processing %q: %v", event.MarshalLine(), err)
+		}
+	}
+}
+
+// readExample reads the events of the example race
+func readExample(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", "events.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// warningCodes returns the codes of the recorded warnings in order
+func warningCodes(simulator *Simulator) []WarningCode {
+	codes := make([]WarningCode, 0, len(simulator.Warnings()))
+	for _, warning := range simulator.Warnings() {
+		codes = append(codes, warning.Code)
+	}
+	return codes
+}
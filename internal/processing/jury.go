@@ -0,0 +1,24 @@
+package processing
+
+import (
+	"strings"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// applyJuryDecision attaches the rule reference of a jury decision to the disqualification of a competitor.
+// A decision replacing an earlier rule reference is reported as a warning
+func (simulator *Simulator) applyJuryDecision(competitor *domain.Competitor, event *domain.Event) {
+	if competitor.Status != domain.StatusDisqualified {
+		simulator.warn(WarningUnexpectedStatus, event, competitor.ID, "JuryDecision event (%d) for a competitor who is not disqualified (status %s). Ignored.",
+			competitor.ID, competitor.Status)
+		return
+	}
+
+	rule := strings.Join(event.ExtraParameters, " ")
+	if previous := competitor.Disqualification.Rule; previous != "" && previous != rule {
+		simulator.warn(WarningRuleOverridden, event, competitor.ID, "jury decision for competitor %d replaces rule reference '%s' with '%s'.",
+			competitor.ID, previous, rule)
+	}
+	competitor.Disqualification.Rule = rule
+}
@@ -0,0 +1,77 @@
+package processing
+
+import (
+	"sort"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// pullLappedCompetitors takes every competitor of the group of a competitor who just completed a lap off the course
+// if the leader is a full lap ahead of them, i.e. has completed at least two more laps. Laps are compared at equal
+// race time, so a competitor who started later is not lapped by an early starter; in pursuits and relays, where the
+// start gaps are part of the race, they are compared at the same time of day
+func (simulator *Simulator) pullLappedCompetitors(trigger *domain.Competitor, event *domain.Event) {
+	cfg := simulator.configFor(trigger)
+	byTimeOfDay := cfg.IsPursuit() || cfg.IsRelay()
+	group := make([]*domain.Competitor, 0)
+	for _, competitor := range simulator.Competitors {
+		if competitor.Group == trigger.Group && !competitor.ActualStartTime.IsZero() {
+			group = append(group, competitor)
+		}
+	}
+
+	ids := make([]int, 0)
+	for _, competitor := range group {
+		if competitor.Status != domain.StatusStarted && competitor.Status != domain.StatusFiring && competitor.Status != domain.StatusPenalized {
+			continue
+		}
+		elapsed := competitor.ElapsedTime(event.Timestamp)
+		for _, leader := range group {
+			if leader != competitor && lapsCompletedBy(leader, event.Timestamp, elapsed, byTimeOfDay) >= competitor.CurrentLap+1 {
+				ids = append(ids, competitor.ID)
+				break
+			}
+		}
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		competitor := simulator.Competitors[id]
+		before := progressOf(competitor)
+		simulator.lapCompetitor(competitor, event.Timestamp)
+		simulator.notifyChanges(competitor, before, event)
+	}
+}
+
+// lapsCompletedBy returns the number of laps a competitor had completed after the elapsed race time or, with
+// byTimeOfDay, at the given time
+func lapsCompletedBy(competitor *domain.Competitor, at time.Time, elapsed time.Duration, byTimeOfDay bool) int {
+	laps := 0
+	for _, lap := range competitor.LapDetails {
+		if lap.Duration <= 0 {
+			continue
+		}
+		if (byTimeOfDay && !lap.EndTime.After(at)) || (!byTimeOfDay && lap.Elapsed <= elapsed) {
+			laps++
+		}
+	}
+	return laps
+}
+
+// lapCompetitor pulls a lapped competitor from the course and records the generated Lapped event
+func (simulator *Simulator) lapCompetitor(competitor *domain.Competitor, at time.Time) *domain.Event {
+	competitor.SetStatus(domain.StatusLapped, at, domain.Lapped)
+	competitor.FinishTime = at
+	closeOpenSessions(competitor, at)
+	setAverageSpeed(competitor, simulator.configFor(competitor))
+
+	lappedEvent := &domain.Event{
+		Timestamp:    at,
+		ID:           domain.Lapped,
+		CompetitorID: competitor.ID,
+		IsIncoming:   false,
+	}
+	simulator.recordGenerated(lappedEvent)
+	return lappedEvent
+}
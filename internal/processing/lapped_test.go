@@ -0,0 +1,39 @@
+package processing
+
+import (
+	"strings"
+	"testing"
+
+	"biathlonPrototype/internal/domain"
+)
+
+func TestPullLappedComparesEqualRaceTime(t *testing.T) {
+	simulator := NewSimulator(loadConfig(t, strings.Replace(testConfig, `"laps": 2`, `"laps": 3, "pullLapped": true`, 1)),
+		WithLogger(quietLogger()))
+	mustProcess(t, simulator, `
+		[09:00:00.000] 1 1
+		[09:00:00.000] 1 2
+		[09:00:00.000] 1 3
+		[09:00:01.000] 2 1 10:00:00.000
+		[09:00:01.000] 2 2 10:00:30.000
+		[09:00:01.000] 2 3 10:21:00.000
+		[10:00:00.000] 4 1
+		[10:00:30.000] 4 2
+		[10:10:00.000] 10 1
+		[10:20:00.000] 10 1`)
+
+	if status := simulator.Competitors[2].Status; status != domain.StatusStarted {
+		t.Fatalf("competitor 2 is %s one lap behind the leader, want Started", status)
+	}
+
+	mustProcess(t, simulator, `
+		[10:21:00.000] 4 3
+		[10:30:00.000] 10 1`)
+
+	if status := simulator.Competitors[2].Status; status != domain.StatusLapped {
+		t.Errorf("competitor 2 is %s two laps behind the leader at equal race time, want Lapped", status)
+	}
+	if status := simulator.Competitors[3].Status; status != domain.StatusStarted {
+		t.Errorf("competitor 3, who started after the leader's second lap, is %s, want Started", status)
+	}
+}
@@ -0,0 +1,95 @@
+package processing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// Option configures a simulator created by NewSimulator
+type Option func(*Simulator)
+
+// WithLogger routes the informational and warning output of the simulator through the logger
+func WithLogger(logger *slog.Logger) Option {
+	return func(simulator *Simulator) {
+		if logger != nil {
+			simulator.logger = logger
+		}
+	}
+}
+
+// Attributes of a record that control how the console logger prints it
+const (
+	// PrefixKey is the string printed before the message; warnings without it are printed as "Warning: ..."
+	PrefixKey = "prefix"
+	// NewlineKey tells whether a line break follows the message; it does unless the attribute is false
+	NewlineKey = "newline"
+)
+
+// NewConsoleLogger returns the default logger, which prints each message after its PrefixKey attribute ("Warning: "
+// for warnings without one), errors going to stderr and everything else to stdout; other attributes are not printed
+func NewConsoleLogger(stdout, stderr io.Writer) *slog.Logger {
+	return slog.New(&consoleHandler{stdout: stdout, stderr: stderr, mu: &sync.Mutex{}})
+}
+
+// defaultLogger returns the console logger writing to the process stdout and stderr
+func defaultLogger() *slog.Logger {
+	return NewConsoleLogger(os.Stdout, os.Stderr)
+}
+
+// consoleHandler is the slog handler behind NewConsoleLogger
+type consoleHandler struct {
+	stdout io.Writer
+	stderr io.Writer
+	mu     *sync.Mutex
+}
+
+// Enabled reports whether records of the level are printed
+func (handler *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelInfo
+}
+
+// Handle prints the message of a record
+func (handler *consoleHandler) Handle(_ context.Context, record slog.Record) error {
+	prefix, newline := "", "\n"
+	if record.Level >= slog.LevelWarn && record.Level < slog.LevelError {
+		prefix = "Warning: "
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		switch {
+		case attr.Key == PrefixKey:
+			prefix = attr.Value.String()
+		case attr.Key == NewlineKey && attr.Value.Kind() == slog.KindBool && !attr.Value.Bool():
+			newline = ""
+		}
+		return true
+	})
+
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+
+	output := handler.stdout
+	if record.Level >= slog.LevelError {
+		output = handler.stderr
+	}
+	_, err := fmt.Fprint(output, prefix, record.Message, newline)
+	return err
+}
+
+// WithAttrs returns the handler itself because attributes are not printed
+func (handler *consoleHandler) WithAttrs(_ []slog.Attr) slog.Handler {
+	return handler
+}
+
+// WithGroup returns the handler itself because attributes are not printed
+func (handler *consoleHandler) WithGroup(_ string) slog.Handler {
+	return handler
+}
+
+// Logger returns the logger the simulator writes its output to
+func (simulator *Simulator) Logger() *slog.Logger {
+	return simulator.logger
+}
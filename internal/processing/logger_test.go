@@ -0,0 +1,127 @@
+package processing
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+// captureHandler records every log record it handles
+type captureHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (handler *captureHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (handler *captureHandler) Handle(_ context.Context, record slog.Record) error {
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	handler.records = append(handler.records, record.Clone())
+	return nil
+}
+
+func (handler *captureHandler) WithAttrs([]slog.Attr) slog.Handler { return handler }
+
+func (handler *captureHandler) WithGroup(string) slog.Handler { return handler }
+
+// recordAttrs returns the attributes of a record by key
+func recordAttrs(record slog.Record) map[string]slog.Value {
+	attrs := make(map[string]slog.Value)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs[attr.Key] = attr.Value
+		return true
+	})
+	return attrs
+}
+
+func TestWarningsAreLoggedWithAttributes(t *testing.T) {
+	handler := &captureHandler{}
+	simulator := NewSimulator(loadConfig(t, testConfig), WithLogger(slog.New(handler)))
+	event := parseEvents(t, "[09:00:00.000] 1 1\n[09:01:00.000] 1 1")[1]
+	event.LineNumber = 2
+	mustProcess(t, simulator, "[09:00:00.000] 1 1")
+	if err := simulator.ProcessEvent(event); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(handler.records) != 1 {
+		t.Fatalf("got %d records, want 1", len(handler.records))
+	}
+	record := handler.records[0]
+	if record.Level != slog.LevelWarn {
+		t.Errorf("level = %s, want WARN", record.Level)
+	}
+	if want := simulator.Warnings()[0].Message; record.Message != want {
+		t.Errorf("message = %q, want %q", record.Message, want)
+	}
+	attrs := recordAttrs(record)
+	if got := attrs["code"].String(); got != string(WarningReRegistered) {
+		t.Errorf("code = %q, want %q", got, WarningReRegistered)
+	}
+	if got := attrs["competitor"].Int64(); got != 1 {
+		t.Errorf("competitor = %d, want 1", got)
+	}
+	if got := attrs["event_id"].Int64(); got != 1 {
+		t.Errorf("event_id = %d, want 1", got)
+	}
+	if got := attrs["line"].Int64(); got != 2 {
+		t.Errorf("line = %d, want 2", got)
+	}
+}
+
+func TestPrintWarningsOffLogsNothing(t *testing.T) {
+	handler := &captureHandler{}
+	simulator := NewSimulator(loadConfig(t, testConfig), WithLogger(slog.New(handler)))
+	simulator.PrintWarnings = false
+	mustProcess(t, simulator, "[09:00:00.000] 1 1\n[09:01:00.000] 1 1")
+
+	if len(handler.records) != 0 {
+		t.Errorf("got %d records, want none", len(handler.records))
+	}
+	if len(simulator.Warnings()) != 1 {
+		t.Errorf("got %d warnings, want 1", len(simulator.Warnings()))
+	}
+}
+
+func TestConsoleLoggerKeepsOriginalPrefixes(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	simulator := NewSimulator(loadConfig(t, testConfig), WithLogger(NewConsoleLogger(&stdout, &stderr)))
+	mustProcess(t, simulator, `
+		[09:00:00.000] 1 1
+		[09:00:00.000] 1 2
+		[09:00:01.000] 2 1 10:00:00.000
+		[09:00:01.000] 2 2 10:01:30.000
+		[09:59:00.000] 3 1
+		[10:00:01.000] 4 1
+		[10:00:02.000] 3 1
+		[10:10:00.000] 10 1
+		[10:20:00.000] 10 1`)
+	simulator.CheckForNotStarted()
+
+	want := "Warning: OnStartLine event (1) in unexpected status Started" +
+		"Warning/Error: competitor 1 (ID 1) is finishing but Not all 2 firing ranges completed (completed 0, missed 1, 2).\n" +
+		"Info: competitor 2 (ID 2) did not start by [10:20:00.000] (deadline [10:03:00.000]). Status: NotStarted.\n"
+	if got := stdout.String(); got != want {
+		t.Errorf("stdout =\n%q\nwant\n%q", got, want)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("stderr = %q, want empty", stderr.String())
+	}
+}
+
+func TestConsoleLoggerWritesErrorsToStderr(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	logger := NewConsoleLogger(&stdout, &stderr)
+	logger.Error("closing failed", "file", "events.log")
+	logger.Info("plain")
+
+	if got := stderr.String(); got != "closing failed\n" {
+		t.Errorf("stderr = %q", got)
+	}
+	if got := stdout.String(); got != "plain\n" {
+		t.Errorf("stdout = %q", got)
+	}
+}
@@ -0,0 +1,51 @@
+package processing
+
+import (
+	"sort"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// ignoredStartTimeNote marks the output line of a SetStartTime event, which is ignored in a mass start
+const ignoredStartTimeNote = " (ignored in a mass start)"
+
+// seedMassStart gives a registering competitor of a mass start the common start time
+func (simulator *Simulator) seedMassStart(competitor *domain.Competitor) {
+	cfg := simulator.configFor(competitor)
+	if !cfg.IsMassStart() {
+		return
+	}
+	competitor.ScheduledStartTime = cfg.ParsedStart
+	competitor.RaceStartTime = cfg.ParsedStart
+}
+
+// startMass starts every registered competitor of the same mass start together with the competitor of the Started
+// event, whether or not they reported on the start line
+func (simulator *Simulator) startMass(event *domain.Event) {
+	starter := simulator.Competitors[event.CompetitorID]
+	ids := make([]int, 0)
+	for id, competitor := range simulator.Competitors {
+		waiting := competitor.Status == domain.StatusRegistered || competitor.Status == domain.StatusReadyToStart
+		if competitor != starter && waiting && competitor.Group == starter.Group {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		competitor := simulator.Competitors[id]
+		before := progressOf(competitor)
+		startCompetitor(competitor, simulator.configFor(competitor), event.Timestamp)
+		competitor.LastEventTime = event.Timestamp
+		startEvent := &domain.Event{Timestamp: event.Timestamp, ID: domain.Started, CompetitorID: id}
+		simulator.appendOutput(startEvent.String(), startEvent.Timestamp)
+		simulator.notifyChanges(competitor, before, event)
+	}
+}
+
+// startedByMassStart reports whether a Started event only confirms the start of a competitor who was already
+// started together with the others
+func (simulator *Simulator) startedByMassStart(competitor *domain.Competitor) bool {
+	return simulator.configFor(competitor).IsMassStart() && competitor.Status != domain.StatusRegistered &&
+		competitor.Status != domain.StatusReadyToStart && !competitor.ActualStartTime.IsZero()
+}
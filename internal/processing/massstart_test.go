@@ -0,0 +1,40 @@
+package processing
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"biathlonPrototype/internal/domain"
+)
+
+func TestMassStartStartsEveryRegisteredCompetitor(t *testing.T) {
+	simulator := NewSimulator(loadConfig(t, strings.Replace(testConfig, `"laps": 2`, `"laps": 2, "format": "mass-start"`, 1)),
+		WithLogger(quietLogger()))
+	mustProcess(t, simulator, `
+		[09:00:00.000] 1 1
+		[09:00:00.000] 1 2
+		[09:00:00.000] 1 3
+		[09:00:01.000] 2 2 10:05:00.000
+		[09:59:00.000] 3 1
+		[09:59:30.000] 3 3
+		[10:00:00.000] 4 1`)
+
+	for id := 1; id <= 3; id++ {
+		competitor := simulator.Competitors[id]
+		if competitor.Status != domain.StatusStarted || !competitor.ActualStartTime.Equal(competitor.ScheduledStartTime) {
+			t.Errorf("competitor %d is %s, started at %v and scheduled at %v, want Started at the common start",
+				id, competitor.Status, competitor.ActualStartTime, competitor.ScheduledStartTime)
+		}
+	}
+	if !slices.Contains(warningCodes(simulator), WarningIgnoredStartTime) {
+		t.Errorf("warnings %v, want the ignored start time", warningCodes(simulator))
+	}
+	ignored := "[09:00:01.000] The start time for the competitor(2) was set by a draw to 10:05:00.000" + ignoredStartTimeNote
+	if !slices.Contains(simulator.OutputLog, ignored) {
+		t.Errorf("output log has no line %q:\n%s", ignored, strings.Join(simulator.OutputLog, "\n"))
+	}
+	if started := strings.Count(strings.Join(simulator.OutputLog, "\n"), "has started"); started != 3 {
+		t.Errorf("%d start lines in the output log, want 3:\n%s", started, strings.Join(simulator.OutputLog, "\n"))
+	}
+}
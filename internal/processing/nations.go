@@ -0,0 +1,38 @@
+package processing
+
+import "biathlonPrototype/internal/domain"
+
+// setAffiliation applies the nation=, team= and category= tokens of a Register or SetStartTime event, which take
+// precedence over the nations, teams and categories of the configuration
+func (simulator *Simulator) setAffiliation(competitor *domain.Competitor, event *domain.Event) {
+	if event.Team != "" {
+		competitor.Team = event.Team
+	}
+	if event.Category != "" {
+		competitor.Category = event.Category
+	}
+	if event.Nation != "" {
+		simulator.setNation(competitor, event.Nation, event)
+	}
+}
+
+// setNation sets the nation of a competitor, warning about codes that are not three letters
+func (simulator *Simulator) setNation(competitor *domain.Competitor, code string, event *domain.Event) {
+	if !isNationCode(code) {
+		simulator.warn(WarningInvalidNation, event, competitor.ID, "nation '%s' of competitor %d is not a three-letter code.", code, competitor.ID)
+	}
+	competitor.Nation = code
+}
+
+// isNationCode reports whether the code consists of exactly three letters, e.g. NOR
+func isNationCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, letter := range code {
+		if (letter < 'A' || letter > 'Z') && (letter < 'a' || letter > 'z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package processing
+
+import (
+	"slices"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// appendOutput adds a line for an event at the given time to OutputLog, before any later lines so that OutputLog
+// stays ordered by time even for events generated retroactively, e.g. a NotStarted at the start deadline
+func (simulator *Simulator) appendOutput(line string, at time.Time) {
+	index := len(simulator.OutputLog)
+	for index > 0 && index <= len(simulator.outputTimes) && !simulator.outputTimes[index-1].IsZero() &&
+		simulator.outputTimes[index-1].After(at) {
+		index--
+	}
+	simulator.OutputLog = slices.Insert(simulator.OutputLog, index, line)
+	simulator.outputTimes = slices.Insert(simulator.alignedOutputTimes(), index, at)
+}
+
+// appendUntimedOutput adds a line without its own time, e.g. a comment, to the end of OutputLog. It takes the time
+// of the preceding line so that it stays behind the event it follows
+func (simulator *Simulator) appendUntimedOutput(line string) {
+	times := simulator.alignedOutputTimes()
+	var at time.Time
+	if len(times) > 0 {
+		at = times[len(times)-1]
+	}
+	simulator.OutputLog = append(simulator.OutputLog, line)
+	simulator.outputTimes = append(times, at)
+}
+
+// alignedOutputTimes returns the times of the OutputLog lines, padded with zero times for lines restored from a
+// snapshot without times; lines with a zero time are never moved
+func (simulator *Simulator) alignedOutputTimes() []time.Time {
+	for len(simulator.outputTimes) < len(simulator.OutputLog) {
+		simulator.outputTimes = append(simulator.outputTimes, time.Time{})
+	}
+	return simulator.outputTimes[:len(simulator.OutputLog)]
+}
+
+// insertEvent adds an event to a slice ordered by timestamp, after the events with the same timestamp
+func insertEvent(events []*domain.Event, event *domain.Event) []*domain.Event {
+	index := len(events)
+	for index > 0 && events[index-1].Timestamp.After(event.Timestamp) {
+		index--
+	}
+	return slices.Insert(events, index, event)
+}
@@ -0,0 +1,83 @@
+package processing
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// checkOutputOrder fails the test if a line of the output log has an earlier timestamp than the line before it
+func checkOutputOrder(t *testing.T, outputLog []string) {
+	t.Helper()
+	var previous string
+	for i, line := range outputLog {
+		timestamp, _, found := strings.Cut(line, " ")
+		if !found {
+			t.Fatalf("line %d %q has no timestamp", i+1, line)
+		}
+		if _, err := domain.ParseTimeFromString(timestamp); err != nil {
+			t.Fatalf("line %d %q: %v", i+1, line, err)
+		}
+		// "[HH:MM:SS.sss]" compares in time order as a string
+		if timestamp < previous {
+			t.Errorf("line %d %q is earlier than the line before it (%s)", i+1, line, previous)
+		}
+		previous = timestamp
+	}
+}
+
+func TestOutputLogStaysInTimeOrderWithRetroactiveEvents(t *testing.T) {
+	// Competitor 6 never starts; the NotStarted at the deadline 10:09:00.000 is generated at the end of the input.
+	// Competitor 7 is still on the course when the input ends and is closed at their last event
+	race := readExample(t) + `
+		[09:30:00.000] 1 6
+		[09:30:00.000] 1 7
+		[09:30:01.000] 2 6 10:07:30.000
+		[09:30:01.000] 2 7 10:09:00.000
+		[10:08:30.000] 3 7
+		[10:09:00.000] 4 7
+		[10:12:00.000] 14 7 1`
+	simulator := newTestSimulator(t)
+	simulator.SortEvents = true
+	simulator.CloseUnfinished = true
+	if err := simulator.ProcessEventsFromReader(strings.NewReader(race)); err != nil {
+		t.Fatal(err)
+	}
+	if status := simulator.Competitors[6].Status; status != domain.StatusNotStarted {
+		t.Fatalf("competitor 6 has status %s, want NotStarted", status)
+	}
+	if status := simulator.Competitors[7].Status; status != domain.StatusNotFinished {
+		t.Fatalf("competitor 7 has status %s, want NotFinished", status)
+	}
+	notStarted := slices.IndexFunc(simulator.OutputLog, func(line string) bool {
+		return strings.HasPrefix(line, "[10:09:00.000]") && strings.Contains(line, "competitor(6)")
+	})
+	if notStarted < 0 || notStarted == len(simulator.OutputLog)-1 {
+		t.Errorf("NotStarted of competitor 6 at %d of %d lines, want it before the later events", notStarted, len(simulator.OutputLog))
+	}
+	checkOutputOrder(t, simulator.OutputLog)
+}
+
+func TestEventsStayInTimeOrderWithRetroactiveEvents(t *testing.T) {
+	simulator := newTestSimulator(t)
+	mustProcess(t, simulator, `
+		[09:00:00.000] 1 1
+		[09:00:00.000] 1 2
+		[09:00:01.000] 2 1 10:00:00.000
+		[09:00:01.000] 2 2 10:01:30.000
+		[10:01:00.000] 3 2
+		[10:01:30.000] 4 2
+		[10:15:00.000] 5 2 1`)
+	simulator.CheckForNotStarted()
+	checkOutputOrder(t, simulator.OutputLog)
+	for i := 1; i < len(simulator.Events); i++ {
+		if simulator.Events[i].Timestamp.Before(simulator.Events[i-1].Timestamp) {
+			t.Errorf("event %q follows the later %q", simulator.Events[i].MarshalLine(), simulator.Events[i-1].MarshalLine())
+		}
+	}
+	if !slices.ContainsFunc(simulator.Events, func(event *domain.Event) bool { return event.ID == domain.Disqualified && event.CompetitorID == 1 }) {
+		t.Error("no generated event for competitor 1")
+	}
+}
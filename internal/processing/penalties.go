@@ -0,0 +1,128 @@
+package processing
+
+import (
+	"time"
+
+	"biathlonPrototype/internal/config"
+	"biathlonPrototype/internal/domain"
+)
+
+// MaxRangeTransition is the longest plausible time between leaving the firing range and entering the penalty loops
+const MaxRangeTransition = time.Minute
+
+// setPenaltyDetails computes the total penalty time and average penalty speed of a competitor who reached a final status;
+// time spent in a session closed by CannotContinue counts in the total time but not in the speed, which only covers
+// the completed sessions
+func setPenaltyDetails(competitor *domain.Competitor, cfg *config.Config) {
+	if competitor.TotalPenaltyTime > 0 && cfg.PenaltyLen > 0 {
+		competitor.PenaltyDetails = domain.PenaltyDetail{
+			TotalDuration: competitor.TotalPenaltyTime,
+			AverageSpeed:  domain.AggregatePenaltySessions(competitor.PenaltySessions, cfg.PenaltyLen).AverageSpeed,
+		}
+	}
+}
+
+// setAverageSpeed computes the average speed of a competitor who reached a final status over the distance covered
+// and records the distance, and for finishers the average speed over the full course
+func setAverageSpeed(competitor *domain.Competitor, cfg *config.Config) {
+	distance, atLapEnd, elapsed := competitor.CoveredDistance(cfg.LapLen, cfg.PenaltyLen, cfg.SplitPoints)
+	competitor.AverageSpeed = domain.CalculateSpeed(atLapEnd, elapsed)
+	competitor.Distance = distance
+	competitor.OverallSpeed, _ = competitor.CalculateOverallSpeed(cfg.TotalLaps(), cfg.LapLen, cfg.PenaltyLen)
+}
+
+// closeOpenSessions closes the lap, firing range and penalty loops a competitor is stopped in by a terminal event
+func closeOpenSessions(competitor *domain.Competitor, at time.Time) {
+	closeOpenLap(competitor, at)
+	closeOpenRangeEntry(competitor, at)
+	closeOpenPenaltySession(competitor, at)
+}
+
+// closeOpenLap records the time spent on the lap a competitor is stopped on
+func closeOpenLap(competitor *domain.Competitor, at time.Time) {
+	if competitor.CurrentLap < 1 || competitor.CurrentLap <= len(competitor.LapDetails) || competitor.CurrentLapStartTime.IsZero() {
+		return
+	}
+	if at.After(competitor.CurrentLapStartTime) {
+		competitor.IncompleteLapTime = at.Sub(competitor.CurrentLapStartTime)
+	}
+}
+
+// closeOpenRangeEntry records the firing range a competitor is stopped on as incomplete and counts the time spent on it
+func closeOpenRangeEntry(competitor *domain.Competitor, at time.Time) {
+	if competitor.RangeEnterTime.IsZero() {
+		return
+	}
+	intervals, shootingTime := domain.ShotIntervals(competitor.ShotTimesThisRange)
+	detail := domain.RangeDetail{
+		Range:         competitor.LastFiringRangeEntered,
+		Position:      competitor.PositionThisRange,
+		Hits:          competitor.HitsThisRange,
+		Shots:         competitor.ShotsThisRange,
+		SpareRounds:   competitor.SpareRoundsThisRange,
+		EnterTime:     competitor.RangeEnterTime,
+		LeaveTime:     at,
+		Incomplete:    true,
+		ShotIntervals: intervals,
+		ShootingTime:  shootingTime,
+	}
+	competitor.RangeDetails = append(competitor.RangeDetails, detail)
+	competitor.TotalRangeTime += detail.Duration()
+	if leg := competitor.CurrentLeg(); leg != nil {
+		leg.Hits += competitor.HitsThisRange
+	}
+
+	competitor.HitsThisRange = 0
+	competitor.ShotsThisRange = 0
+	competitor.SpareRoundsThisRange = 0
+	competitor.TargetsHitThisRange = competitor.TargetsHitThisRange[:0]
+	competitor.ShotTimesThisRange = competitor.ShotTimesThisRange[:0]
+	competitor.LastFiringRangeEntered = 0
+	competitor.PositionThisRange = domain.PositionUnknown
+	competitor.RangeEnterTime = time.Time{}
+}
+
+// recordRangeTransition stores the time the competitor took from the firing range to the penalty loops for the
+// penalty session starting with the event, warning about negative or implausibly long transitions
+func (simulator *Simulator) recordRangeTransition(competitor *domain.Competitor, event *domain.Event) {
+	competitor.PenaltyTransition = 0
+	transition, ok := competitor.RangeTransition(event.Timestamp)
+	if !ok {
+		return
+	}
+	if transition < 0 {
+		simulator.warn(WarningNegativeRangeTransition, event, competitor.ID, "competitor %d entered the penalty laps %s before leaving the firing range. Ignored.",
+			competitor.ID, domain.FormatDuration(-transition))
+		return
+	}
+	if transition > MaxRangeTransition {
+		simulator.warn(WarningLongRangeTransition, event, competitor.ID, "competitor %d took %s from the firing range to the penalty laps (more than %s).",
+			competitor.ID, domain.FormatDuration(transition), domain.FormatDuration(MaxRangeTransition))
+	}
+	competitor.PenaltyTransition = transition
+}
+
+// closeOpenPenaltySession records the penalty session of a competitor stopped in the penalty loops as incomplete
+// and adds the partial duration to the penalty time
+func closeOpenPenaltySession(competitor *domain.Competitor, at time.Time) {
+	if competitor.PenaltyStartTime.IsZero() {
+		return
+	}
+	if duration := at.Sub(competitor.PenaltyStartTime); duration > 0 {
+		competitor.TotalPenaltyTime += duration
+		if leg := competitor.CurrentLeg(); leg != nil {
+			leg.PenaltyTime += duration
+		}
+	}
+	competitor.PenaltySessions = append(competitor.PenaltySessions, domain.PenaltySession{
+		Lap:        competitor.CurrentLap,
+		Range:      competitor.LastCompletedRange(),
+		Loops:      competitor.MissesToPenalize,
+		StartTime:  competitor.PenaltyStartTime,
+		EndTime:    at,
+		Incomplete: true,
+		Transition: competitor.PenaltyTransition,
+	})
+	competitor.PenaltyStartTime = time.Time{}
+	competitor.PenaltyTransition = 0
+}
@@ -0,0 +1,117 @@
+package processing
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+	"biathlonPrototype/internal/report"
+)
+
+// onTheCourse registers and starts competitor 1 at 10:00:00.000
+const onTheCourse = `
+	[09:00:00.000] 1 1
+	[09:00:01.000] 2 1 10:00:00.000
+	[09:59:00.000] 3 1
+	[10:00:00.000] 4 1`
+
+func TestCannotContinueInThePenaltyLoopsClosesTheSession(t *testing.T) {
+	simulator := newTestSimulator(t)
+	mustProcess(t, simulator, onTheCourse+`
+		[10:05:00.000] 5 1 1
+		[10:05:10.000] 6 1 1
+		[10:05:20.000] 6 1 2
+		[10:05:30.000] 6 1 3
+		[10:05:40.000] 7 1
+		[10:05:45.000] 8 1
+		[10:06:05.000] 11 1 broken pole`)
+
+	competitor := simulator.Competitors[1]
+	if competitor.Status != domain.StatusNotFinished || !competitor.PenaltyStartTime.IsZero() {
+		t.Fatalf("status %s with penalty start %v, want NotFinished and a closed session", competitor.Status, competitor.PenaltyStartTime)
+	}
+	if competitor.TotalPenaltyTime != 20*time.Second {
+		t.Errorf("penalty time %v, want the 20s skied before stopping", competitor.TotalPenaltyTime)
+	}
+	if len(competitor.PenaltySessions) != 1 || !competitor.PenaltySessions[0].Incomplete || competitor.PenaltySessions[0].Duration() != 20*time.Second {
+		t.Errorf("penalty sessions %+v, want one incomplete session of 20s", competitor.PenaltySessions)
+	}
+	if competitor.PenaltySessions[0].Transition != 5*time.Second {
+		t.Errorf("transition %v, want 5s", competitor.PenaltySessions[0].Transition)
+	}
+	if competitor.PenaltyDetails.TotalDuration != competitor.TotalPenaltyTime || competitor.PenaltyDetails.AverageSpeed != 0 {
+		t.Errorf("penalty details %+v, want the penalty time %v without a speed", competitor.PenaltyDetails, competitor.TotalPenaltyTime)
+	}
+	checkPenaltyCells(t, simulator, "{0, 00:00:20.000, 0.000}", "{00:00:20.000, 0.000}")
+	if violations := competitor.Validate(domain.Limits{Laps: 2, FiringLines: 2}); len(violations) != 0 {
+		t.Errorf("violations %v", violations)
+	}
+}
+
+func TestPenaltySpeedCoversTheCompletedSessions(t *testing.T) {
+	simulator := newTestSimulator(t)
+	mustProcess(t, simulator, onTheCourse+`
+		[10:05:00.000] 5 1 1
+		[10:05:10.000] 6 1 1
+		[10:05:20.000] 6 1 2
+		[10:05:30.000] 6 1 3
+		[10:05:40.000] 7 1
+		[10:05:45.000] 8 1
+		[10:06:45.000] 9 1
+		[10:10:00.000] 10 1
+		[10:15:00.000] 5 1 2
+		[10:15:10.000] 6 1 1
+		[10:15:40.000] 7 1
+		[10:15:45.000] 8 1
+		[10:16:05.000] 11 1 broken pole`)
+
+	competitor := simulator.Competitors[1]
+	if competitor.TotalPenaltyLaps != 2 || competitor.TotalPenaltyTime != 80*time.Second {
+		t.Fatalf("%d penalty loops in %v, want 2 in 80s", competitor.TotalPenaltyLaps, competitor.TotalPenaltyTime)
+	}
+	// The two loops of 150 m took 60s; the 20s in the unfinished session count in the time only
+	if competitor.PenaltyDetails.TotalDuration != 80*time.Second || competitor.PenaltyDetails.AverageSpeed != 5 {
+		t.Errorf("penalty details %+v, want 80s at 5 m/s", competitor.PenaltyDetails)
+	}
+	checkPenaltyCells(t, simulator, "{2, 00:01:20.000, 5.000}", "{00:01:20.000, 5.000}")
+}
+
+// checkPenaltyCells checks the penalty cell of the only competitor in the report and the legacy report
+func checkPenaltyCells(t *testing.T, simulator *Simulator, want, wantLegacy string) {
+	t.Helper()
+	competitors := simulator.GetSortedCompetitors()
+	if line := report.GenerateReport(competitors)[0]; !strings.Contains(line, "] "+want+" ") {
+		t.Errorf("report line %q has no penalty cell %s", line, want)
+	}
+	if line := report.GenerateLegacyReport(competitors)[0]; !strings.Contains(line, "] "+wantLegacy+" ") {
+		t.Errorf("legacy report line %q has no penalty cell %s", line, wantLegacy)
+	}
+}
+
+func TestCannotContinueOnTheFiringRangeClosesTheRange(t *testing.T) {
+	simulator := newTestSimulator(t)
+	mustProcess(t, simulator, onTheCourse+`
+		[10:05:00.000] 5 1 1
+		[10:05:10.000] 6 1 1
+		[10:05:20.000] 6 1 2
+		[10:05:25.000] 11 1`)
+
+	competitor := simulator.Competitors[1]
+	if competitor.Status != domain.StatusNotFinished || !competitor.RangeEnterTime.IsZero() {
+		t.Fatalf("status %s with range entry %v, want NotFinished and a closed range", competitor.Status, competitor.RangeEnterTime)
+	}
+	if len(competitor.RangeDetails) != 1 {
+		t.Fatalf("range details %+v, want one", competitor.RangeDetails)
+	}
+	detail := competitor.RangeDetails[0]
+	if !detail.Incomplete || detail.Hits != 2 || detail.Misses != 0 || detail.Duration() != 25*time.Second {
+		t.Errorf("range %+v, want an incomplete range with 2 hits, no misses and 25s", detail)
+	}
+	if competitor.TotalRangeTime != 25*time.Second || competitor.TotalPenaltyTime != 0 {
+		t.Errorf("range time %v and penalty time %v, want 25s and none", competitor.TotalRangeTime, competitor.TotalPenaltyTime)
+	}
+	if violations := competitor.Validate(domain.Limits{Laps: 2, FiringLines: 2}); len(violations) != 0 {
+		t.Errorf("violations %v", violations)
+	}
+}
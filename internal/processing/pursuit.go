@@ -0,0 +1,40 @@
+package processing
+
+import (
+	"biathlonPrototype/internal/domain"
+)
+
+// seedStart sets the common race start and the start time derived from the previous race for a registering
+// competitor of a pursuit
+func (simulator *Simulator) seedStart(competitor *domain.Competitor, event *domain.Event) {
+	cfg := simulator.configFor(competitor)
+	if !cfg.IsPursuit() {
+		return
+	}
+
+	competitor.RaceStartTime = cfg.ParsedStart
+	gap, seeded := simulator.PursuitGaps[competitor.ID]
+	if !seeded {
+		simulator.warn(WarningNotSeeded, event, competitor.ID, "competitor %d has no result in the previous race, the start time must be set by event", competitor.ID)
+		return
+	}
+	competitor.ScheduledStartTime = cfg.ParsedStart.Add(gap)
+}
+
+// finishOrder returns the position of each competitor in the order the Finished events were generated in pursuits,
+// where competitors finishing at the same time are ranked as they crossed the line; nil in other formats
+func (simulator *Simulator) finishOrder() map[int]int {
+	if !simulator.Config.IsPursuit() {
+		return nil
+	}
+
+	order := make(map[int]int)
+	for _, event := range simulator.Events {
+		if event.ID == domain.Finished && !event.IsIncoming {
+			if _, seen := order[event.CompetitorID]; !seen {
+				order[event.CompetitorID] = len(order)
+			}
+		}
+	}
+	return order
+}
@@ -0,0 +1,47 @@
+package processing
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// indexEvent adds an event recorded in Events to the per-competitor index
+func (simulator *Simulator) indexEvent(event *domain.Event) {
+	if simulator.eventsByCompetitor == nil {
+		simulator.eventsByCompetitor = make(map[int][]*domain.Event)
+	}
+	simulator.eventsByCompetitor[event.CompetitorID] = insertEvent(simulator.eventsByCompetitor[event.CompetitorID], event)
+}
+
+// rebuildEventIndex recreates the per-competitor index from Events, e.g. after restoring a snapshot
+func (simulator *Simulator) rebuildEventIndex() {
+	simulator.eventsByCompetitor = make(map[int][]*domain.Event)
+	for _, event := range simulator.Events {
+		simulator.indexEvent(event)
+	}
+}
+
+// EventsForCompetitor returns the recorded incoming and generated events of a competitor in the order of Events.
+// The returned slice is a copy; the events themselves must not be changed
+func (simulator *Simulator) EventsForCompetitor(id int) []*domain.Event {
+	return slices.Clone(simulator.eventsByCompetitor[id])
+}
+
+// EventsBetween returns the recorded incoming and generated events with timestamps in the inclusive range, in the
+// order of Events, which is ordered by timestamp. The returned slice is a copy; the events
+// themselves must not be changed
+func (simulator *Simulator) EventsBetween(from, to time.Time) []*domain.Event {
+	first := sort.Search(len(simulator.Events), func(i int) bool {
+		return !simulator.Events[i].Timestamp.Before(from)
+	})
+	last := sort.Search(len(simulator.Events), func(i int) bool {
+		return simulator.Events[i].Timestamp.After(to)
+	})
+	if first >= last {
+		return []*domain.Event{}
+	}
+	return slices.Clone(simulator.Events[first:last])
+}
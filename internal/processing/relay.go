@@ -0,0 +1,39 @@
+package processing
+
+import (
+	"strconv"
+
+	"biathlonPrototype/internal/config"
+	"biathlonPrototype/internal/domain"
+)
+
+// exchangeLeg hands a relay team over from the current leg to the next one
+func (simulator *Simulator) exchangeLeg(competitor *domain.Competitor, cfg *config.Config, event *domain.Event) {
+	leg := competitor.CurrentLeg()
+	if !cfg.IsRelay() || leg == nil {
+		simulator.warn(WarningUnexpectedExchange, event, competitor.ID, "Exchange event (%d) outside of a relay leg. Ignored.", competitor.ID)
+		return
+	}
+
+	outgoingLeg, _ := strconv.Atoi(event.ExtraParameters[0])
+	incomingLeg, _ := strconv.Atoi(event.ExtraParameters[1])
+	if outgoingLeg != leg.Leg || incomingLeg != leg.Leg+1 || incomingLeg > cfg.Legs {
+		simulator.warn(WarningUnexpectedExchange, event, competitor.ID, "team %d exchanged from leg %d to leg %d during leg %d of %d. Ignored.",
+			competitor.ID, outgoingLeg, incomingLeg, leg.Leg, cfg.Legs)
+		return
+	}
+
+	if lapsCompleted := competitor.CurrentLap - 1; lapsCompleted != leg.Leg*cfg.Laps {
+		simulator.warn(WarningUnexpectedExchange, event, competitor.ID, "team %d exchanged after %d laps, expected %d at the end of leg %d.",
+			competitor.ID, lapsCompleted, leg.Leg*cfg.Laps, leg.Leg)
+	}
+	if competitor.TotalFiringRangesCompleted < cfg.FiringLines {
+		simulator.warn(WarningIncompleteFiringRanges, event, competitor.ID, "team %d exchanged but leg %d completed %d of %d firing ranges.",
+			competitor.ID, leg.Leg, competitor.TotalFiringRangesCompleted, cfg.FiringLines)
+	}
+
+	leg.EndTime = event.Timestamp
+	competitor.Legs = append(competitor.Legs, domain.LegDetail{Leg: incomingLeg, StartTime: event.Timestamp})
+	competitor.TotalFiringRangesCompleted = 0
+	competitor.LastFiringRangeEntered = 0
+}
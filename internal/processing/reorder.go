@@ -0,0 +1,82 @@
+package processing
+
+import (
+	"fmt"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// reorderBuffer holds recently read events and releases them in timestamp order
+type reorderBuffer struct {
+	maxEvents int
+	window    time.Duration
+	pending   []*domain.Event
+	watermark time.Time
+}
+
+// newReorderBuffer creates a buffer; with no limits set every event is released immediately
+func newReorderBuffer(maxEvents int, window time.Duration) *reorderBuffer {
+	return &reorderBuffer{
+		maxEvents: maxEvents,
+		window:    window,
+		pending:   make([]*domain.Event, 0),
+	}
+}
+
+// push adds an event to the buffer and returns the events that are ready to be processed
+func (buffer *reorderBuffer) push(event *domain.Event) ([]*domain.Event, error) {
+	if !buffer.watermark.IsZero() && event.Timestamp.Before(buffer.watermark) {
+		return nil, fmt.Errorf("time order of events %s is broken: %s before %s",
+			describeEventPosition(event), domain.FormatTime(event.Timestamp), domain.FormatTime(buffer.watermark))
+	}
+
+	insertAt := len(buffer.pending)
+	for insertAt > 0 && event.Timestamp.Before(buffer.pending[insertAt-1].Timestamp) {
+		insertAt--
+	}
+	buffer.pending = append(buffer.pending, nil)
+	copy(buffer.pending[insertAt+1:], buffer.pending[insertAt:])
+	buffer.pending[insertAt] = event
+
+	released := make([]*domain.Event, 0)
+	for len(buffer.pending) > 0 && buffer.mustRelease() {
+		released = append(released, buffer.releaseOldest())
+	}
+	return released, nil
+}
+
+// flush releases all remaining events
+func (buffer *reorderBuffer) flush() []*domain.Event {
+	released := make([]*domain.Event, 0, len(buffer.pending))
+	for len(buffer.pending) > 0 {
+		released = append(released, buffer.releaseOldest())
+	}
+	return released
+}
+
+// mustRelease reports whether the oldest pending event has to leave the buffer
+func (buffer *reorderBuffer) mustRelease() bool {
+	if buffer.maxEvents <= 0 && buffer.window <= 0 {
+		return true
+	}
+	if buffer.maxEvents > 0 && len(buffer.pending) > buffer.maxEvents {
+		return true
+	}
+	if buffer.window > 0 {
+		oldest := buffer.pending[0].Timestamp
+		newest := buffer.pending[len(buffer.pending)-1].Timestamp
+		if newest.Sub(oldest) > buffer.window {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseOldest removes the oldest pending event and advances the watermark
+func (buffer *reorderBuffer) releaseOldest() *domain.Event {
+	event := buffer.pending[0]
+	buffer.pending = buffer.pending[1:]
+	buffer.watermark = event.Timestamp
+	return event
+}
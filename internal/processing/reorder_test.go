@@ -0,0 +1,134 @@
+package processing
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// pushAll pushes the events through the buffer, flushes it and returns the competitor IDs in release order
+func pushAll(t *testing.T, buffer *reorderBuffer, events []*domain.Event) ([]int, error) {
+	t.Helper()
+	var order []int
+	for _, event := range events {
+		released, err := buffer.push(event)
+		if err != nil {
+			return order, err
+		}
+		for _, event := range released {
+			order = append(order, event.CompetitorID)
+		}
+	}
+	for _, event := range buffer.flush() {
+		order = append(order, event.CompetitorID)
+	}
+	return order, nil
+}
+
+func TestReorderBufferReleasesLateEventsInOrder(t *testing.T) {
+	events := parseEvents(t, `
+		[10:00:00.000] 1 1
+		[10:00:00.400] 1 3
+		[10:00:00.200] 1 2
+		[10:00:01.000] 1 4
+		[10:00:00.900] 1 5`)
+	order, err := pushAll(t, newReorderBuffer(2, 0), events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 2, 3, 5, 4}; !slices.Equal(order, want) {
+		t.Errorf("released %v, want %v", order, want)
+	}
+}
+
+func TestReorderBufferKeepsArrivalOrderOfEqualTimestamps(t *testing.T) {
+	events := parseEvents(t, `
+		[10:00:00.500] 1 1
+		[10:00:00.500] 1 2
+		[10:00:00.100] 1 3
+		[10:00:00.500] 1 4`)
+	order, err := pushAll(t, newReorderBuffer(0, 2*time.Second), events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{3, 1, 2, 4}; !slices.Equal(order, want) {
+		t.Errorf("released %v, want %v", order, want)
+	}
+}
+
+func TestReorderBufferSortsReversedInputWithinTheWindow(t *testing.T) {
+	var lines []string
+	for i := 9; i >= 1; i-- {
+		lines = append(lines, fmt.Sprintf("[10:00:0%d.000] 1 %d", i, i))
+	}
+	order, err := pushAll(t, newReorderBuffer(0, 10*time.Second), parseEvents(t, strings.Join(lines, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}; !slices.Equal(order, want) {
+		t.Errorf("released %v, want %v", order, want)
+	}
+}
+
+func TestReorderBufferRejectsEventsOlderThanTheWatermark(t *testing.T) {
+	events := parseEvents(t, `
+		[10:00:00.000] 1 1
+		[10:00:05.000] 1 2
+		[10:00:04.000] 1 3
+		[09:59:59.900] 1 4`)
+	order, err := pushAll(t, newReorderBuffer(0, 2*time.Second), events)
+	if err == nil {
+		t.Fatalf("released %v without an error", order)
+	}
+	if !slices.Equal(order, []int{1}) {
+		t.Errorf("released %v before the error, want [1]", order)
+	}
+	if !strings.Contains(err.Error(), "time order of events") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestReorderBufferWithoutLimitsReleasesImmediately(t *testing.T) {
+	buffer := newReorderBuffer(0, 0)
+	events := parseEvents(t, `
+		[10:00:01.000] 1 1
+		[10:00:00.000] 1 2`)
+	if released, err := buffer.push(events[0]); err != nil || len(released) != 1 {
+		t.Fatalf("released %v, %v", released, err)
+	}
+	if _, err := buffer.push(events[1]); err == nil {
+		t.Error("an earlier event was accepted without a buffer")
+	}
+}
+
+func TestReorderWindowSortsAShuffledRace(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(readExample(t)), "\n")
+	// Swap neighbouring lines so that every pair arrives in the wrong order, a few hundred milliseconds apart
+	shuffled := slices.Clone(lines)
+	for i := 0; i+1 < len(shuffled); i += 2 {
+		shuffled[i], shuffled[i+1] = shuffled[i+1], shuffled[i]
+	}
+
+	sorted := newTestSimulator(t)
+	if err := sorted.ProcessEventsFromReader(strings.NewReader(strings.Join(lines, "\n"))); err != nil {
+		t.Fatal(err)
+	}
+	reordered := newTestSimulator(t)
+	reordered.ReorderWindow = 24 * time.Hour
+	if err := reordered.ProcessEventsFromReader(strings.NewReader(strings.Join(shuffled, "\n"))); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(reordered.OutputLog, sorted.OutputLog) {
+		t.Errorf("output log of the shuffled race differs:\n%s\nwant:\n%s",
+			strings.Join(reordered.OutputLog, "\n"), strings.Join(sorted.OutputLog, "\n"))
+	}
+
+	unbuffered := newTestSimulator(t)
+	if err := unbuffered.ProcessEventsFromReader(strings.NewReader(strings.Join(shuffled, "\n"))); err == nil {
+		t.Error("the shuffled race was accepted without a reorder buffer")
+	}
+}
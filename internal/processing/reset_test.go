@@ -0,0 +1,71 @@
+package processing
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"biathlonPrototype/internal/report"
+)
+
+// raceOutput runs the example race and returns the output log and the text and JSON reports
+func raceOutput(t *testing.T, simulator *Simulator) (string, string, string) {
+	t.Helper()
+	if err := simulator.ProcessEventsFromReader(strings.NewReader(readExample(t))); err != nil {
+		t.Fatal(err)
+	}
+	competitors := simulator.GetSortedCompetitors()
+	var text strings.Builder
+	if err := report.WriteReport(&text, competitors, report.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := report.GenerateJSON(competitors, report.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Join(simulator.OutputLog, "\n"), text.String(), string(data)
+}
+
+func TestResetProducesTheOutputOfAFreshSimulator(t *testing.T) {
+	wantLog, wantText, wantJSON := raceOutput(t, newTestSimulator(t))
+
+	simulator := newTestSimulator(t)
+	for run := 1; run <= 3; run++ {
+		log, text, json := raceOutput(t, simulator)
+		if log != wantLog {
+			t.Errorf("run %d: output log differs:\n%s\nwant:\n%s", run, log, wantLog)
+		}
+		if text != wantText {
+			t.Errorf("run %d: text report differs:\n%s\nwant:\n%s", run, text, wantText)
+		}
+		if json != wantJSON {
+			t.Errorf("run %d: JSON report differs", run)
+		}
+		if len(simulator.Warnings()) != 0 {
+			t.Errorf("run %d: warnings %v", run, warningCodes(simulator))
+		}
+		simulator.Reset()
+		if len(simulator.Competitors) != 0 || len(simulator.Events) != 0 || len(simulator.OutputLog) != 0 || !simulator.CurrentTime.IsZero() {
+			t.Fatalf("run %d: state left after Reset", run)
+		}
+	}
+}
+
+func TestResetWithConfigUsesTheNewConfiguration(t *testing.T) {
+	simulator := newTestSimulator(t)
+	mustProcess(t, simulator, readExample(t))
+	oneLap := loadConfig(t, strings.Replace(testConfig, `"laps": 2`, `"laps": 1`, 1))
+
+	simulator.ResetWithConfig(oneLap)
+	if simulator.Config != oneLap {
+		t.Fatal("configuration not replaced")
+	}
+	mustProcess(t, simulator, finishedRace)
+	if got := simulator.Competitors[1].FinishTime; got.IsZero() {
+		t.Fatal("competitor 1 did not finish")
+	}
+	finishes := slices.IndexFunc(simulator.OutputLog, func(line string) bool { return strings.Contains(line, "has finished") })
+	if finishes < 0 || !strings.HasPrefix(simulator.OutputLog[finishes], "[10:10:00.000]") {
+		t.Errorf("output log %q, want a finish after the first lap", simulator.OutputLog)
+	}
+}
@@ -0,0 +1,84 @@
+package processing
+
+import (
+	"errors"
+	"sync"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// ErrSimulatorClosed is returned for events submitted to a SerializedSimulator after Close
+var ErrSimulatorClosed = errors.New("simulator is closed")
+
+// SerializedSimulator is the entry point for processing events from several goroutines, e.g. one per timing station.
+// The Simulator itself is not safe for concurrent use; the wrapper hands every call to a single consumer goroutine
+// that owns the simulator, so callbacks and handlers also run on that goroutine
+type SerializedSimulator struct {
+	simulator *Simulator
+	requests  chan func(*Simulator)
+	closed    chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// NewSerializedSimulator starts the consumer goroutine for the simulator; it must not be used directly until Close
+func NewSerializedSimulator(simulator *Simulator) *SerializedSimulator {
+	serialized := &SerializedSimulator{
+		simulator: simulator,
+		requests:  make(chan func(*Simulator)),
+		closed:    make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	go serialized.consume()
+	return serialized
+}
+
+// consume runs the submitted calls one after another until the simulator is closed
+func (serialized *SerializedSimulator) consume() {
+	defer close(serialized.stopped)
+	for {
+		select {
+		case request := <-serialized.requests:
+			request(serialized.simulator)
+		case <-serialized.closed:
+			return
+		}
+	}
+}
+
+// ProcessEvent processes an event on the consumer goroutine and waits for the result
+func (serialized *SerializedSimulator) ProcessEvent(event *domain.Event) error {
+	var err error
+	if submitErr := serialized.Do(func(simulator *Simulator) {
+		err = simulator.ProcessEvent(event)
+	}); submitErr != nil {
+		return submitErr
+	}
+	return err
+}
+
+// Do runs a function with exclusive access to the simulator, e.g. to read standings, and waits for it to return
+func (serialized *SerializedSimulator) Do(function func(*Simulator)) error {
+	done := make(chan struct{})
+	request := func(simulator *Simulator) {
+		defer close(done)
+		function(simulator)
+	}
+
+	select {
+	case serialized.requests <- request:
+	case <-serialized.closed:
+		return ErrSimulatorClosed
+	}
+	<-done
+	return nil
+}
+
+// Close stops the consumer goroutine after the running call and returns the simulator for direct use
+func (serialized *SerializedSimulator) Close() *Simulator {
+	serialized.closeOnce.Do(func() {
+		close(serialized.closed)
+	})
+	<-serialized.stopped
+	return serialized.simulator
+}
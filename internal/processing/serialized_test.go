@@ -0,0 +1,75 @@
+package processing
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// Run with -race: every competitor's events are submitted from their own goroutine, interleaved with the others
+func TestSerializedSimulatorFromManyGoroutines(t *testing.T) {
+	events := parseEvents(t, readExample(t))
+	sequential := newTestSimulator(t)
+	for _, event := range events {
+		if err := sequential.ProcessEvent(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	byCompetitor := make(map[int][]*domain.Event)
+	for _, event := range parseEvents(t, readExample(t)) {
+		byCompetitor[event.CompetitorID] = append(byCompetitor[event.CompetitorID], event)
+	}
+	serialized := NewSerializedSimulator(newTestSimulator(t))
+	var wait sync.WaitGroup
+	errs := make(chan error, len(events))
+	for _, competitorEvents := range byCompetitor {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for _, event := range competitorEvents {
+				if err := serialized.ProcessEvent(event); err != nil {
+					errs <- err
+				}
+				// Readers share the consumer goroutine with the writers
+				_ = serialized.Do(func(simulator *Simulator) { simulator.Standings() })
+			}
+		}()
+	}
+	wait.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	simulator := serialized.Close()
+	for id, want := range sequential.Competitors {
+		got := simulator.Competitors[id]
+		wantTime, _ := want.CalculateTotalTime()
+		gotTime, _ := got.CalculateTotalTime()
+		if got.Status != want.Status || gotTime != wantTime || got.TotalHits != want.TotalHits {
+			t.Errorf("competitor %d: %s %v %d hits, want %s %v %d hits", id, got.Status, gotTime, got.TotalHits, want.Status, wantTime, want.TotalHits)
+		}
+	}
+	gotLog, wantLog := slices.Sorted(slices.Values(simulator.OutputLog)), slices.Sorted(slices.Values(sequential.OutputLog))
+	if !slices.Equal(gotLog, wantLog) {
+		t.Errorf("output log differs:\n%q\nwant:\n%q", gotLog, wantLog)
+	}
+	if len(simulator.Events) != len(sequential.Events) {
+		t.Errorf("%d events recorded, want %d", len(simulator.Events), len(sequential.Events))
+	}
+}
+
+func TestSerializedSimulatorRejectsEventsAfterClose(t *testing.T) {
+	serialized := NewSerializedSimulator(newTestSimulator(t))
+	serialized.Close()
+	event := parseEvents(t, "[09:00:00.000] 1 1")[0]
+	if err := serialized.ProcessEvent(event); !errors.Is(err, ErrSimulatorClosed) {
+		t.Errorf("ProcessEvent after Close returned %v, want ErrSimulatorClosed", err)
+	}
+	// Closing twice is allowed
+	serialized.Close()
+}
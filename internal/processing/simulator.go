@@ -2,38 +2,197 @@ package processing
 
 import (
 	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"biathlonPrototype/internal/config"
 	"biathlonPrototype/internal/domain"
 )
 
-// Simulator manages the state of the simulation
+// gzipMagic is the header that identifies gzip compressed files
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// DefaultShotsPerRange is the number of shots assumed per firing range when no ShotFired events are reported
+const DefaultShotsPerRange = 5
+
+// Simulator manages the state of the simulation. Competitors is the live race state that processing mutates;
+// code outside the simulator should read competitors through Competitor and CompetitorIDs instead
 type Simulator struct {
 	Config      *config.Config
 	Competitors map[int]*domain.Competitor
 	Events      []*domain.Event
 	CurrentTime time.Time
-	OutputLog   []string
+	// OutputLog holds the output lines ordered by time; generated events are inserted at their timestamp
+	OutputLog []string
+	RaceInfo  *domain.RaceInfo
+
+	// KeepComments echoes '#' comments from the event file into OutputLog
+	KeepComments bool
+
+	// ReorderBufferSize and ReorderWindow allow slightly out-of-order input to be sorted before processing
+	ReorderBufferSize int
+	ReorderWindow     time.Duration
+
+	// DedupHistory and DedupWindow enable dropping exact duplicate events seen recently for the same competitor
+	DedupHistory      int
+	DedupWindow       time.Duration
+	DuplicatesDropped int
+
+	// SortEvents reads the whole input and sorts it by timestamp before processing instead of requiring ordered input
+	SortEvents bool
+
+	// StationPolicy resolves copies of the same event reported by different timing stations
+	StationPolicy         StationPolicy
+	PrimaryStation        string
+	StationConflictWindow time.Duration
+	StationConflicts      []StationConflict
+
+	// CheckpointPath enables writing the simulator state to a checkpoint file every CheckpointEvery events
+	CheckpointPath  string
+	CheckpointEvery int
+
+	// PursuitGaps are the gaps to the winner of the previous race by competitor; in pursuits they seed the start times
+	PursuitGaps map[int]time.Duration
+
+	// StrictTransitions makes ProcessEvent return a *domain.TransitionError for events that the transition table
+	// does not allow in the current status of the competitor instead of warning and applying them anyway. The EndLap
+	// of a cooldown lap after the finish is still only a warning
+	StrictTransitions bool
+
+	// Clock provides the current time for deadline checks; nil uses the time of the last processed event
+	Clock Clock
+
+	// CloseUnfinished marks competitors who started but have no finish when the input ends as NotFinished.
+	// Leave it off for live or intermediate runs that should keep them in progress
+	CloseUnfinished bool
+
+	// ValidateState checks the invariants of the competitor after every event; they are always checked for all
+	// competitors when the input ends
+	ValidateState bool
+
+	// OnlyCompetitors restricts processing to the listed competitors when not empty
+	OnlyCompetitors []int
+
+	// Callbacks are notified about finishes, disqualifications, completed laps and status changes
+	Callbacks Callbacks
+
+	// PrintWarnings logs every recorded warning
+	PrintWarnings bool
+	warnings      []Warning
+	logger        *slog.Logger
+	stats         Stats
+
+	eventsByCompetitor map[int][]*domain.Event
+	outputTimes        []time.Time
+
+	// batching holds back callbacks and logged warnings in pending while ProcessEvents works on a clone
+	batching bool
+	pending  []func(*Simulator)
+
+	handlers   map[domain.EventID]EventHandler
+	formatters map[domain.EventID]EventFormatter
 }
 
-// NewSimulator creates a new simulator
-func NewSimulator(cfg *config.Config) *Simulator {
-	return &Simulator{
-		Config:      cfg,
-		Competitors: make(map[int]*domain.Competitor),
-		Events:      make([]*domain.Event, 0),
-		OutputLog:   make([]string, 0),
+// NewSimulator creates a new simulator; without options it logs to stdout and stderr
+func NewSimulator(cfg *config.Config, options ...Option) *Simulator {
+	simulator := &Simulator{
+		Config:        cfg,
+		Competitors:   make(map[int]*domain.Competitor),
+		Events:        make([]*domain.Event, 0),
+		OutputLog:     make([]string, 0),
+		PrintWarnings: true,
+		warnings:      make([]Warning, 0),
+		handlers:      make(map[domain.EventID]EventHandler),
+		formatters:    make(map[domain.EventID]EventFormatter),
+		logger:        defaultLogger(),
+		stats:         newStats(),
+	}
+	for _, option := range options {
+		option(simulator)
 	}
+	return simulator
 }
 
-// LoadEventsFromFile loads and processes events from a file
-func (simulator *Simulator) LoadEventsFromFile(filePath string) (err error) {
+// Reset clears the race state so the simulator can process another race with the same configuration and options.
+// The allocated maps and slices are reused, so slices returned before the reset must not be used afterwards
+func (simulator *Simulator) Reset() {
+	clear(simulator.Competitors)
+	clear(simulator.Events)
+	simulator.Events = simulator.Events[:0]
+	clear(simulator.OutputLog)
+	simulator.OutputLog = simulator.OutputLog[:0]
+	simulator.outputTimes = simulator.outputTimes[:0]
+	simulator.CurrentTime = time.Time{}
+	simulator.RaceInfo = nil
+	simulator.DuplicatesDropped = 0
+	simulator.StationConflicts = nil
+	clear(simulator.warnings)
+	simulator.warnings = simulator.warnings[:0]
+	simulator.stats = newStats()
+	clear(simulator.eventsByCompetitor)
+}
+
+// ResetWithConfig resets the simulator and replaces its configuration
+func (simulator *Simulator) ResetWithConfig(cfg *config.Config) {
+	simulator.Reset()
+	simulator.Config = cfg
+}
+
+// LoadEventsFromFile loads and processes events from a file, decompressing gzip files transparently
+func (simulator *Simulator) LoadEventsFromFile(filePath string) error {
+	return simulator.LoadEventsFromFileCtx(context.Background(), filePath)
+}
+
+// LoadEventsFromFileCtx is LoadEventsFromFile that stops between lines when the context is canceled
+func (simulator *Simulator) LoadEventsFromFileCtx(ctx context.Context, filePath string) error {
+	_, err := simulator.LoadEventsFromFileAtCtx(ctx, filePath, FilePosition{})
+	return err
+}
+
+// LoadEventsFromFileAt loads and processes events starting at a file position and returns the position after the last processed line
+func (simulator *Simulator) LoadEventsFromFileAt(filePath string, position FilePosition) (FilePosition, error) {
+	return simulator.LoadEventsFromFileAtCtx(context.Background(), filePath, position)
+}
+
+// LoadEventsFromFileAtCtx is LoadEventsFromFileAt that stops between lines when the context is canceled
+func (simulator *Simulator) LoadEventsFromFileAtCtx(ctx context.Context, filePath string, position FilePosition) (FilePosition, error) {
+	finalPosition := position
+	err := withEventFile(filePath, position.Offset, simulator.logger, func(reader io.Reader) error {
+		source := simulator.newLineEventSource(reader, position)
+		_, err := simulator.RunCtx(ctx, source)
+		finalPosition = source.Position()
+		return err
+	})
+	return finalPosition, err
+}
+
+// LoadEventsFromBinaryFile loads and processes length-prefixed binary encoded events from a file
+func (simulator *Simulator) LoadEventsFromBinaryFile(filePath string) error {
+	return simulator.LoadEventsFromBinaryFileCtx(context.Background(), filePath)
+}
+
+// LoadEventsFromBinaryFileCtx is LoadEventsFromBinaryFile that stops between events when the context is canceled
+func (simulator *Simulator) LoadEventsFromBinaryFileCtx(ctx context.Context, filePath string) error {
+	return withEventFile(filePath, 0, simulator.logger, func(reader io.Reader) error {
+		_, err := simulator.RunCtx(ctx, NewBinaryEventSource(reader))
+		return err
+	})
+}
+
+// withEventFile opens an event file, unwrapping gzip compression if present, and passes its contents from the offset on to process
+func withEventFile(filePath string, offset int64, logger *slog.Logger, process func(io.Reader) error) (err error) {
 	var file *os.File
 	file, err = os.Open(filePath)
 	if err != nil {
@@ -44,73 +203,300 @@ func (simulator *Simulator) LoadEventsFromFile(filePath string) (err error) {
 		if err == nil && closeErr != nil {
 			err = fmt.Errorf("error closing event file %s: %w", filePath, closeErr)
 		} else if closeErr != nil {
-			fmt.Fprintf(os.Stderr, "Additional error while closing event file %s: %v (original error: %v)\n", filePath, closeErr, err)
+			logger.Error(fmt.Sprintf("Additional error while closing event file %s: %v (original error: %v)", filePath, closeErr, err),
+				"file", filePath)
 		}
 	}()
 
-	scanner := bufio.NewScanner(file)
-	lineNumber := 0
-	var previousTimestamp time.Time
-	var processEventErr error
+	bufferedReader := bufio.NewReader(file)
+	var reader io.Reader = bufferedReader
+	magic, _ := bufferedReader.Peek(len(gzipMagic))
+	if strings.HasSuffix(filePath, ".gz") || bytes.Equal(magic, gzipMagic) {
+		var gzipReader *gzip.Reader
+		gzipReader, err = gzip.NewReader(reader)
+		if err != nil {
+			return fmt.Errorf("error opening compressed event file %s: %w", filePath, err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+
+		if offset > 0 {
+			if _, err = io.CopyN(io.Discard, reader, offset); err != nil {
+				return fmt.Errorf("error skipping to offset %d in compressed event file %s: %w", offset, filePath, err)
+			}
+		}
+	} else if offset > 0 {
+		if _, err = file.Seek(offset, io.SeekStart); err != nil {
+			return fmt.Errorf("error seeking to offset %d in event file %s: %w", offset, filePath, err)
+		}
+		reader = bufio.NewReader(file)
+	}
+
+	if err = process(reader); err != nil {
+		return fmt.Errorf("error processing event file %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// ProcessEventsFromReader reads events line by line from a reader and processes them
+func (simulator *Simulator) ProcessEventsFromReader(reader io.Reader) error {
+	return simulator.ProcessEventsFromReaderCtx(context.Background(), reader)
+}
+
+// ProcessEventsFromReaderCtx is ProcessEventsFromReader that stops between lines when the context is canceled
+func (simulator *Simulator) ProcessEventsFromReaderCtx(ctx context.Context, reader io.Reader) error {
+	_, err := simulator.RunCtx(ctx, simulator.newLineEventSource(reader, FilePosition{}))
+	return err
+}
 
-	for scanner.Scan() {
-		lineNumber++
-		line := scanner.Text()
-		if line == "" {
-			continue
+// newLineEventSource creates a line source that echoes comments and holds back partial lines when checkpointing
+func (simulator *Simulator) newLineEventSource(reader io.Reader, position FilePosition) *LineEventSource {
+	source := NewLineEventSourceAt(reader, position)
+	source.HoldPartialLine = simulator.CheckpointPath != ""
+	source.Logger = simulator.logger
+	source.OnComment = func(lineNumber int, comment string) {
+		if simulator.KeepComments {
+			simulator.appendUntimedOutput(comment)
 		}
+	}
+	source.OnRaceHeader = func(info *domain.RaceInfo) {
+		simulator.RaceInfo = info
+		simulator.appendUntimedOutput(info.String())
+	}
+	return source
+}
+
+// Run consumes events from the source until it is exhausted and returns the number of processed events
+func (simulator *Simulator) Run(source EventSource) (int, error) {
+	return simulator.RunCtx(context.Background(), source)
+}
 
-		var event *domain.Event
-		event, err = domain.ParseEventFromString(line)
+// RunCtx is Run that stops reading from the source when the context is canceled. The events read so far are still
+// processed, so the simulator can be reported on or checkpointed, and the context error is returned wrapped with
+// how far processing got
+func (simulator *Simulator) RunCtx(ctx context.Context, source EventSource) (int, error) {
+	if simulator.SortEvents {
+		source = newSortedEventSource(source)
+	}
+	buffer := newReorderBuffer(simulator.ReorderBufferSize, simulator.ReorderWindow)
+	duplicates := newDuplicateFilter(simulator.DedupHistory, simulator.DedupWindow)
+	resolver := newStationResolver(simulator.StationPolicy, simulator.PrimaryStation, simulator.StationConflictWindow)
+	positioned, canCheckpoint := source.(positionedSource)
+	canCheckpoint = canCheckpoint && simulator.CheckpointPath != ""
+	processed := 0
+	lastCheckpoint := 0
+	var canceled error
+
+	for {
+		if canceled = ctx.Err(); canceled != nil {
+			break
+		}
+		event, err := source.Next()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			err = fmt.Errorf("string parsing error at line %d ('%s'): %w", lineNumber, line, err)
-			return err
+			return processed, err
 		}
 
-		if !previousTimestamp.IsZero() && event.Timestamp.Before(previousTimestamp) {
-			err = fmt.Errorf("time order of events on line %d is broken: %s before %s", lineNumber, domain.FormatTime(event.Timestamp), domain.FormatTime(previousTimestamp))
-			return err
+		released, err := buffer.push(event)
+		if err != nil {
+			return processed, err
+		}
+		count, err := simulator.processReleased(simulator.resolveStations(resolver, released), duplicates)
+		processed += count
+		if err != nil {
+			return processed, err
 		}
-		previousTimestamp = event.Timestamp
 
-		processEventErr = simulator.ProcessEvent(event)
-		if processEventErr != nil {
-			err = fmt.Errorf("event handling error at line %d ('%s'): %w", lineNumber, line, processEventErr)
-			return err
+		if canCheckpoint && processed-lastCheckpoint >= simulator.CheckpointEvery && len(buffer.pending) == 0 && len(resolver.pending) == 0 {
+			if err = simulator.WriteCheckpoint(simulator.CheckpointPath, positioned.Position()); err != nil {
+				return processed, err
+			}
+			lastCheckpoint = processed
 		}
 	}
 
-	if scanErr := scanner.Err(); scanErr != nil {
-		if err == nil {
-			err = fmt.Errorf("error reading event file %s: %w", filePath, scanErr)
-		} else {
-			fmt.Fprintf(os.Stderr, "Additional error while scanning event file %s: %v (original error: %v)\n", filePath, scanErr, err)
+	count, err := simulator.processReleased(simulator.resolveStations(resolver, buffer.flush()), duplicates)
+	processed += count
+	if err != nil {
+		return processed, err
+	}
+	count, err = simulator.processReleased(resolver.flush(), duplicates)
+	processed += count
+	if err != nil {
+		return processed, err
+	}
+
+	if canCheckpoint {
+		if err = simulator.WriteCheckpoint(simulator.CheckpointPath, positioned.Position()); err != nil {
+			return processed, err
 		}
 	}
 
-	if err == nil {
-		simulator.CheckForNotStarted()
+	simulator.CheckForNotStarted()
+	if simulator.CloseUnfinished && canceled == nil {
+		simulator.CheckForUnfinished()
 	}
+	simulator.ValidateCompetitors()
+	if canceled != nil {
+		if positioned, ok := source.(positionedSource); ok {
+			return processed, fmt.Errorf("processing stopped after line %d (last event at %s): %w",
+				positioned.Position().LineNumber, domain.FormatTime(simulator.CurrentTime), canceled)
+		}
+		return processed, fmt.Errorf("processing stopped after %d events (last event at %s): %w",
+			processed, domain.FormatTime(simulator.CurrentTime), canceled)
+	}
+	return processed, nil
+}
 
-	return err
+// resolveStations passes released events through the station resolver and records conflicts
+func (simulator *Simulator) resolveStations(resolver *stationResolver, events []*domain.Event) []*domain.Event {
+	resolved, conflicts := resolver.push(events)
+	simulator.StationConflicts = append(simulator.StationConflicts, conflicts...)
+	return resolved
+}
+
+// processReleased processes events released by the reorder buffer, dropping duplicates and adding source context to errors
+func (simulator *Simulator) processReleased(events []*domain.Event, duplicates *duplicateFilter) (int, error) {
+	for i, event := range events {
+		if duplicates.enabled() {
+			isDuplicate, nearDuplicate := duplicates.check(event)
+			if isDuplicate {
+				simulator.DuplicatesDropped++
+				continue
+			}
+			if nearDuplicate != nil {
+				simulator.warn(WarningNearDuplicate, event, event.CompetitorID, "event %s is a near-duplicate of %s", event.MarshalLine(), nearDuplicate.MarshalLine())
+			}
+		}
+
+		if err := simulator.ProcessEvent(event); err != nil {
+			return i, fmt.Errorf("event handling error %s: %w", describeEventPosition(event), err)
+		}
+
+		if event.Comment != "" && simulator.KeepComments {
+			simulator.appendUntimedOutput(event.Comment)
+		}
+	}
+	return len(events), nil
+}
+
+// eventLine returns the input line of an event, or the event in input format if it was not read from a line
+func eventLine(event *domain.Event) string {
+	if event.RawLine == "" {
+		return event.MarshalLine()
+	}
+	return event.RawLine
+}
+
+// describeEventPosition describes where an event came from for error messages
+func describeEventPosition(event *domain.Event) string {
+	line := eventLine(event)
+	if event.LineNumber > 0 {
+		return fmt.Sprintf("at line %d ('%s')", event.LineNumber, line)
+	}
+	return fmt.Sprintf("for event '%s'", line)
+}
+
+// splitComment separates a '#' comment, at the start of the line or after any whitespace, from the event part of a
+// line
+func splitComment(rawLine string) (string, string) {
+	trimmed := strings.TrimSpace(rawLine)
+	if strings.HasPrefix(trimmed, "#") {
+		return "", trimmed
+	}
+
+	for offset := 0; ; {
+		commentIndex := strings.IndexByte(trimmed[offset:], '#')
+		if commentIndex < 0 {
+			return trimmed, ""
+		}
+		commentIndex += offset
+		if previous, _ := utf8.DecodeLastRuneInString(trimmed[:commentIndex]); unicode.IsSpace(previous) {
+			return strings.TrimSpace(trimmed[:commentIndex]), trimmed[commentIndex:]
+		}
+		offset = commentIndex + 1
+	}
 }
 
-// ProcessEvent processes a single event and updates the simulation state
+// ProcessEvent processes a single event, updates the simulation state and invokes the lifecycle callbacks
 func (simulator *Simulator) ProcessEvent(event *domain.Event) error {
-	simulator.CurrentTime = event.Timestamp
-	simulator.Events = append(simulator.Events, event)
+	simulator.stats.LinesProcessed++
+	competitor, competitorExists := simulator.Competitors[event.CompetitorID]
+	if !competitorExists {
+		return simulator.processEvent(event)
+	}
+	before := progressOf(competitor)
+	err := simulator.processEvent(event)
+	simulator.notifyChanges(competitor, before, event)
+	if simulator.ValidateState {
+		simulator.validateCompetitor(competitor, event)
+	}
+	return err
+}
 
-	if event.IsIncoming {
-		simulator.OutputLog = append(simulator.OutputLog, event.String())
+// processEvent updates the simulation state for a single event
+func (simulator *Simulator) processEvent(event *domain.Event) error {
+	simulator.CurrentTime = event.Timestamp
+	simulator.applyCutoff(event.Timestamp)
+	if len(simulator.OnlyCompetitors) > 0 && !slices.Contains(simulator.OnlyCompetitors, event.CompetitorID) {
+		return nil
+	}
+	if err := domain.ValidateEvent(event); err != nil {
+		return err
 	}
 
 	competitor, competitorExists := simulator.Competitors[event.CompetitorID]
+	if competitorExists && event.ID == domain.Started && simulator.startedByMassStart(competitor) {
+		return nil
+	}
+	// an EndLap after the finish, e.g. of a cooldown lap, is only warned about, even with strict transitions
+	cooldownLap := competitorExists && event.ID == domain.EndLap && competitor.Status == domain.StatusFinished
+	if competitorExists && simulator.StrictTransitions && !cooldownLap && !domain.TransitionAllowed(competitor.Status, event.ID) {
+		return &domain.TransitionError{CompetitorID: competitor.ID, Status: competitor.Status, Event: event}
+	}
+	simulator.Events = insertEvent(simulator.Events, event)
+	simulator.countEvent(event, false)
+	simulator.indexEvent(event)
+
+	handler, hasHandler := simulator.handlers[event.ID]
+	if event.IsIncoming || hasHandler {
+		line := simulator.formatEvent(event)
+		if competitorExists && event.ID == domain.SetStartTime && simulator.configFor(competitor).IsMassStart() {
+			line += ignoredStartTimeNote
+		}
+		simulator.appendOutput(line, event.Timestamp)
+	}
 
 	if event.ID == domain.Register {
 		if competitorExists {
-			fmt.Printf("Warning: Competitor %d re-registered in %s\n", event.CompetitorID, domain.FormatTime(event.Timestamp))
+			if competitor.Status != domain.StatusRegistered && competitor.Status != domain.StatusReadyToStart {
+				return fmt.Errorf("competitor %d registered again at %s in status %s", competitor.ID, domain.FormatTime(event.Timestamp), competitor.Status)
+			}
+			simulator.warn(WarningReRegistered, event, event.CompetitorID, "Competitor %d re-registered in %s (first registered in %s), keeping the start time and status",
+				event.CompetitorID, domain.FormatTime(event.Timestamp), domain.FormatTime(competitor.RegistrationTime))
+			competitor.RegistrationTime = event.Timestamp
+			competitor.LastEventTime = event.Timestamp
+			if len(event.ExtraParameters) > 0 {
+				competitor.Name = strings.Join(event.ExtraParameters, " ")
+			}
+			simulator.setAffiliation(competitor, event)
 		} else {
-			simulator.Competitors[event.CompetitorID] = domain.NewCompetitor(event.CompetitorID, event.Timestamp)
+			competitor = domain.NewCompetitor(event.CompetitorID, event.Timestamp)
+			competitor.Name = strings.Join(event.ExtraParameters, " ")
+			competitor.Group = simulator.groupFor(event)
+			competitor.Team = simulator.Config.TeamForBib(competitor.ID)
+			competitor.Category = simulator.Config.CategoryForBib(competitor.ID)
+			competitor.StartGroup = simulator.Config.StartGroupForBib(competitor.ID)
+			if nation := simulator.Config.NationForBib(competitor.ID); nation != "" {
+				simulator.setNation(competitor, nation, event)
+			}
+			simulator.setAffiliation(competitor, event)
+			simulator.seedStart(competitor, event)
+			simulator.seedMassStart(competitor)
+			simulator.Competitors[event.CompetitorID] = competitor
+			simulator.countStatusChange("", competitor.Status)
 		}
 		return nil
 	}
@@ -123,240 +509,520 @@ func (simulator *Simulator) ProcessEvent(event *domain.Event) error {
 		competitor.LastEventTime = event.Timestamp
 	}
 
+	if event.Group != "" && event.Group != competitor.Group {
+		simulator.warn(WarningGroupConflict, event, competitor.ID, "event for competitor %d names group '%s', but the competitor belongs to group '%s'",
+			competitor.ID, event.Group, competitor.Group)
+	}
+
+	if event.ID == domain.JuryDecision {
+		simulator.applyJuryDecision(competitor, event)
+		return nil
+	}
+	if competitor.Status == domain.StatusLapped {
+		simulator.warn(WarningEventAfterFinalStatus, event, competitor.ID, "Event %d for competitor %d ignored, the competitor was lapped and pulled from the course",
+			event.ID, competitor.ID)
+		return nil
+	}
+	if competitor.Status == domain.StatusNotFinished && competitor.DisqualificationReason.Code == domain.ReasonCutoff {
+		simulator.stats.EventsAfterCutoff++
+		simulator.warn(WarningEventAfterCutoff, event, competitor.ID, "Event %d for competitor %d ignored, the competitor was stopped at the cutoff %s",
+			event.ID, competitor.ID, domain.FormatTime(competitor.FinishTime))
+		return nil
+	}
+
+	if hasHandler {
+		return handler(simulator, competitor, event)
+	}
+
+	return simulator.applyEvent(competitor, simulator.configFor(competitor), event)
+}
+
+// applyEvent updates the state of a registered competitor for a built-in incoming event
+func (simulator *Simulator) applyEvent(competitor *domain.Competitor, cfg *config.Config, event *domain.Event) error {
 	switch event.ID {
 	case domain.SetStartTime:
-		if len(event.ExtraParameters) < 1 {
-			return fmt.Errorf("start time missing for SetStartTime event for competitor %d", competitor.ID)
+		simulator.setAffiliation(competitor, event)
+		if cfg.IsMassStart() {
+			simulator.warn(WarningIgnoredStartTime, event, competitor.ID, "SetStartTime event (%d) ignored, all competitors start together at %s in a mass start",
+				competitor.ID, domain.FormatTime(cfg.ParsedStart))
+			return nil
 		}
 		scheduledTime, err := domain.ParseTimeFromString(fmt.Sprintf("[%s]", event.ExtraParameters[0]))
 		if err != nil {
 			return fmt.Errorf("invalid start time format '%s' for competitor %d: %v", event.ExtraParameters[0], competitor.ID, err)
 		}
 		competitor.ScheduledStartTime = scheduledTime
+		if len(event.ExtraParameters) > 1 {
+			simulator.setStartGroup(competitor, event.ExtraParameters[1], event)
+		}
 
 	case domain.OnStartLine:
 		if competitor.Status == domain.StatusRegistered || competitor.Status == domain.StatusReadyToStart {
-			competitor.Status = domain.StatusReadyToStart
+			competitor.SetStatus(domain.StatusReadyToStart, event.Timestamp, event.ID)
 		} else {
-			fmt.Printf("Warning: OnStartLine event (%d) in unexpected status %s", competitor.ID, competitor.Status)
+			simulator.warnStyled(consoleStyle{prefix: "Warning: "}, WarningUnexpectedStatus, event, competitor.ID, "OnStartLine event (%d) in unexpected status %s", competitor.ID, competitor.Status)
 		}
 
 	case domain.Started:
 		if competitor.Status != domain.StatusReadyToStart && competitor.Status != domain.StatusRegistered {
-			fmt.Printf("Warning: Event Started (%d) in unexpected status %s (expected ReadyToStart or Registered)\n", competitor.ID, competitor.Status)
+			simulator.warn(WarningUnexpectedStatus, event, competitor.ID, "Event Started (%d) in unexpected status %s (expected ReadyToStart or Registered)", competitor.ID, competitor.Status)
 		}
 		if !competitor.ScheduledStartTime.IsZero() {
-			startDeadline := competitor.ScheduledStartTime.Add(simulator.Config.ParsedStartDelta)
+			startDeadline := competitor.ScheduledStartTime.Add(simulator.startDeltaFor(competitor))
 			if event.Timestamp.After(startDeadline) {
-				simulator.DisqualifyCompetitor(competitor, event.Timestamp, "NotStarted")
+				simulator.disqualifyCompetitor(competitor, event.Timestamp, domain.NewReason(domain.ReasonNotStarted, ""), event)
 				return nil
 			}
+			if event.Timestamp.Before(competitor.ScheduledStartTime) {
+				competitor.FalseStartMargin = competitor.ScheduledStartTime.Sub(event.Timestamp)
+				simulator.warn(WarningFalseStart, event, competitor.ID, "competitor %d started %s before the scheduled start %s.",
+					competitor.ID, domain.FormatDuration(competitor.FalseStartMargin), domain.FormatTime(competitor.ScheduledStartTime))
+				switch cfg.FalseStartPolicy {
+				case config.FalseStartPenalty:
+					competitor.TimePenalty += cfg.ParsedFalseStartPenalty
+				case config.FalseStartDisqualify:
+					startCompetitor(competitor, cfg, event.Timestamp)
+					simulator.disqualifyCompetitor(competitor, event.Timestamp, domain.NewReason(domain.ReasonFalseStart, ""), event)
+					return nil
+				}
+			}
+		}
+		startCompetitor(competitor, cfg, event.Timestamp)
+		if cfg.IsMassStart() {
+			simulator.startMass(event)
 		}
-		competitor.Status = domain.StatusStarted
-		competitor.ActualStartTime = event.Timestamp
-		competitor.CurrentLap = 1
-		competitor.CurrentLapStartTime = event.Timestamp
 
 	case domain.EnterFiringRange:
-		if competitor.TotalFiringRangesCompleted >= simulator.Config.FiringLines {
-			msg := fmt.Sprintf("competitor %d attempts to enter the firing line after completing all %d required lines (completed: %d)",
-				competitor.ID, simulator.Config.FiringLines, competitor.TotalFiringRangesCompleted)
-			fmt.Printf("Warning: %s\n", msg)
-			simulator.DisqualifyCompetitor(competitor, event.Timestamp, "Extra firing line")
+		if competitor.TotalFiringRangesCompleted >= cfg.FiringLines {
+			simulator.warn(WarningExtraFiringLine, event, competitor.ID, "competitor %d attempts to enter the firing line after completing all %d required lines (completed: %d)",
+				competitor.ID, cfg.FiringLines, competitor.TotalFiringRangesCompleted)
+			simulator.disqualifyCompetitor(competitor, event.Timestamp, domain.NewReason(domain.ReasonExtraFiringLine, ""), event)
 
 			return nil
 		}
 
-		actualRangeNumFromEvent := 0
-		if len(event.ExtraParameters) > 0 {
-			rangeNum, err := strconv.Atoi(event.ExtraParameters[0])
-			if err == nil {
-				actualRangeNumFromEvent = rangeNum
-			} else {
-				return fmt.Errorf("invalid milestone number '%s' in event 5 for competitor %d", event.ExtraParameters[0], competitor.ID)
-			}
-		} else {
-			return fmt.Errorf("missing milestone number in event 5 for competitor %d", competitor.ID)
-		}
+		actualRangeNumFromEvent, _ := strconv.Atoi(event.ExtraParameters[0])
 
 		expectedRangeGlobalNum := competitor.TotalFiringRangesCompleted + 1
 		if actualRangeNumFromEvent != expectedRangeGlobalNum {
-			fmt.Printf("Warning: competitor %d (ID %d) has reached milestone %d (by event), although the expected milestone was %d (completed: %d).\n",
+			simulator.warn(WarningUnexpectedFiringRange, event, competitor.ID, "competitor %d (ID %d) has reached milestone %d (by event), although the expected milestone was %d (completed: %d).",
 				competitor.ID, competitor.ID, actualRangeNumFromEvent, expectedRangeGlobalNum, competitor.TotalFiringRangesCompleted)
 
-			if actualRangeNumFromEvent <= 0 || actualRangeNumFromEvent > simulator.Config.FiringLines {
-				return fmt.Errorf("invalid milestone number %d from event for competitor %d (max: %d)", actualRangeNumFromEvent, competitor.ID, simulator.Config.FiringLines)
+			if actualRangeNumFromEvent <= 0 || actualRangeNumFromEvent > cfg.FiringLines {
+				return fmt.Errorf("invalid milestone number %d from event for competitor %d (max: %d)", actualRangeNumFromEvent, competitor.ID, cfg.FiringLines)
 			}
 		}
 
-		if actualRangeNumFromEvent > simulator.Config.FiringLines {
+		if actualRangeNumFromEvent > cfg.FiringLines {
 			return fmt.Errorf("competitor %d entered the %d milestone, which is more than the configured %d milestones for the race",
-				competitor.ID, actualRangeNumFromEvent, simulator.Config.FiringLines)
+				competitor.ID, actualRangeNumFromEvent, cfg.FiringLines)
 		}
 
 		if competitor.Status != domain.StatusStarted && competitor.Status != domain.StatusPenalized {
-			fmt.Printf("Warning: EnterFiringRange event (%d) in unexpected status %s (expected Started or Penalized)\n", competitor.ID, competitor.Status)
+			simulator.warn(WarningUnexpectedStatus, event, competitor.ID, "EnterFiringRange event (%d) in unexpected status %s (expected Started or Penalized)", competitor.ID, competitor.Status)
 		}
 
-		competitor.Status = domain.StatusFiring
+		competitor.SetStatus(domain.StatusFiring, event.Timestamp, event.ID)
 		competitor.HitsThisRange = 0
+		competitor.ShotsThisRange = 0
+		competitor.SpareRoundsThisRange = 0
+		competitor.TargetsHitThisRange = competitor.TargetsHitThisRange[:0]
+		competitor.ShotTimesThisRange = competitor.ShotTimesThisRange[:0]
 		competitor.LastFiringRangeEntered = actualRangeNumFromEvent
+		competitor.PositionThisRange = cfg.PositionForRange(actualRangeNumFromEvent)
+		competitor.RangeEnterTime = event.Timestamp
+		if len(event.ExtraParameters) > 1 {
+			position, _ := domain.ParseShootingPosition(event.ExtraParameters[1])
+			if competitor.PositionThisRange != domain.PositionUnknown && position != competitor.PositionThisRange {
+				simulator.warn(WarningPositionMismatch, event, competitor.ID, "competitor %d shoots range %d %s, but the firing schedule says %s.",
+					competitor.ID, actualRangeNumFromEvent, position, competitor.PositionThisRange)
+			}
+			competitor.PositionThisRange = position
+		}
 
 	case domain.HitTarget:
 		if competitor.Status != domain.StatusFiring {
-			fmt.Printf("Warning: HitTarget event (%d) out of range (status %s)\n", competitor.ID, competitor.Status)
+			simulator.warn(WarningUnexpectedStatus, event, competitor.ID, "HitTarget event (%d) out of range (status %s)", competitor.ID, competitor.Status)
 		} else {
 			competitor.HitsThisRange++
 			competitor.TotalHits++
+			target, _ := strconv.Atoi(event.ExtraParameters[0])
+			if !slices.Contains(competitor.TargetsHitThisRange, target) {
+				competitor.TargetsHitThisRange = append(competitor.TargetsHitThisRange, target)
+			}
+		}
+
+	case domain.ShotFired:
+		if competitor.Status != domain.StatusFiring {
+			simulator.warn(WarningUnexpectedStatus, event, competitor.ID, "ShotFired event (%d) out of range (status %s)", competitor.ID, competitor.Status)
+		} else {
+			competitor.ShotsThisRange++
+			competitor.ShotTimesThisRange = append(competitor.ShotTimesThisRange, event.Timestamp)
+		}
+
+	case domain.SpareRound:
+		if competitor.Status != domain.StatusFiring {
+			simulator.warn(WarningUnexpectedStatus, event, competitor.ID, "SpareRound event (%d) out of range (status %s)", competitor.ID, competitor.Status)
+		} else {
+			competitor.SpareRoundsThisRange++
 		}
 
 	case domain.LeaveFiringRange:
 		if competitor.Status != domain.StatusFiring {
-			fmt.Printf("Warning: LeaveFiringRange event (%d) in unexpected status %s (expected Firing)\n", competitor.ID, competitor.Status)
+			simulator.warn(WarningUnexpectedStatus, event, competitor.ID, "LeaveFiringRange event (%d) in unexpected status %s (expected Firing)", competitor.ID, competitor.Status)
 			competitor.LastFiringRangeEntered = 0
 			return nil
 		}
 
 		shotsThisRange := 0
+		spareRounds := 0
+		maxSpareRounds := cfg.MaxSpareRounds()
 		if competitor.LastFiringRangeEntered > 0 && competitor.LastFiringRangeEntered > competitor.TotalFiringRangesCompleted {
-			shotsThisRange = 5
+			shotsThisRange = DefaultShotsPerRange
+			if maxSpareRounds > 0 && (competitor.ShotsThisRange > DefaultShotsPerRange || competitor.SpareRoundsThisRange > 0) {
+				spareRounds = max(competitor.ShotsThisRange-DefaultShotsPerRange, competitor.SpareRoundsThisRange)
+				if spareRounds > maxSpareRounds {
+					simulator.warn(WarningTooManySpareRounds, event, competitor.ID, "competitor %d used %d spare rounds at range %d (at most %d allowed).",
+						competitor.ID, spareRounds, competitor.LastFiringRangeEntered, maxSpareRounds)
+					spareRounds = maxSpareRounds
+				}
+			} else if competitor.ShotsThisRange > 0 {
+				if competitor.ShotsThisRange != DefaultShotsPerRange {
+					simulator.warn(WarningShotCountMismatch, event, competitor.ID, "competitor %d fired %d shots at range %d (expected %d).",
+						competitor.ID, competitor.ShotsThisRange, competitor.LastFiringRangeEntered, DefaultShotsPerRange)
+				}
+				shotsThisRange = competitor.ShotsThisRange
+			}
 			competitor.TotalShots += shotsThisRange
 			competitor.TotalFiringRangesCompleted++
 		} else if competitor.LastFiringRangeEntered > 0 {
-			fmt.Printf("Warning: competitor %d left range %d, which may have already been processed or wasn't expected (completed: %d).\n",
+			simulator.warn(WarningRangeAlreadyProcessed, event, competitor.ID, "competitor %d left range %d, which may have already been processed or wasn't expected (completed: %d).",
 				competitor.ID, competitor.LastFiringRangeEntered, competitor.TotalFiringRangesCompleted)
 		}
 
 		misses := shotsThisRange - competitor.HitsThisRange
+		if maxSpareRounds > 0 && shotsThisRange > 0 {
+			// With spare rounds only the targets still standing after all shots are penalized
+			misses = DefaultShotsPerRange - len(competitor.TargetsHitThisRange)
+		}
+		competitor.TotalSpareRounds += spareRounds
 		if misses < 0 {
-			fmt.Printf("Warning: competitor %d recorded %d hits with %d shots at range %d.\n",
+			simulator.warn(WarningTooManyHits, event, competitor.ID, "competitor %d recorded %d hits with %d shots at range %d.",
 				competitor.ID, competitor.HitsThisRange, shotsThisRange, competitor.LastFiringRangeEntered)
 			misses = 0
 		}
 		competitor.MissesToPenalize += misses
+		if shotsThisRange > 0 {
+			if competitor.RangeEnterTime.IsZero() {
+				simulator.warn(WarningMissingRangeEntry, event, competitor.ID, "competitor %d left range %d without a recorded entry time, the time on the range is unknown.",
+					competitor.ID, competitor.LastFiringRangeEntered)
+			}
+			intervals, shootingTime := domain.ShotIntervals(competitor.ShotTimesThisRange)
+			competitor.RangeDetails = append(competitor.RangeDetails, domain.RangeDetail{
+				Range:         competitor.LastFiringRangeEntered,
+				Position:      competitor.PositionThisRange,
+				Hits:          competitor.HitsThisRange,
+				Shots:         shotsThisRange,
+				SpareRounds:   spareRounds,
+				Misses:        misses,
+				EnterTime:     competitor.RangeEnterTime,
+				LeaveTime:     event.Timestamp,
+				ShotIntervals: intervals,
+				ShootingTime:  shootingTime,
+			})
+			competitor.TotalRangeTime += competitor.RangeDetails[len(competitor.RangeDetails)-1].Duration()
+		}
+		if leg := competitor.CurrentLeg(); leg != nil {
+			leg.Hits += competitor.HitsThisRange
+			leg.Shots += shotsThisRange
+			leg.SpareRounds += spareRounds
+		}
 
 		if competitor.MissesToPenalize == 0 {
-			competitor.Status = domain.StatusStarted
+			competitor.SetStatus(domain.StatusStarted, event.Timestamp, event.ID)
 		}
 
 		competitor.HitsThisRange = 0
+		competitor.ShotsThisRange = 0
+		competitor.SpareRoundsThisRange = 0
+		competitor.TargetsHitThisRange = competitor.TargetsHitThisRange[:0]
+		competitor.ShotTimesThisRange = competitor.ShotTimesThisRange[:0]
 		competitor.LastFiringRangeEntered = 0
+		competitor.PositionThisRange = domain.PositionUnknown
+		competitor.RangeEnterTime = time.Time{}
 
 	case domain.EnterPenaltyLaps:
 		if competitor.Status != domain.StatusFiring && competitor.Status != domain.StatusStarted && competitor.Status != domain.StatusPenalized {
-			fmt.Printf("Warning: Event EnterPenaltyLaps (%d) in unexpected status %s\n", competitor.ID, competitor.Status)
+			simulator.warn(WarningUnexpectedStatus, event, competitor.ID, "Event EnterPenaltyLaps (%d) in unexpected status %s", competitor.ID, competitor.Status)
 		}
-		if simulator.Config.PenaltyLen <= 0 {
-			fmt.Printf("Warning: competitor %d entered the penalty laps, but their length is 0. Let's skip.\n", competitor.ID)
-			competitor.Status = domain.StatusStarted
+		if cfg.PenaltyLen <= 0 {
+			simulator.warn(WarningNoPenaltyLength, event, competitor.ID, "competitor %d entered the penalty laps, but their length is 0. Let's skip.", competitor.ID)
+			competitor.SetStatus(domain.StatusStarted, event.Timestamp, event.ID)
 			return nil
 		}
 		if competitor.MissesToPenalize == 0 {
-			fmt.Printf("Warning: competitor %d entered the penalty laps without any outstanding penalties. We'll let him through.\n", competitor.ID)
-			competitor.Status = domain.StatusStarted
+			simulator.warn(WarningNoOutstandingPenalties, event, competitor.ID, "competitor %d entered the penalty laps without any outstanding penalties. We'll let him through.", competitor.ID)
+			competitor.SetStatus(domain.StatusStarted, event.Timestamp, event.ID)
 			return nil
 		}
-		competitor.Status = domain.StatusPenalized
+		competitor.SetStatus(domain.StatusPenalized, event.Timestamp, event.ID)
 		competitor.PenaltyStartTime = event.Timestamp
+		simulator.recordRangeTransition(competitor, event)
 
 	case domain.LeavePenaltyLaps:
 		if competitor.Status != domain.StatusPenalized {
-			fmt.Printf("Warning: LeavePenaltyLaps event (%d) in unexpected status %s (expected Penalized)\n", competitor.ID, competitor.Status)
+			simulator.warn(WarningUnexpectedStatus, event, competitor.ID, "LeavePenaltyLaps event (%d) in unexpected status %s (expected Penalized)", competitor.ID, competitor.Status)
 			if competitor.PenaltyStartTime.IsZero() {
-				fmt.Printf("Warning: competitor %d (status %s) has no penalty lap entry time, LeavePenaltyLaps event ignored for time calculation.\n", competitor.ID, competitor.Status)
+				simulator.warn(WarningMissingPenaltyStart, event, competitor.ID, "competitor %d (status %s) has no penalty lap entry time, LeavePenaltyLaps event ignored for time calculation.", competitor.ID, competitor.Status)
 				return nil
 			}
 		}
 
 		if competitor.PenaltyStartTime.IsZero() {
-			fmt.Printf("Error/Warning: competitor %d left penalty laps but entry time (PenaltyStartTime) was not recorded. Cannot calculate penalty duration.\n", competitor.ID)
+			simulator.warnStyled(errorWarningStyle, WarningMissingPenaltyStart, event, competitor.ID, "competitor %d left penalty laps but entry time (PenaltyStartTime) was not recorded. Cannot calculate penalty duration.", competitor.ID)
 		} else {
 			penaltyDuration := event.Timestamp.Sub(competitor.PenaltyStartTime)
 			if penaltyDuration < 0 {
-				fmt.Printf("Error/Warning: Negative penalty lap duration (%s) for competitor %d. Ignored.\n", domain.FormatDuration(penaltyDuration), competitor.ID)
+				simulator.warnStyled(errorWarningStyle, WarningNegativePenaltyDuration, event, competitor.ID, "Negative penalty lap duration (%s) for competitor %d. Ignored.", domain.FormatDuration(penaltyDuration), competitor.ID)
 			} else {
 				competitor.TotalPenaltyTime += penaltyDuration
+				if leg := competitor.CurrentLeg(); leg != nil {
+					leg.PenaltyTime += penaltyDuration
+				}
+				competitor.PenaltySessions = append(competitor.PenaltySessions, domain.PenaltySession{
+					Lap:        competitor.CurrentLap,
+					Range:      competitor.LastCompletedRange(),
+					Loops:      competitor.MissesToPenalize,
+					StartTime:  competitor.PenaltyStartTime,
+					EndTime:    event.Timestamp,
+					Speed:      domain.CalculateSpeed(float64(competitor.MissesToPenalize)*cfg.PenaltyLen, penaltyDuration),
+					Transition: competitor.PenaltyTransition,
+				})
 			}
 			competitor.PenaltyStartTime = time.Time{}
+			competitor.PenaltyTransition = 0
 		}
 
 		competitor.TotalPenaltyLaps += competitor.MissesToPenalize
+		if leg := competitor.CurrentLeg(); leg != nil {
+			leg.PenaltyLaps += competitor.MissesToPenalize
+		}
 		competitor.MissesToPenalize = 0
 
-		competitor.Status = domain.StatusStarted
+		competitor.SetStatus(domain.StatusStarted, event.Timestamp, event.ID)
 
 	case domain.EndLap:
+		if competitor.Status == domain.StatusFinished {
+			simulator.warn(WarningEventAfterFinalStatus, event, competitor.ID, "EndLap event (%d) ignored, the competitor already finished at %s (e.g. a cooldown lap)",
+				competitor.ID, domain.FormatTime(competitor.FinishTime))
+			return nil
+		}
+		if competitor.Status == domain.StatusNotFinished || competitor.Status == domain.StatusNotStarted || competitor.Status == domain.StatusDisqualified {
+			simulator.warn(WarningEventAfterFinalStatus, event, competitor.ID, "EndLap event (%d) for competitor in final status %s ignored", competitor.ID, competitor.Status)
+			return nil
+		}
+		if competitor.CurrentLap > cfg.TotalLaps() {
+			if simulator.StrictTransitions {
+				return fmt.Errorf("EndLap event for competitor %d on lap %d, but the race has %d laps", competitor.ID, competitor.CurrentLap, cfg.TotalLaps())
+			}
+			simulator.warn(WarningUnexpectedStatus, event, competitor.ID, "EndLap event (%d) on lap %d ignored, the race has %d laps", competitor.ID, competitor.CurrentLap, cfg.TotalLaps())
+			return nil
+		}
+		if !simulator.StrictTransitions {
+			if err := simulator.recoverMissingExits(competitor, cfg, event); err != nil {
+				return err
+			}
+		}
 		if competitor.Status != domain.StatusStarted {
-			fmt.Printf("Warning: EndLap event (%d) in unexpected status %s (expected Started)\n", competitor.ID, competitor.Status)
+			simulator.warn(WarningUnexpectedStatus, event, competitor.ID, "EndLap event (%d) in unexpected status %s (expected Started)", competitor.ID, competitor.Status)
+		}
+		if competitor.CurrentLap < 1 {
+			return nil
 		}
 
 		lapDuration := event.Timestamp.Sub(competitor.CurrentLapStartTime)
-		lapSpeed := domain.CalculateSpeed(simulator.Config.LapLen, lapDuration)
+		lapSpeed := domain.CalculateSpeed(cfg.LapLen, lapDuration)
 
 		if len(competitor.LapDetails) < competitor.CurrentLap {
 			competitor.LapDetails = append(competitor.LapDetails, make([]domain.LapDetail, competitor.CurrentLap-len(competitor.LapDetails))...)
 		}
 		competitor.LapDetails[competitor.CurrentLap-1] = domain.LapDetail{
-			Duration: lapDuration,
-			Speed:    lapSpeed,
+			Duration:  lapDuration,
+			Speed:     lapSpeed,
+			Elapsed:   competitor.ElapsedTime(event.Timestamp),
+			StartTime: competitor.CurrentLapStartTime,
+			EndTime:   event.Timestamp,
 		}
 
-		if competitor.CurrentLap >= simulator.Config.Laps {
-			if competitor.TotalFiringRangesCompleted < simulator.Config.FiringLines {
-				reason := fmt.Sprintf("Not all %d firing ranges completed (completed %d)", simulator.Config.FiringLines, competitor.TotalFiringRangesCompleted)
-				fmt.Printf("Warning/Error: competitor %d (ID %d) is finishing but %s.\n", competitor.ID, competitor.ID, reason)
+		if competitor.CurrentLap >= cfg.TotalLaps() {
+			if competitor.TotalFiringRangesCompleted < cfg.FiringLines {
+				missed := make([]string, 0, cfg.FiringLines-competitor.TotalFiringRangesCompleted)
+				for rangeNum := competitor.TotalFiringRangesCompleted + 1; rangeNum <= cfg.FiringLines; rangeNum++ {
+					missed = append(missed, strconv.Itoa(rangeNum))
+				}
+				reason := fmt.Sprintf("Not all %d firing ranges completed (completed %d, missed %s)", cfg.FiringLines, competitor.TotalFiringRangesCompleted, strings.Join(missed, ", "))
+				switch cfg.MissedRangePolicy {
+				case config.MissedRangeDisqualify:
+					simulator.warnStyled(warningErrorStyle, WarningIncompleteFiringRanges, event, competitor.ID, "competitor %d (ID %d) is finishing but %s. Disqualified.", competitor.ID, competitor.ID, reason)
+					simulator.disqualifyCompetitor(competitor, event.Timestamp, domain.NewReason(domain.ReasonMissedFiringRange, ""), event)
+					return nil
+				case config.MissedRangeNotFinished:
+					simulator.warnStyled(warningErrorStyle, WarningIncompleteFiringRanges, event, competitor.ID, "competitor %d (ID %d) is finishing but %s. Status: NotFinished.", competitor.ID, competitor.ID, reason)
+					simulator.markNotFinished(competitor, event.Timestamp, domain.NewReason(domain.ReasonMissedFiringRange, ""))
+					return nil
+				default:
+					simulator.warnStyled(warningErrorStyle, WarningIncompleteFiringRanges, event, competitor.ID, "competitor %d (ID %d) is finishing but %s.", competitor.ID, competitor.ID, reason)
+				}
 			}
-			simulator.FinishCompetitor(competitor, event.Timestamp)
+			simulator.finishCompetitor(competitor, event.Timestamp)
 		} else {
 			competitor.CurrentLap++
 			competitor.CurrentLapStartTime = event.Timestamp
 		}
+		if cfg.PullLapped {
+			simulator.pullLappedCompetitors(competitor, event)
+		}
 
 	case domain.CannotContinue:
 		if competitor.Status != domain.StatusFinished && competitor.Status != domain.StatusNotStarted && competitor.Status != domain.StatusDisqualified {
-			competitor.Status = domain.StatusNotFinished
+			competitor.SetStatus(domain.StatusNotFinished, event.Timestamp, event.ID)
 			competitor.FinishTime = event.Timestamp
-			reason := "Reason not specified"
-			if len(event.ExtraParameters) > 0 {
-				reason = strings.Join(event.ExtraParameters, " ")
-			}
-			competitor.DisqualificationReason = reason
-
-			if competitor.TotalPenaltyLaps > 0 && simulator.Config.PenaltyLen > 0 {
-				totalPenaltyDistance := float64(competitor.TotalPenaltyLaps) * simulator.Config.PenaltyLen
-				avgPenaltySpeed := domain.CalculateSpeed(totalPenaltyDistance, competitor.TotalPenaltyTime)
-				competitor.PenaltyDetails = domain.PenaltyDetail{
-					TotalDuration: competitor.TotalPenaltyTime,
-					AverageSpeed:  avgPenaltySpeed,
-				}
-			}
+			competitor.DisqualificationReason = domain.OtherReason(strings.Join(event.ExtraParameters, " "))
+			closeOpenSessions(competitor, event.Timestamp)
+
+			setPenaltyDetails(competitor, cfg)
+			setAverageSpeed(competitor, cfg)
 		} else {
-			fmt.Printf("Warning: CannotContinue event (%d) for competitor in final status %s\n", competitor.ID, competitor.Status)
+			simulator.warn(WarningEventAfterFinalStatus, event, competitor.ID, "CannotContinue event (%d) for competitor in final status %s", competitor.ID, competitor.Status)
+		}
+	case domain.EquipmentIssue:
+		if competitor.Status == domain.StatusFinished || competitor.Status == domain.StatusNotFinished || competitor.Status == domain.StatusNotStarted || competitor.Status == domain.StatusDisqualified {
+			simulator.warn(WarningEventAfterFinalStatus, event, competitor.ID, "EquipmentIssue event (%d) for competitor in final status %s", competitor.ID, competitor.Status)
+			return nil
+		}
+		competitor.Incidents = append(competitor.Incidents, domain.Incident{
+			Time:        event.Timestamp,
+			Description: strings.Join(event.ExtraParameters, " "),
+		})
+
+	case domain.SplitPoint:
+		splitIndex, _ := strconv.Atoi(event.ExtraParameters[0])
+		if competitor.Status != domain.StatusStarted && competitor.Status != domain.StatusPenalized {
+			simulator.warn(WarningUnexpectedStatus, event, competitor.ID, "SplitPoint event (%d) in unexpected status %s (expected Started or Penalized)", competitor.ID, competitor.Status)
+		}
+		if competitor.CurrentLap < 1 {
+			simulator.warn(WarningSplitBeforeLap, event, competitor.ID, "competitor %d passed split point %d before starting a lap. Ignored.", competitor.ID, splitIndex)
+			return nil
+		}
+
+		if len(competitor.LapSplits) < competitor.CurrentLap {
+			competitor.LapSplits = append(competitor.LapSplits, make([][]domain.SplitDetail, competitor.CurrentLap-len(competitor.LapSplits))...)
+		}
+		lapSplits := competitor.LapSplits[competitor.CurrentLap-1]
+		splitDuration := event.Timestamp.Sub(competitor.CurrentLapStartTime)
+		if len(lapSplits) > 0 {
+			previous := lapSplits[len(lapSplits)-1]
+			if splitIndex <= previous.Index || splitDuration <= previous.Duration {
+				simulator.warn(WarningSplitOutOfOrder, event, competitor.ID, "split point %d of competitor %d on lap %d is not after split point %d. Ignored.",
+					splitIndex, competitor.ID, competitor.CurrentLap, previous.Index)
+				return nil
+			}
 		}
+		competitor.LapSplits[competitor.CurrentLap-1] = append(lapSplits, domain.SplitDetail{
+			Index:    splitIndex,
+			Duration: splitDuration,
+		})
+
+	case domain.Withdrawn:
+		reason := "Reason not specified"
+		if len(event.ExtraParameters) > 0 {
+			reason = strings.Join(event.ExtraParameters, " ")
+		}
+		simulator.withdrawCompetitor(competitor, event.Timestamp, reason)
+
+	case domain.Exchange:
+		simulator.exchangeLeg(competitor, cfg, event)
+
 	default:
-		fmt.Printf("Warning: Unknown incoming event ID %d for competitor %d\n", event.ID, competitor.ID)
+		simulator.warn(WarningUnknownEvent, event, competitor.ID, "Unknown incoming event ID %d for competitor %d", event.ID, competitor.ID)
 	}
 
 	return nil
 }
 
+// recoverMissingExits closes the firing range or penalty loops a competitor is still in when their lap ends, as if
+// the dropped LeaveFiringRange or LeavePenaltyLaps event had arrived with the EndLap event
+func (simulator *Simulator) recoverMissingExits(competitor *domain.Competitor, cfg *config.Config, endLap *domain.Event) error {
+	if competitor.Status == domain.StatusFiring {
+		simulator.warn(WarningRecoveredRangeExit, endLap, competitor.ID, "competitor %d ended lap %d on firing range %d without leaving it, the range is closed with %d hits.",
+			competitor.ID, competitor.CurrentLap, competitor.LastFiringRangeEntered, competitor.HitsThisRange)
+		if err := simulator.applyEvent(competitor, cfg, simulator.impliedEvent(endLap, domain.LeaveFiringRange)); err != nil {
+			return err
+		}
+		if competitor.Status == domain.StatusFiring {
+			simulator.warn(WarningRecoveredRangeExit, endLap, competitor.ID, "competitor %d did not run the %d penalty loops of the range, they remain outstanding.",
+				competitor.ID, competitor.MissesToPenalize)
+			competitor.SetStatus(domain.StatusStarted, endLap.Timestamp, endLap.ID)
+		}
+	}
+	if competitor.Status == domain.StatusPenalized {
+		simulator.warn(WarningRecoveredPenaltyExit, endLap, competitor.ID, "competitor %d ended lap %d in the penalty loops without leaving them, the loops are closed at the end of the lap.",
+			competitor.ID, competitor.CurrentLap)
+		return simulator.applyEvent(competitor, cfg, simulator.impliedEvent(endLap, domain.LeavePenaltyLaps))
+	}
+	return nil
+}
+
+// impliedEvent creates an event that was not reported but is implied by another one; it is applied to the
+// competitor without being recorded
+func (simulator *Simulator) impliedEvent(cause *domain.Event, id domain.EventID) *domain.Event {
+	return &domain.Event{
+		Timestamp:    cause.Timestamp,
+		ID:           id,
+		CompetitorID: cause.CompetitorID,
+		IsIncoming:   true,
+		LineNumber:   cause.LineNumber,
+		RawLine:      cause.RawLine,
+	}
+}
+
+// startCompetitor puts a competitor on the first lap
+func startCompetitor(competitor *domain.Competitor, cfg *config.Config, startTime time.Time) {
+	competitor.SetStatus(domain.StatusStarted, startTime, domain.Started)
+	competitor.ActualStartTime = startTime
+	competitor.CurrentLap = 1
+	competitor.CurrentLapStartTime = startTime
+	if cfg.IsRelay() {
+		competitor.Legs = append(competitor.Legs[:0], domain.LegDetail{Leg: 1, StartTime: startTime})
+	}
+}
+
 // FinishCompetitor handles the competitor's finish
 func (simulator *Simulator) FinishCompetitor(competitor *domain.Competitor, finishTime time.Time) {
+	before := progressOf(competitor)
+	if finishEvent := simulator.finishCompetitor(competitor, finishTime); finishEvent != nil {
+		simulator.notifyChanges(competitor, before, finishEvent)
+	}
+}
+
+// finishCompetitor handles the competitor's finish and returns the generated Finished event, or nil if the competitor already has a final status
+func (simulator *Simulator) finishCompetitor(competitor *domain.Competitor, finishTime time.Time) *domain.Event {
 	if competitor.Status == domain.StatusFinished || competitor.Status == domain.StatusNotFinished || competitor.Status == domain.StatusNotStarted || competitor.Status == domain.StatusDisqualified {
-		return
+		return nil
 	}
 
-	competitor.Status = domain.StatusFinished
+	competitor.SetStatus(domain.StatusFinished, finishTime, domain.Finished)
 	competitor.FinishTime = finishTime
-
-	if competitor.TotalPenaltyLaps > 0 && simulator.Config.PenaltyLen > 0 {
-		totalPenaltyDistance := float64(competitor.TotalPenaltyLaps) * simulator.Config.PenaltyLen
-		avgPenaltySpeed := domain.CalculateSpeed(totalPenaltyDistance, competitor.TotalPenaltyTime)
-		competitor.PenaltyDetails = domain.PenaltyDetail{
-			TotalDuration: competitor.TotalPenaltyTime,
-			AverageSpeed:  avgPenaltySpeed,
-		}
+	if rangeHits := competitor.RangeHits(); rangeHits != competitor.TotalHits {
+		simulator.warn(WarningRangeHitsMismatch, nil, competitor.ID, "competitor %d has %d hits in total, but %d hits on completed firing ranges.",
+			competitor.ID, competitor.TotalHits, rangeHits)
 	}
+	if leg := competitor.CurrentLeg(); leg != nil && leg.EndTime.IsZero() {
+		leg.EndTime = finishTime
+	}
+
+	setPenaltyDetails(competitor, simulator.configFor(competitor))
+	setAverageSpeed(competitor, simulator.configFor(competitor))
 
 	finishEvent := &domain.Event{
 		Timestamp:    finishTime,
@@ -364,30 +1030,71 @@ func (simulator *Simulator) FinishCompetitor(competitor *domain.Competitor, fini
 		CompetitorID: competitor.ID,
 		IsIncoming:   false,
 	}
-	simulator.Events = append(simulator.Events, finishEvent)
-	simulator.OutputLog = append(simulator.OutputLog, finishEvent.String())
+	simulator.recordGenerated(finishEvent)
+	return finishEvent
 }
 
 // DisqualifyCompetitor handles competitor disqualification
-func (simulator *Simulator) DisqualifyCompetitor(competitor *domain.Competitor, dqTime time.Time, reason string) {
+func (simulator *Simulator) DisqualifyCompetitor(competitor *domain.Competitor, dqTime time.Time, reason domain.DisqualificationReason) {
+	before := progressOf(competitor)
+	if dqEvent := simulator.disqualifyCompetitor(competitor, dqTime, reason, nil); dqEvent != nil {
+		simulator.notifyChanges(competitor, before, dqEvent)
+	}
+}
+
+// disqualifyCompetitor handles competitor disqualification caused by the trigger event (nil if not caused by an event)
+// and returns the Disqualified event, or nil if the competitor already has a final status
+func (simulator *Simulator) disqualifyCompetitor(competitor *domain.Competitor, dqTime time.Time, reason domain.DisqualificationReason, trigger *domain.Event) *domain.Event {
 	if competitor.Status == domain.StatusFinished || competitor.Status == domain.StatusNotFinished || competitor.Status == domain.StatusNotStarted || competitor.Status == domain.StatusDisqualified {
-		return
+		return nil
 	}
 
 	competitor.FinishTime = dqTime
 
-	if reason == "NotStarted" {
-		competitor.Status = domain.StatusNotStarted
+	if reason.Code == domain.ReasonNotStarted {
+		competitor.SetStatus(domain.StatusNotStarted, dqTime, domain.Disqualified)
 	} else {
-		competitor.Status = domain.StatusDisqualified
+		competitor.SetStatus(domain.StatusDisqualified, dqTime, domain.Disqualified)
 	}
 	competitor.DisqualificationReason = reason
+	competitor.Disqualification = domain.Disqualification{Time: dqTime}
+	if trigger != nil {
+		competitor.Disqualification.Trigger = eventLine(trigger)
+	}
+	closeOpenSessions(competitor, dqTime)
+
+	return simulator.emitDisqualifiedEvent(competitor, dqTime, reason)
+}
+
+// WithdrawCompetitor marks a competitor who withdrew before the start as NotStarted
+func (simulator *Simulator) WithdrawCompetitor(competitor *domain.Competitor, withdrawTime time.Time, reason string) {
+	before := progressOf(competitor)
+	if dqEvent := simulator.withdrawCompetitor(competitor, withdrawTime, reason); dqEvent != nil {
+		simulator.notifyChanges(competitor, before, dqEvent)
+	}
+}
+
+// withdrawCompetitor marks a competitor who withdrew before the start as NotStarted and returns the Disqualified event, or nil if the status did not allow it
+func (simulator *Simulator) withdrawCompetitor(competitor *domain.Competitor, withdrawTime time.Time, reason string) *domain.Event {
+	if competitor.Status != domain.StatusRegistered && competitor.Status != domain.StatusReadyToStart {
+		simulator.warn(WarningUnexpectedStatus, nil, competitor.ID, "Withdrawn event (%d) in unexpected status %s (expected Registered or ReadyToStart)", competitor.ID, competitor.Status)
+		return nil
+	}
+
+	competitor.SetStatus(domain.StatusNotStarted, withdrawTime, domain.Withdrawn)
+	competitor.FinishTime = withdrawTime
+	competitor.DisqualificationReason = domain.NewReason(domain.ReasonNotStarted, reason)
+
+	return simulator.emitDisqualifiedEvent(competitor, withdrawTime, competitor.DisqualificationReason)
+}
 
+// emitDisqualifiedEvent adds the outgoing Disqualified event for a competitor unless one was already generated, and returns it
+func (simulator *Simulator) emitDisqualifiedEvent(competitor *domain.Competitor, dqTime time.Time, reason domain.DisqualificationReason) *domain.Event {
 	dqEvent := &domain.Event{
 		Timestamp:       dqTime,
 		ID:              domain.Disqualified,
 		CompetitorID:    competitor.ID,
-		ExtraParameters: []string{reason},
+		ExtraParameters: reason.Parameters(),
 		IsIncoming:      false,
 	}
 
@@ -400,9 +1107,9 @@ func (simulator *Simulator) DisqualifyCompetitor(competitor *domain.Competitor,
 	}
 
 	if !alreadyDQEventExists {
-		simulator.Events = append(simulator.Events, dqEvent)
-		simulator.OutputLog = append(simulator.OutputLog, dqEvent.String())
+		simulator.recordGenerated(dqEvent)
 	}
+	return dqEvent
 }
 
 // CheckForNotStarted checks for athletes who were supposed to start but did not do so on time
@@ -412,19 +1119,81 @@ func (simulator *Simulator) CheckForNotStarted() {
 			competitor.ActualStartTime.IsZero() &&
 			!competitor.ScheduledStartTime.IsZero() {
 
-			startDeadline := competitor.ScheduledStartTime.Add(simulator.Config.ParsedStartDelta)
+			startDeadline := competitor.ScheduledStartTime.Add(simulator.startDeltaFor(competitor))
 
-			if simulator.CurrentTime.After(startDeadline) {
+			if now := simulator.now(); now.After(startDeadline) {
 				if competitor.Status != domain.StatusNotFinished && competitor.Status != domain.StatusDisqualified {
-					fmt.Printf("Info: competitor %d (ID %d) did not start by %s (deadline %s). Status: NotStarted.\n",
-						competitor.ID, competitor.ID, domain.FormatTime(simulator.CurrentTime), domain.FormatTime(startDeadline))
-					simulator.DisqualifyCompetitor(competitor, startDeadline, "NotStarted")
+					simulator.warnStyled(infoStyle, WarningMissedStart, nil, competitor.ID, "competitor %d (ID %d) did not start by %s (deadline %s). Status: NotStarted.",
+						competitor.ID, competitor.ID, domain.FormatTime(now), domain.FormatTime(startDeadline))
+					simulator.DisqualifyCompetitor(competitor, startDeadline, domain.NewReason(domain.ReasonNotStarted, ""))
 				}
 			}
 		}
 	}
 }
 
+// CheckForUnfinished marks competitors who are still on the course as NotFinished at their last recorded event,
+// e.g. because their chip failed at the finish
+func (simulator *Simulator) CheckForUnfinished() {
+	ids := make([]int, 0)
+	for id, competitor := range simulator.Competitors {
+		if competitor.Status == domain.StatusStarted || competitor.Status == domain.StatusFiring || competitor.Status == domain.StatusPenalized {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		competitor := simulator.Competitors[id]
+		before := progressOf(competitor)
+		simulator.warn(WarningNoFinish, nil, competitor.ID, "competitor %d has no finish recorded (last event at %s). Status: NotFinished.",
+			competitor.ID, domain.FormatTime(competitor.LastEventTime))
+		notFinishedEvent := simulator.markNotFinished(competitor, competitor.LastEventTime, domain.OtherReason("No finish recorded"))
+		simulator.notifyChanges(competitor, before, notFinishedEvent)
+	}
+}
+
+// markNotFinished classifies a competitor on the course as NotFinished and records the generated NotFinished event
+func (simulator *Simulator) markNotFinished(competitor *domain.Competitor, at time.Time, reason domain.DisqualificationReason) *domain.Event {
+	competitor.SetStatus(domain.StatusNotFinished, at, domain.NotFinished)
+	competitor.FinishTime = at
+	competitor.DisqualificationReason = reason
+	closeOpenSessions(competitor, at)
+	setPenaltyDetails(competitor, simulator.configFor(competitor))
+	setAverageSpeed(competitor, simulator.configFor(competitor))
+
+	notFinishedEvent := &domain.Event{
+		Timestamp:       at,
+		ID:              domain.NotFinished,
+		CompetitorID:    competitor.ID,
+		ExtraParameters: reason.Parameters(),
+		IsIncoming:      false,
+	}
+	simulator.recordGenerated(notFinishedEvent)
+	return notFinishedEvent
+}
+
+// ValidateCompetitors checks the invariants of every competitor and records each violation as a warning
+func (simulator *Simulator) ValidateCompetitors() {
+	ids := make([]int, 0, len(simulator.Competitors))
+	for id := range simulator.Competitors {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		simulator.validateCompetitor(simulator.Competitors[id], nil)
+	}
+}
+
+// validateCompetitor records a warning for every violated invariant of the competitor after the event (nil at the end of the input)
+func (simulator *Simulator) validateCompetitor(competitor *domain.Competitor, event *domain.Event) {
+	cfg := simulator.configFor(competitor)
+	for _, violation := range competitor.Validate(domain.Limits{Laps: cfg.TotalLaps(), FiringLines: cfg.FiringLines}) {
+		simulator.warn(WarningInvariantViolation, event, competitor.ID, "competitor %d is in an inconsistent state: %v", competitor.ID, violation)
+	}
+}
+
 // GetSortedCompetitors returns a sorted list of athletes for the report
 func (simulator *Simulator) GetSortedCompetitors() []*domain.Competitor {
 	competitorsList := make([]*domain.Competitor, 0, len(simulator.Competitors))
@@ -432,6 +1201,7 @@ func (simulator *Simulator) GetSortedCompetitors() []*domain.Competitor {
 		competitorsList = append(competitorsList, c)
 	}
 
+	finishOrder := simulator.finishOrder()
 	sort.SliceStable(competitorsList, func(i, j int) bool {
 		c1 := competitorsList[i]
 		c2 := competitorsList[j]
@@ -454,6 +1224,9 @@ func (simulator *Simulator) GetSortedCompetitors() []*domain.Competitor {
 				if t1 != t2 {
 					return t1 < t2
 				}
+				if finishOrder != nil && finishOrder[c1.ID] != finishOrder[c2.ID] {
+					return finishOrder[c1.ID] < finishOrder[c2.ID]
+				}
 				return c1.ID < c2.ID
 			}
 			if ok1 && !ok2 {
@@ -467,14 +1240,16 @@ func (simulator *Simulator) GetSortedCompetitors() []*domain.Competitor {
 
 		statusRank := func(status domain.CompetitorStatus) int {
 			switch status {
-			case domain.StatusNotFinished:
+			case domain.StatusLapped:
 				return 1
-			case domain.StatusNotStarted:
+			case domain.StatusNotFinished:
 				return 2
-			case domain.StatusDisqualified:
+			case domain.StatusNotStarted:
 				return 3
-			default:
+			case domain.StatusDisqualified:
 				return 4
+			default:
+				return 5
 			}
 		}
 
@@ -484,6 +1259,19 @@ func (simulator *Simulator) GetSortedCompetitors() []*domain.Competitor {
 		if rank1 != rank2 {
 			return rank1 < rank2
 		}
+		if c1.Status == domain.StatusLapped && !c1.FinishTime.Equal(c2.FinishTime) {
+			// Competitors lapped later were pulled further along the course
+			return c1.FinishTime.After(c2.FinishTime)
+		}
+		if c1.Status == domain.StatusNotFinished {
+			// Competitors who got further along the course, or later, rank higher
+			if c1.Distance != c2.Distance {
+				return c1.Distance > c2.Distance
+			}
+			if !c1.LastEventTime.Equal(c2.LastEventTime) {
+				return c1.LastEventTime.After(c2.LastEventTime)
+			}
+		}
 
 		return c1.ID < c2.ID
 	})
@@ -0,0 +1,76 @@
+package processing
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"biathlonPrototype/internal/domain"
+)
+
+func TestCooldownLapAfterFinishIsAWarning(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		simulator := newTestSimulator(t)
+		simulator.StrictTransitions = strict
+		mustProcess(t, simulator, finishedRace)
+
+		cooldown := parseEvents(t, "[10:30:00.000] 10 1")[0]
+		if err := simulator.ProcessEvent(cooldown); err != nil {
+			t.Fatalf("strict %v: cooldown lap: %v", strict, err)
+		}
+
+		competitor := simulator.Competitors[1]
+		if competitor.Status != domain.StatusFinished || len(competitor.LapDetails) != 2 {
+			t.Errorf("strict %v: status %s with %d laps, want Finished with 2", strict, competitor.Status, len(competitor.LapDetails))
+		}
+		if got := competitor.FinishTime.Format(domain.TimeLayout); got != "10:20:00.000" {
+			t.Errorf("strict %v: finish time %s", strict, got)
+		}
+		codes := warningCodes(simulator)
+		if len(codes) != 1 || codes[0] != WarningEventAfterFinalStatus {
+			t.Errorf("strict %v: warnings %v, want one %s", strict, codes, WarningEventAfterFinalStatus)
+		}
+	}
+}
+
+func TestStrictTransitionsRejectOtherEventsAfterFinish(t *testing.T) {
+	simulator := newTestSimulator(t)
+	simulator.StrictTransitions = true
+	mustProcess(t, simulator, finishedRace)
+
+	err := simulator.ProcessEvent(parseEvents(t, "[10:30:00.000] 5 1 1")[0])
+	var transitionError *domain.TransitionError
+	if !errors.As(err, &transitionError) || transitionError.Status != domain.StatusFinished {
+		t.Errorf("err = %v, want a TransitionError in status Finished", err)
+	}
+}
+
+func TestStrictTransitionsAcceptTheExampleRace(t *testing.T) {
+	strict := newTestSimulator(t)
+	strict.StrictTransitions = true
+	mustProcess(t, strict, readExample(t))
+
+	lenient := newTestSimulator(t)
+	mustProcess(t, lenient, readExample(t))
+	if !slices.Equal(strict.OutputLog, lenient.OutputLog) {
+		t.Error("strict transitions changed the output of the example race")
+	}
+}
+
+func TestStrictTransitionsRejectAHitBeforeTheRange(t *testing.T) {
+	simulator := newTestSimulator(t)
+	simulator.StrictTransitions = true
+	mustProcess(t, simulator, `
+		[09:00:00.000] 1 1
+		[09:59:00.000] 3 1
+		[10:00:00.000] 4 1`)
+
+	err := simulator.ProcessEvent(parseEvents(t, "[10:05:00.000] 6 1 1")[0])
+	var transitionError *domain.TransitionError
+	if !errors.As(err, &transitionError) || transitionError.Status != domain.StatusStarted {
+		t.Fatalf("got %v, want a TransitionError in status Started", err)
+	}
+	if simulator.Competitors[1].TotalHits != 0 {
+		t.Error("the rejected hit was counted")
+	}
+}
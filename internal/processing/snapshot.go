@@ -0,0 +1,404 @@
+package processing
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"biathlonPrototype/internal/config"
+	"biathlonPrototype/internal/domain"
+)
+
+// SnapshotVersion is the version of the snapshot format written by Snapshot and checkpoints
+const SnapshotVersion = 3
+
+// snapshot is the serialized race state of a simulator. It is encoded with marshalSnapshot, which writes every
+// exported field of the competitors and events, times and durations in the "HH:MM:SS.sss" form of the event files
+type snapshot struct {
+	Version           int                        `json:"version"`
+	CurrentTime       time.Time                  `json:"currentTime"`
+	Competitors       map[int]*domain.Competitor `json:"competitors"`
+	Events            []*domain.Event            `json:"events"`
+	OutputLog         []string                   `json:"outputLog"`
+	OutputTimes       []time.Time                `json:"outputTimes"`
+	RaceInfo          *domain.RaceInfo           `json:"raceInfo"`
+	DuplicatesDropped int                        `json:"duplicatesDropped"`
+	StationConflicts  []StationConflict          `json:"stationConflicts"`
+	Warnings          []Warning                  `json:"warnings"`
+	Stats             Stats                      `json:"stats"`
+}
+
+// Snapshot serializes the complete race state to JSON so processing can later continue with RestoreSimulator
+func (simulator *Simulator) Snapshot() ([]byte, error) {
+	data, err := marshalSnapshot(simulator.snapshot())
+	if err != nil {
+		return nil, fmt.Errorf("error encoding snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// RestoreSimulator creates a simulator with the race state of a snapshot; processing continues with the next event
+func RestoreSimulator(cfg *config.Config, data []byte, options ...Option) (*Simulator, error) {
+	var saved snapshot
+	if err := unmarshalSnapshot(data, &saved); err != nil {
+		return nil, fmt.Errorf("error parsing snapshot: %w", err)
+	}
+
+	simulator := NewSimulator(cfg, options...)
+	if err := simulator.restore(saved); err != nil {
+		return nil, err
+	}
+	return simulator, nil
+}
+
+// snapshot returns the race state of the simulator
+func (simulator *Simulator) snapshot() snapshot {
+	return snapshot{
+		Version:           SnapshotVersion,
+		CurrentTime:       simulator.CurrentTime,
+		Competitors:       simulator.Competitors,
+		Events:            simulator.Events,
+		OutputLog:         simulator.OutputLog,
+		OutputTimes:       simulator.alignedOutputTimes(),
+		RaceInfo:          simulator.RaceInfo,
+		DuplicatesDropped: simulator.DuplicatesDropped,
+		StationConflicts:  simulator.StationConflicts,
+		Warnings:          simulator.warnings,
+		Stats:             simulator.stats,
+	}
+}
+
+// restore replaces the race state of the simulator with a snapshot
+func (simulator *Simulator) restore(saved snapshot) error {
+	if saved.Version != SnapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d, expected %d", saved.Version, SnapshotVersion)
+	}
+
+	simulator.CurrentTime = saved.CurrentTime
+	simulator.Competitors = saved.Competitors
+	if simulator.Competitors == nil {
+		simulator.Competitors = make(map[int]*domain.Competitor)
+	}
+	simulator.Events = saved.Events
+	if simulator.Events == nil {
+		simulator.Events = make([]*domain.Event, 0)
+	}
+	simulator.OutputLog = saved.OutputLog
+	if simulator.OutputLog == nil {
+		simulator.OutputLog = make([]string, 0)
+	}
+	simulator.outputTimes = saved.OutputTimes
+	simulator.RaceInfo = saved.RaceInfo
+	simulator.DuplicatesDropped = saved.DuplicatesDropped
+	simulator.StationConflicts = saved.StationConflicts
+	simulator.warnings = saved.Warnings
+	if simulator.warnings == nil {
+		simulator.warnings = make([]Warning, 0)
+	}
+	simulator.rebuildEventIndex()
+	simulator.stats = saved.Stats
+	if simulator.stats.IncomingEvents == nil || simulator.stats.CompetitorsByStatus == nil {
+		simulator.stats = newStats()
+	}
+	return nil
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// marshalSnapshot encodes a snapshot or checkpoint as JSON. Structs become objects with every exported field, named
+// by its json tag if it has one, so the encoding does not depend on custom marshalling like that of
+// domain.Competitor. Times are written as "HH:MM:SS.sss" and durations as "[-]HH:MM:SS.sss", or in RFC 3339 and Go
+// duration syntax if that would lose precision
+func marshalSnapshot(value any) ([]byte, error) {
+	data, err := snapshotData(reflect.ValueOf(value))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+// unmarshalSnapshot decodes JSON written by marshalSnapshot into target, a pointer to a snapshot or checkpoint. The
+// version is checked first so that snapshots of other versions are reported as such
+func unmarshalSnapshot(data []byte, target any) error {
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+	if header.Version != SnapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d, expected %d", header.Version, SnapshotVersion)
+	}
+	return decodeSnapshotData(data, reflect.ValueOf(target).Elem())
+}
+
+// snapshotData converts a value into data that encoding/json writes in the snapshot encoding
+func snapshotData(value reflect.Value) (any, error) {
+	switch value.Type() {
+	case timeType:
+		return formatSnapshotTime(value.Interface().(time.Time)), nil
+	case durationType:
+		return formatSnapshotDuration(time.Duration(value.Int())), nil
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return nil, nil
+		}
+		return snapshotData(value.Elem())
+	case reflect.Struct:
+		object := make(map[string]any)
+		return object, snapshotFields(value, object)
+	case reflect.Slice:
+		if value.IsNil() {
+			return nil, nil
+		}
+		list := make([]any, value.Len())
+		for i := range list {
+			item, err := snapshotData(value.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			list[i] = item
+		}
+		return list, nil
+	case reflect.Map:
+		if value.IsNil() {
+			return nil, nil
+		}
+		object := make(map[string]any, value.Len())
+		for iterator := value.MapRange(); iterator.Next(); {
+			key, err := snapshotKey(iterator.Key())
+			if err != nil {
+				return nil, err
+			}
+			if object[key], err = snapshotData(iterator.Value()); err != nil {
+				return nil, err
+			}
+		}
+		return object, nil
+	case reflect.Bool, reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return value.Interface(), nil
+	}
+	return nil, fmt.Errorf("unsupported type %s in snapshot", value.Type())
+}
+
+// snapshotFields adds the exported fields of a struct to an object, including those of embedded structs
+func snapshotFields(value reflect.Value, object map[string]any) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := snapshotFields(value.Field(i), object); err != nil {
+				return err
+			}
+			continue
+		}
+		name, ok := snapshotFieldName(field)
+		if !ok {
+			continue
+		}
+		data, err := snapshotData(value.Field(i))
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.Name, err)
+		}
+		object[name] = data
+	}
+	return nil
+}
+
+// decodeSnapshotData decodes JSON written by snapshotData into an addressable value
+func decodeSnapshotData(data json.RawMessage, target reflect.Value) error {
+	isNull := string(data) == "null"
+	switch target.Type() {
+	case timeType, durationType:
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		if target.Type() == timeType {
+			parsed, err := parseSnapshotTime(text)
+			target.Set(reflect.ValueOf(parsed))
+			return err
+		}
+		parsed, err := parseSnapshotDuration(text)
+		target.SetInt(int64(parsed))
+		return err
+	}
+
+	switch target.Kind() {
+	case reflect.Pointer:
+		if isNull {
+			target.SetZero()
+			return nil
+		}
+		target.Set(reflect.New(target.Type().Elem()))
+		return decodeSnapshotData(data, target.Elem())
+	case reflect.Struct:
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(data, &object); err != nil {
+			return err
+		}
+		return decodeSnapshotFields(object, target)
+	case reflect.Slice:
+		var list []json.RawMessage
+		if err := json.Unmarshal(data, &list); err != nil {
+			return err
+		}
+		if list == nil {
+			target.SetZero()
+			return nil
+		}
+		slice := reflect.MakeSlice(target.Type(), len(list), len(list))
+		for i, item := range list {
+			if err := decodeSnapshotData(item, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+		return nil
+	case reflect.Map:
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(data, &object); err != nil {
+			return err
+		}
+		if object == nil {
+			target.SetZero()
+			return nil
+		}
+		mapValue := reflect.MakeMapWithSize(target.Type(), len(object))
+		for key, item := range object {
+			keyValue, err := parseSnapshotKey(key, target.Type().Key())
+			if err != nil {
+				return err
+			}
+			element := reflect.New(target.Type().Elem()).Elem()
+			if err = decodeSnapshotData(item, element); err != nil {
+				return err
+			}
+			mapValue.SetMapIndex(keyValue, element)
+		}
+		target.Set(mapValue)
+		return nil
+	}
+	return json.Unmarshal(data, target.Addr().Interface())
+}
+
+// decodeSnapshotFields sets the fields of a struct, including those of embedded structs, from an object
+func decodeSnapshotFields(object map[string]json.RawMessage, target reflect.Value) error {
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Type().Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := decodeSnapshotFields(object, target.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+		name, ok := snapshotFieldName(field)
+		if !ok {
+			continue
+		}
+		if data, found := object[name]; found {
+			if err := decodeSnapshotData(data, target.Field(i)); err != nil {
+				return fmt.Errorf("%s: %w", field.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// snapshotFieldName returns the key of a struct field in the snapshot; false for unexported and ignored fields
+func snapshotFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return field.Name, true
+	}
+	return name, true
+}
+
+// snapshotKey returns the object key of a map key, which must be a string or an integer
+func snapshotKey(key reflect.Value) (string, error) {
+	switch key.Kind() {
+	case reflect.String:
+		return key.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported map key type %s in snapshot", key.Type())
+}
+
+// parseSnapshotKey converts an object key back to a map key of the given type
+func parseSnapshotKey(key string, keyType reflect.Type) (reflect.Value, error) {
+	value := reflect.New(keyType).Elem()
+	switch keyType.Kind() {
+	case reflect.String:
+		value.SetString(key)
+		return value, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, err := strconv.ParseInt(key, 10, keyType.Bits())
+		value.SetInt(number)
+		return value, err
+	}
+	return value, fmt.Errorf("unsupported map key type %s in snapshot", keyType)
+}
+
+// formatSnapshotTime formats a time of day as "HH:MM:SS.sss", a time with a date or finer precision in RFC 3339 and
+// the zero time as ""
+func formatSnapshotTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	text := t.Format(domain.TimeLayout)
+	if clock, err := time.Parse(domain.TimeLayout, text); err == nil && clock.Equal(t) && t.Location() == time.UTC {
+		return text
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// parseSnapshotTime parses a time written by formatSnapshotTime
+func parseSnapshotTime(text string) (time.Time, error) {
+	switch {
+	case text == "":
+		return time.Time{}, nil
+	case len(text) == len(domain.TimeLayout):
+		return time.Parse(domain.TimeLayout, text)
+	}
+	return time.Parse(time.RFC3339Nano, text)
+}
+
+// formatSnapshotDuration formats a duration as "[-]HH:MM:SS.sss", or in Go syntax like "1.5µs" if it is not a whole
+// number of milliseconds
+func formatSnapshotDuration(d time.Duration) string {
+	switch {
+	case d%time.Millisecond != 0:
+		return d.String()
+	case d < 0:
+		return "-" + domain.FormatDuration(-d)
+	}
+	return domain.FormatDuration(d)
+}
+
+// parseSnapshotDuration parses a duration written by formatSnapshotDuration
+func parseSnapshotDuration(text string) (time.Duration, error) {
+	if !strings.Contains(text, ":") {
+		return time.ParseDuration(text)
+	}
+	if negative, found := strings.CutPrefix(text, "-"); found {
+		d, err := domain.ParseDurationFromString(negative)
+		return -d, err
+	}
+	return domain.ParseDurationFromString(text)
+}
@@ -0,0 +1,124 @@
+package processing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshotAtEveryLineContinuesLikeAFullRun(t *testing.T) {
+	events := parseEvents(t, readExample(t))
+	full := newTestSimulator(t)
+	for _, event := range events {
+		if err := full.ProcessEvent(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	full.CheckForNotStarted()
+	want, err := full.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []int{0, 1, 17, len(events) / 2, len(events) - 1} {
+		first := newTestSimulator(t)
+		for _, event := range events[:line] {
+			if err = first.ProcessEvent(event); err != nil {
+				t.Fatal(err)
+			}
+		}
+		data, err := first.Snapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		restored, err := RestoreSimulator(first.Config, data, WithLogger(quietLogger()))
+		if err != nil {
+			t.Fatalf("line %d: %v", line, err)
+		}
+		for _, event := range parseEvents(t, readExample(t))[line:] {
+			if err = restored.ProcessEvent(event); err != nil {
+				t.Fatal(err)
+			}
+		}
+		restored.CheckForNotStarted()
+		got, err := restored.Snapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("snapshot at line %d plus the remaining events differs from a full run", line)
+		}
+		if !reflect.DeepEqual(restored.GetSortedCompetitors(), full.GetSortedCompetitors()) {
+			t.Errorf("competitors restored at line %d differ from a full run", line)
+		}
+		if !reflect.DeepEqual(restored.OutputLog, full.OutputLog) {
+			t.Errorf("output log restored at line %d differs from a full run", line)
+		}
+	}
+}
+
+func TestSnapshotWritesTimesAndDurationsAsClockTimes(t *testing.T) {
+	simulator := newTestSimulator(t)
+	mustProcess(t, simulator, `
+		[09:00:00.000] 1 1
+		[09:00:01.000] 2 1 10:00:00.000
+		[10:00:01.500] 4 1
+		[10:10:00.000] 10 1`)
+	data, err := simulator.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded struct {
+		Version     int                        `json:"version"`
+		CurrentTime string                     `json:"currentTime"`
+		Competitors map[string]json.RawMessage `json:"competitors"`
+	}
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Version != SnapshotVersion || decoded.CurrentTime != "10:10:00.000" {
+		t.Errorf("version %d, current time %q", decoded.Version, decoded.CurrentTime)
+	}
+	competitor := string(decoded.Competitors["1"])
+	for _, want := range []string{`"ActualStartTime":"10:00:01.500"`, `"ScheduledStartTime":"10:00:00.000"`, `"Duration":"00:09:58.500"`} {
+		if !strings.Contains(competitor, want) {
+			t.Errorf("competitor %s does not contain %s", competitor, want)
+		}
+	}
+}
+
+func TestSnapshotKeepsTimesAndDurationsExactly(t *testing.T) {
+	for _, d := range []time.Duration{0, 1500 * time.Millisecond, -2 * time.Second, 1500 * time.Microsecond, 100 * time.Hour} {
+		got, err := parseSnapshotDuration(formatSnapshotDuration(d))
+		if err != nil || got != d {
+			t.Errorf("duration %v round-trips to %v (%v)", d, got, err)
+		}
+	}
+	for _, tm := range []time.Time{{}, time.Date(0, 1, 1, 10, 0, 1, 500e6, time.UTC), time.Date(2024, 3, 12, 10, 0, 1, 1, time.UTC)} {
+		got, err := parseSnapshotTime(formatSnapshotTime(tm))
+		if err != nil || !got.Equal(tm) {
+			t.Errorf("time %v round-trips to %v (%v)", tm, got, err)
+		}
+	}
+}
+
+func TestRestoreSimulatorRejectsOtherVersions(t *testing.T) {
+	simulator := newTestSimulator(t)
+	mustProcess(t, simulator, "[09:00:00.000] 1 1")
+	data, err := simulator.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = bytes.Replace(data, []byte(fmt.Sprintf(`"version":%d`, SnapshotVersion)), []byte(`"version":1`), 1)
+
+	_, err = RestoreSimulator(simulator.Config, data)
+	if err == nil || !strings.Contains(err.Error(), "unsupported snapshot version 1") {
+		t.Errorf("err = %v, want an unsupported version error", err)
+	}
+}
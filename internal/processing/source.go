@@ -0,0 +1,238 @@
+package processing
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// EventSource supplies events one by one; Next returns io.EOF when no events are left
+type EventSource interface {
+	Next() (*domain.Event, error)
+}
+
+// FilePosition identifies a position in a line-based event file
+type FilePosition struct {
+	Offset     int64 `json:"offset"`
+	LineNumber int   `json:"lineNumber"`
+}
+
+// positionedSource is implemented by sources that can report how far they have read
+type positionedSource interface {
+	Position() FilePosition
+}
+
+// LineEventSource reads events in the input log format from a reader
+type LineEventSource struct {
+	scanner         *bufio.Scanner
+	position        FilePosition
+	lastAdvance     int
+	lastTerminated  bool
+	HoldPartialLine bool
+	eventsRead      bool
+
+	// OnComment is called for every full-line '#' comment
+	OnComment func(lineNumber int, comment string)
+	// OnRaceHeader is called for the optional race header preceding the first event
+	OnRaceHeader func(info *domain.RaceInfo)
+	// Logger receives the warnings of the source; the console logger is used when nil
+	Logger *slog.Logger
+}
+
+// NewLineEventSource creates an event source reading lines from a reader
+func NewLineEventSource(reader io.Reader) *LineEventSource {
+	return NewLineEventSourceAt(reader, FilePosition{})
+}
+
+// NewLineEventSourceAt creates an event source for a reader that starts at the given file position
+func NewLineEventSourceAt(reader io.Reader, position FilePosition) *LineEventSource {
+	source := &LineEventSource{
+		scanner:  bufio.NewScanner(reader),
+		position: position,
+	}
+	source.scanner.Split(source.scanLines)
+	return source
+}
+
+// Position returns the position just after the last line that was read
+func (source *LineEventSource) Position() FilePosition {
+	return source.position
+}
+
+// logger returns the logger for warnings of the source
+func (source *LineEventSource) logger() *slog.Logger {
+	if source.Logger == nil {
+		return defaultLogger()
+	}
+	return source.Logger
+}
+
+// scanLines splits lines like bufio.ScanLines while recording the consumed bytes
+func (source *LineEventSource) scanLines(data []byte, atEOF bool) (int, []byte, error) {
+	advance, token, err := bufio.ScanLines(data, atEOF)
+	if token != nil {
+		source.lastAdvance = advance
+		source.lastTerminated = advance > 0 && data[advance-1] == '\n'
+	}
+	return advance, token, err
+}
+
+// Next reads the next event, skipping empty and comment lines
+func (source *LineEventSource) Next() (*domain.Event, error) {
+	for source.scanner.Scan() {
+		if !source.lastTerminated && source.HoldPartialLine {
+			source.logger().Warn(fmt.Sprintf("unterminated last line %d is held back until it is complete", source.position.LineNumber+1),
+				"line", source.position.LineNumber+1)
+			break
+		}
+		source.position.LineNumber++
+		source.position.Offset += int64(source.lastAdvance)
+
+		line, comment := splitComment(source.scanner.Text())
+		if strings.HasPrefix(line, domain.RaceHeaderPrefix) {
+			if source.eventsRead {
+				return nil, fmt.Errorf("race header at line %d must precede all events", source.position.LineNumber)
+			}
+			info, err := domain.ParseRaceHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("race header error at line %d: %w", source.position.LineNumber, err)
+			}
+			if source.OnRaceHeader != nil {
+				source.OnRaceHeader(info)
+			}
+			continue
+		}
+		if line == "" {
+			if comment != "" && source.OnComment != nil {
+				source.OnComment(source.position.LineNumber, comment)
+			}
+			continue
+		}
+
+		event, err := domain.ParseEventFromString(line)
+		if err != nil {
+			var parseError *domain.ParseError
+			if errors.As(err, &parseError) {
+				parseError.Line = source.position.LineNumber
+				return nil, fmt.Errorf("string parsing error: %w", parseError)
+			}
+			return nil, fmt.Errorf("string parsing error at line %d ('%s'): %w", source.position.LineNumber, line, err)
+		}
+		event.LineNumber = source.position.LineNumber
+		event.Comment = comment
+		source.eventsRead = true
+		return event, nil
+	}
+
+	if scanErr := source.scanner.Err(); scanErr != nil {
+		return nil, fmt.Errorf("error reading events after line %d: %w", source.position.LineNumber, scanErr)
+	}
+	return nil, io.EOF
+}
+
+// ChannelEventSource reads events from a channel until it is closed
+type ChannelEventSource struct {
+	events <-chan *domain.Event
+	ticks  <-chan time.Time
+	onTick func()
+}
+
+// NewChannelEventSource creates an event source reading from a channel
+func NewChannelEventSource(events <-chan *domain.Event) *ChannelEventSource {
+	return &ChannelEventSource{events: events}
+}
+
+// OnTick makes Next call the function on every tick while it waits for events, e.g. Simulator.CheckDeadlines.
+// The function runs in the goroutine that reads the source, so it may safely change the simulator
+func (source *ChannelEventSource) OnTick(ticks <-chan time.Time, onTick func()) {
+	source.ticks = ticks
+	source.onTick = onTick
+}
+
+// Next waits for the next event and returns io.EOF once the channel is closed
+func (source *ChannelEventSource) Next() (*domain.Event, error) {
+	for {
+		select {
+		case event, ok := <-source.events:
+			if !ok {
+				return nil, io.EOF
+			}
+			return event, nil
+		case <-source.ticks:
+			source.onTick()
+		}
+	}
+}
+
+// BinaryEventSource reads length-prefixed binary encoded events from a reader
+type BinaryEventSource struct {
+	reader *bufio.Reader
+	index  int
+}
+
+// NewBinaryEventSource creates an event source reading binary encoded events
+func NewBinaryEventSource(reader io.Reader) *BinaryEventSource {
+	return &BinaryEventSource{
+		reader: bufio.NewReader(reader),
+	}
+}
+
+// Next reads the next encoded event
+func (source *BinaryEventSource) Next() (*domain.Event, error) {
+	event, err := domain.ReadEventFrame(source.reader)
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	source.index++
+	if err != nil {
+		return nil, fmt.Errorf("error decoding event #%d: %w", source.index, err)
+	}
+	return event, nil
+}
+
+// sortedEventSource reads all events from another source and serves them in chronological order
+type sortedEventSource struct {
+	source EventSource
+	events []*domain.Event
+	loaded bool
+}
+
+// newSortedEventSource wraps a source so that its events are sorted by timestamp, keeping the input order for ties
+func newSortedEventSource(source EventSource) *sortedEventSource {
+	return &sortedEventSource{source: source}
+}
+
+// Next returns the next event in chronological order, reading the whole underlying source on first use
+func (source *sortedEventSource) Next() (*domain.Event, error) {
+	if !source.loaded {
+		source.loaded = true
+		for {
+			event, err := source.source.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			source.events = append(source.events, event)
+		}
+		sort.SliceStable(source.events, func(i, j int) bool {
+			return source.events[i].Timestamp.Before(source.events[j].Timestamp)
+		})
+	}
+
+	if len(source.events) == 0 {
+		return nil, io.EOF
+	}
+	event := source.events[0]
+	source.events[0] = nil
+	source.events = source.events[1:]
+	return event, nil
+}
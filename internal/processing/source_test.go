@@ -0,0 +1,60 @@
+package processing
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"biathlonPrototype/internal/domain"
+)
+
+func TestLoadEventsFromFileReportsTheLineOfAParseError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	events := "[09:00:00.000] 1 1\n\n# a comment\n[09:00:01.000] 2 1 10:61:00.000\n"
+	if err := os.WriteFile(path, []byte(events), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := newTestSimulator(t).LoadEventsFromFile(path)
+	var parseError *domain.ParseError
+	if !errors.As(err, &parseError) {
+		t.Fatalf("error %v is not a ParseError", err)
+	}
+	if parseError.Line != 4 || parseError.Kind != domain.ParseErrorParameter || parseError.Field != 4 {
+		t.Errorf("line %d, kind %v, field %d, want line 4, %v and field 4", parseError.Line, parseError.Kind, parseError.Field, domain.ParseErrorParameter)
+	}
+	if parseError.RawLine != "[09:00:01.000] 2 1 10:61:00.000" {
+		t.Errorf("raw line %q", parseError.RawLine)
+	}
+}
+
+func TestSplitCommentAfterAnyWhitespace(t *testing.T) {
+	tests := []struct {
+		line, wantEvent, wantComment string
+	}{
+		{"[10:07:00.000] 11 1 fell # note", "[10:07:00.000] 11 1 fell", "# note"},
+		{"[10:07:00.000] 11 1 fell\t# note", "[10:07:00.000] 11 1 fell", "# note"},
+		{"[10:07:00.000] 11 1 fell\u00a0# note", "[10:07:00.000] 11 1 fell", "# note"},
+		{"  # a comment line", "", "# a comment line"},
+		{"[10:07:00.000] 11 1 bib#2 lost", "[10:07:00.000] 11 1 bib#2 lost", ""},
+		{"[10:07:00.000] 11 1 bib#2\t# note", "[10:07:00.000] 11 1 bib#2", "# note"},
+	}
+	for _, tt := range tests {
+		if event, comment := splitComment(tt.line); event != tt.wantEvent || comment != tt.wantComment {
+			t.Errorf("splitComment(%q) = %q, %q, want %q, %q", tt.line, event, comment, tt.wantEvent, tt.wantComment)
+		}
+	}
+}
+
+func TestTabSeparatedCommentIsNotAParameter(t *testing.T) {
+	simulator := newTestSimulator(t)
+	events := "[09:00:00.000] 1 1\n[09:00:01.000] 2 1 10:00:00.000\n[10:00:00.000] 4 1\n[10:07:00.000] 11 1 fell\t# note\n"
+	if err := simulator.ProcessEventsFromReader(strings.NewReader(events)); err != nil {
+		t.Fatal(err)
+	}
+	if reason := simulator.Competitors[1].DisqualificationReason.String(); reason != "fell" {
+		t.Errorf("reason %q, want the parameters without the comment", reason)
+	}
+}
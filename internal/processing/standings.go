@@ -0,0 +1,151 @@
+package processing
+
+import (
+	"io"
+	"log/slog"
+	"sort"
+	"time"
+
+	"biathlonPrototype/internal/config"
+	"biathlonPrototype/internal/domain"
+)
+
+// Standing is the position of a competitor in the intermediate standings
+type Standing struct {
+	// Place is the position among the ranked competitors; zero for competitors that are not ranked
+	// (not started yet, not finished, not started or disqualified)
+	Place         int
+	Competitor    *domain.Competitor
+	LapsCompleted int
+	// Elapsed is the race time at the finish for finished competitors and at the last recorded event otherwise
+	Elapsed  time.Duration
+	Finished bool
+
+	// ProjectedFinish is the estimated finish time of a competitor still racing; Projected is false until
+	// the competitor completed a lap and an estimate can be made
+	ProjectedFinish time.Time
+	Projected       bool
+}
+
+// Standings returns the standings at the current moment: finished competitors by total time, then competitors
+// still racing by completed laps and the race time at their last recorded event. Competitors on the firing range
+// or in the penalty loop are ranked by the event that took them there, so they only move up once they leave it
+func (simulator *Simulator) Standings() []Standing {
+	standings := make([]Standing, 0, len(simulator.Competitors))
+	for _, competitor := range simulator.Competitors {
+		standing := Standing{Competitor: competitor, LapsCompleted: len(competitor.LapDetails)}
+		if totalTime, ok := competitor.CalculateTotalTime(); ok {
+			standing.Finished = true
+			standing.Elapsed = totalTime
+		} else {
+			standing.Elapsed = competitor.ElapsedTime(competitor.LastEventTime)
+		}
+		if standingRank(standing) == 1 {
+			standing.ProjectedFinish, standing.Projected = competitor.ProjectedFinish(projectionSettings(simulator.configFor(competitor)), simulator.CurrentTime)
+		}
+		standings = append(standings, standing)
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		s1, s2 := standings[i], standings[j]
+		rank1, rank2 := standingRank(s1), standingRank(s2)
+		if rank1 != rank2 {
+			return rank1 < rank2
+		}
+		if rank1 == 1 && s1.LapsCompleted != s2.LapsCompleted {
+			return s1.LapsCompleted > s2.LapsCompleted
+		}
+		if rank1 <= 1 && s1.Elapsed != s2.Elapsed {
+			return s1.Elapsed < s2.Elapsed
+		}
+		return s1.Competitor.ID < s2.Competitor.ID
+	})
+
+	for i := range standings {
+		if standingRank(standings[i]) <= 1 {
+			standings[i].Place = i + 1
+		}
+	}
+	return standings
+}
+
+// projectionSettings returns the values finish projections extrapolate over for the configuration
+func projectionSettings(cfg *config.Config) domain.ProjectionSettings {
+	return domain.ProjectionSettings{
+		Laps:          cfg.TotalLaps(),
+		FiringRanges:  cfg.TotalFiringLines(),
+		ShotsPerRange: DefaultShotsPerRange,
+		ShootingStop:  cfg.ParsedExpectedShootingStop,
+		PenaltyLoop:   cfg.ParsedExpectedPenaltyLoop,
+	}
+}
+
+// standingRank groups standings: finished, racing, not started yet, lapped, and the other final non-finish statuses
+func standingRank(standing Standing) int {
+	if standing.Finished {
+		return 0
+	}
+	switch standing.Competitor.Status {
+	case domain.StatusStarted, domain.StatusFiring, domain.StatusPenalized:
+		return 1
+	case domain.StatusRegistered, domain.StatusReadyToStart:
+		return 2
+	case domain.StatusLapped:
+		return 3
+	case domain.StatusNotFinished:
+		return 4
+	case domain.StatusNotStarted:
+		return 5
+	default:
+		return 6
+	}
+}
+
+// LapStanding is the position of a competitor at the end of a lap
+type LapStanding struct {
+	Place      int
+	Competitor *domain.Competitor
+	// Elapsed is the race time at the end of the lap and Behind the gap to the fastest competitor there
+	Elapsed time.Duration
+	Behind  time.Duration
+}
+
+// StandingsAfterLap ranks the competitors by their race time at the end of a lap (numbered from 1). Competitors
+// who have not completed the lap or whose time at its end is unknown are left out
+func (simulator *Simulator) StandingsAfterLap(lap int) []LapStanding {
+	standings := make([]LapStanding, 0, len(simulator.Competitors))
+	for _, competitor := range simulator.Competitors {
+		if elapsed, ok := competitor.ElapsedAfterLap(lap); ok {
+			standings = append(standings, LapStanding{Competitor: competitor, Elapsed: elapsed})
+		}
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		if standings[i].Elapsed != standings[j].Elapsed {
+			return standings[i].Elapsed < standings[j].Elapsed
+		}
+		return standings[i].Competitor.ID < standings[j].Competitor.ID
+	})
+	for i := range standings {
+		standings[i].Place = i + 1
+		standings[i].Behind = standings[i].Elapsed - standings[0].Elapsed
+	}
+	return standings
+}
+
+// StandingsAt returns the standings as they were at the given moment by replaying the recorded incoming events up to it
+func (simulator *Simulator) StandingsAt(at time.Time) []Standing {
+	replay := NewSimulator(simulator.Config, WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	replay.PrintWarnings = false
+	replay.handlers = simulator.handlers
+	for _, event := range simulator.Events {
+		if event.Timestamp.After(at) {
+			break
+		}
+		if event.IsIncoming || replay.handlers[event.ID] != nil {
+			// Errors were already reported when the event was processed the first time
+			_ = replay.ProcessEvent(event)
+		}
+	}
+	return replay.Standings()
+}
@@ -0,0 +1,54 @@
+package processing
+
+import (
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// setStartGroup assigns the competitor to a start group named by a SetStartTime event; an unknown group is
+// reported and ignored, so the global start delta applies
+func (simulator *Simulator) setStartGroup(competitor *domain.Competitor, name string, event *domain.Event) {
+	if _, found := simulator.Config.StartGroupDelta(name); !found {
+		simulator.warn(WarningUnknownStartGroup, event, competitor.ID, "competitor %d was drawn into start group '%s', which is not configured; the global start delta applies",
+			competitor.ID, name)
+		return
+	}
+	competitor.StartGroup = name
+}
+
+// startDeltaFor returns the start delta of the competitor's start group, or the start delta of their race group
+func (simulator *Simulator) startDeltaFor(competitor *domain.Competitor) time.Duration {
+	if delta, found := simulator.Config.StartGroupDelta(competitor.StartGroup); found {
+		return delta
+	}
+	return simulator.configFor(competitor).ParsedStartDelta
+}
+
+// StartGroups returns the names of the configured start groups that have competitors, in configuration order
+func (simulator *Simulator) StartGroups() []string {
+	present := make(map[string]bool)
+	for _, competitor := range simulator.Competitors {
+		present[competitor.StartGroup] = true
+	}
+
+	groups := make([]string, 0, len(simulator.Config.StartGroups))
+	for _, group := range simulator.Config.StartGroups {
+		if present[group.Name] {
+			groups = append(groups, group.Name)
+		}
+	}
+	return groups
+}
+
+// GetSortedCompetitorsInStartGroup returns the classification within one start group in report order
+func (simulator *Simulator) GetSortedCompetitorsInStartGroup(group string) []*domain.Competitor {
+	sorted := simulator.GetSortedCompetitors()
+	inGroup := make([]*domain.Competitor, 0, len(sorted))
+	for _, competitor := range sorted {
+		if competitor.StartGroup == group {
+			inGroup = append(inGroup, competitor)
+		}
+	}
+	return inGroup
+}
@@ -0,0 +1,110 @@
+package processing
+
+import (
+	"slices"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// StationPolicy selects which copy of an event reported by several timing stations is kept
+type StationPolicy string
+
+const (
+	StationPolicyNone     StationPolicy = ""
+	StationPolicyPrimary  StationPolicy = "primary"
+	StationPolicyEarliest StationPolicy = "earliest"
+)
+
+// DefaultStationConflictWindow is the time within which copies from different stations are treated as the same event
+const DefaultStationConflictWindow = time.Second
+
+// StationConflict records an event reported by several stations and the copy that was kept
+type StationConflict struct {
+	Chosen    *domain.Event
+	Discarded *domain.Event
+}
+
+// stationResolver delays events by the conflict window so that copies from different stations can be resolved
+type stationResolver struct {
+	policy         StationPolicy
+	primaryStation string
+	window         time.Duration
+	pending        []*domain.Event
+}
+
+// newStationResolver creates a resolver; with StationPolicyNone events pass through unchanged
+func newStationResolver(policy StationPolicy, primaryStation string, window time.Duration) *stationResolver {
+	if window <= 0 {
+		window = DefaultStationConflictWindow
+	}
+	return &stationResolver{
+		policy:         policy,
+		primaryStation: primaryStation,
+		window:         window,
+		pending:        make([]*domain.Event, 0),
+	}
+}
+
+// push adds chronologically ordered events and returns the events ready for processing and any resolved conflicts
+func (resolver *stationResolver) push(events []*domain.Event) ([]*domain.Event, []StationConflict) {
+	if resolver.policy == StationPolicyNone {
+		return events, nil
+	}
+
+	released := make([]*domain.Event, 0)
+	conflicts := make([]StationConflict, 0)
+	for _, event := range events {
+		for len(resolver.pending) > 0 && event.Timestamp.Sub(resolver.pending[0].Timestamp) > resolver.window {
+			released = append(released, resolver.pending[0])
+			resolver.pending = resolver.pending[1:]
+		}
+
+		conflictIndex := slices.IndexFunc(resolver.pending, func(pending *domain.Event) bool {
+			return resolver.isConflict(pending, event)
+		})
+		if conflictIndex < 0 {
+			resolver.pending = append(resolver.pending, event)
+			continue
+		}
+
+		existing := resolver.pending[conflictIndex]
+		if resolver.prefer(event, existing) {
+			conflicts = append(conflicts, StationConflict{Chosen: event, Discarded: existing})
+			resolver.pending = slices.Delete(resolver.pending, conflictIndex, conflictIndex+1)
+			resolver.pending = append(resolver.pending, event)
+		} else {
+			conflicts = append(conflicts, StationConflict{Chosen: existing, Discarded: event})
+		}
+	}
+	return released, conflicts
+}
+
+// flush releases all pending events
+func (resolver *stationResolver) flush() []*domain.Event {
+	released := resolver.pending
+	resolver.pending = make([]*domain.Event, 0)
+	return released
+}
+
+// isConflict reports whether two events are copies of the same event from different stations
+func (resolver *stationResolver) isConflict(first, second *domain.Event) bool {
+	return first.Station != second.Station &&
+		first.CompetitorID == second.CompetitorID &&
+		first.ID == second.ID &&
+		slices.Equal(first.ExtraParameters, second.ExtraParameters) &&
+		second.Timestamp.Sub(first.Timestamp).Abs() <= resolver.window
+}
+
+// prefer reports whether the candidate should replace the existing copy
+func (resolver *stationResolver) prefer(candidate, existing *domain.Event) bool {
+	if resolver.policy == StationPolicyPrimary && candidate.Station != existing.Station {
+		if candidate.Station == resolver.primaryStation {
+			return true
+		}
+		if existing.Station == resolver.primaryStation {
+			return false
+		}
+	}
+	return candidate.Timestamp.Before(existing.Timestamp)
+}
@@ -0,0 +1,73 @@
+package processing
+
+import (
+	"maps"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// Stats are counters about the processed events, maintained while processing. LinesProcessed counts every event
+// passed to ProcessEvent, including filtered and rejected ones; IncomingEvents counts the recorded input events by ID;
+// EventsAfterCutoff counts the events ignored because their competitor was stopped at the cutoff
+type Stats struct {
+	LinesProcessed      int                             `json:"linesProcessed"`
+	IncomingEvents      map[domain.EventID]int          `json:"incomingEvents"`
+	OutgoingEvents      int                             `json:"outgoingEvents"`
+	Warnings            int                             `json:"warnings"`
+	CompetitorsByStatus map[domain.CompetitorStatus]int `json:"competitorsByStatus"`
+	FirstEventTime      time.Time                       `json:"firstEventTime"`
+	LastEventTime       time.Time                       `json:"lastEventTime"`
+	EventsAfterCutoff   int                             `json:"eventsAfterCutoff"`
+}
+
+// newStats returns empty statistics
+func newStats() Stats {
+	return Stats{
+		IncomingEvents:      make(map[domain.EventID]int),
+		CompetitorsByStatus: make(map[domain.CompetitorStatus]int),
+	}
+}
+
+// Stats returns a copy of the statistics collected so far
+func (simulator *Simulator) Stats() Stats {
+	stats := simulator.stats
+	stats.IncomingEvents = maps.Clone(simulator.stats.IncomingEvents)
+	stats.CompetitorsByStatus = maps.Clone(simulator.stats.CompetitorsByStatus)
+	stats.Warnings = len(simulator.warnings)
+	return stats
+}
+
+// countEvent updates the statistics for an event recorded in Events, either read from the input or generated
+func (simulator *Simulator) countEvent(event *domain.Event, generated bool) {
+	if generated {
+		simulator.stats.OutgoingEvents++
+	} else {
+		simulator.stats.IncomingEvents[event.ID]++
+	}
+	if simulator.stats.FirstEventTime.IsZero() || event.Timestamp.Before(simulator.stats.FirstEventTime) {
+		simulator.stats.FirstEventTime = event.Timestamp
+	}
+	if simulator.stats.LastEventTime.IsZero() || event.Timestamp.After(simulator.stats.LastEventTime) {
+		simulator.stats.LastEventTime = event.Timestamp
+	}
+}
+
+// countStatusChange moves a competitor between the status counters
+func (simulator *Simulator) countStatusChange(from, to domain.CompetitorStatus) {
+	if from != "" {
+		simulator.stats.CompetitorsByStatus[from]--
+		if simulator.stats.CompetitorsByStatus[from] == 0 {
+			delete(simulator.stats.CompetitorsByStatus, from)
+		}
+	}
+	simulator.stats.CompetitorsByStatus[to]++
+}
+
+// recordGenerated appends a generated outgoing event to the events and the output log
+func (simulator *Simulator) recordGenerated(event *domain.Event) {
+	simulator.Events = insertEvent(simulator.Events, event)
+	simulator.appendOutput(event.String(), event.Timestamp)
+	simulator.countEvent(event, true)
+	simulator.indexEvent(event)
+}
@@ -0,0 +1,66 @@
+package processing
+
+import (
+	"sort"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// CompetitorView is an immutable snapshot of a competitor together with values derived from its state
+type CompetitorView struct {
+	domain.Competitor
+
+	// TotalTime is the race time of a finished competitor; HasTotalTime is false until it is known
+	TotalTime    time.Duration
+	HasTotalTime bool
+	// LapsCompleted is the number of completed main laps
+	LapsCompleted int
+	// Elapsed is the race time at the current simulation time, or at the finish
+	Elapsed time.Duration
+	// State is the live state of the competitor with the place filled in for finished competitors
+	State domain.LiveState
+}
+
+// Competitor returns a snapshot of the competitor; changing it does not affect the simulator
+func (simulator *Simulator) Competitor(id int) (CompetitorView, bool) {
+	competitor, ok := simulator.Competitors[id]
+	if !ok {
+		return CompetitorView{}, false
+	}
+
+	view := CompetitorView{Competitor: *competitor.Clone(), LapsCompleted: len(competitor.LapDetails)}
+	view.TotalTime, view.HasTotalTime = competitor.CalculateTotalTime()
+	at := simulator.CurrentTime
+	if !competitor.FinishTime.IsZero() {
+		at = competitor.FinishTime
+	}
+	view.Elapsed = competitor.ElapsedTime(at)
+	view.State = simulator.CurrentState(competitor)
+	return view, true
+}
+
+// CurrentState returns the live state of a competitor with the place among the finishers
+func (simulator *Simulator) CurrentState(competitor *domain.Competitor) domain.LiveState {
+	state := competitor.CurrentState()
+	if competitor.Status != domain.StatusFinished {
+		return state
+	}
+	for _, standing := range simulator.Standings() {
+		if standing.Competitor == competitor {
+			state.Place = standing.Place
+			break
+		}
+	}
+	return state
+}
+
+// CompetitorIDs returns the IDs of all registered competitors in ascending order
+func (simulator *Simulator) CompetitorIDs() []int {
+	ids := make([]int, 0, len(simulator.Competitors))
+	for id := range simulator.Competitors {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,122 @@
+package processing
+
+import (
+	"fmt"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// WarningCode identifies the kind of anomaly recorded by the simulator; the values are stable
+type WarningCode string
+
+const (
+	WarningNearDuplicate           WarningCode = "near_duplicate"
+	WarningReRegistered            WarningCode = "re_registered"
+	WarningUnexpectedStatus        WarningCode = "unexpected_status"
+	WarningExtraFiringLine         WarningCode = "extra_firing_line"
+	WarningUnexpectedFiringRange   WarningCode = "unexpected_firing_range"
+	WarningShotCountMismatch       WarningCode = "shot_count_mismatch"
+	WarningRangeAlreadyProcessed   WarningCode = "range_already_processed"
+	WarningTooManyHits             WarningCode = "too_many_hits"
+	WarningNoPenaltyLength         WarningCode = "no_penalty_length"
+	WarningNoOutstandingPenalties  WarningCode = "no_outstanding_penalties"
+	WarningMissingPenaltyStart     WarningCode = "missing_penalty_start"
+	WarningNegativePenaltyDuration WarningCode = "negative_penalty_duration"
+	WarningIncompleteFiringRanges  WarningCode = "incomplete_firing_ranges"
+	WarningEventAfterFinalStatus   WarningCode = "event_after_final_status"
+	WarningSplitBeforeLap          WarningCode = "split_before_lap"
+	WarningSplitOutOfOrder         WarningCode = "split_out_of_order"
+	WarningUnknownEvent            WarningCode = "unknown_event"
+	WarningMissedStart             WarningCode = "missed_start"
+	WarningNoFinish                WarningCode = "no_finish"
+	WarningFalseStart              WarningCode = "false_start"
+	WarningMissingRangeEntry       WarningCode = "missing_range_entry"
+	WarningUnknownStartGroup       WarningCode = "unknown_start_group"
+	WarningCallbackPanic           WarningCode = "callback_panic"
+	WarningGroupConflict           WarningCode = "group_conflict"
+	WarningUnexpectedExchange      WarningCode = "unexpected_exchange"
+	WarningTooManySpareRounds      WarningCode = "too_many_spare_rounds"
+	WarningNotSeeded               WarningCode = "not_seeded"
+	WarningIgnoredStartTime        WarningCode = "ignored_start_time"
+	WarningPositionMismatch        WarningCode = "position_mismatch"
+	WarningRangeHitsMismatch       WarningCode = "range_hits_mismatch"
+	WarningRecoveredRangeExit      WarningCode = "recovered_range_exit"
+	WarningRecoveredPenaltyExit    WarningCode = "recovered_penalty_exit"
+	WarningCutoff                  WarningCode = "cutoff"
+	WarningEventAfterCutoff        WarningCode = "event_after_cutoff"
+	WarningInvalidNation           WarningCode = "invalid_nation"
+	WarningInvariantViolation      WarningCode = "invariant_violation"
+	WarningRuleOverridden          WarningCode = "rule_overridden"
+	WarningNegativeRangeTransition WarningCode = "negative_range_transition"
+	WarningLongRangeTransition     WarningCode = "long_range_transition"
+)
+
+// Warning describes an anomaly noticed while processing events
+type Warning struct {
+	Timestamp    time.Time
+	CompetitorID int
+	EventID      domain.EventID
+	Code         WarningCode
+	Message      string
+	RawLine      string
+}
+
+// Warnings returns the warnings recorded so far
+func (simulator *Simulator) Warnings() []Warning {
+	return simulator.warnings
+}
+
+// WarningCounts returns the number of recorded warnings per code
+func (simulator *Simulator) WarningCounts() map[WarningCode]int {
+	counts := make(map[WarningCode]int)
+	for _, warning := range simulator.warnings {
+		counts[warning.Code]++
+	}
+	return counts
+}
+
+// consoleStyle is how the console logger prints a warning: the prefix before the message and whether a line break
+// follows it. Most warnings are "Warning: ..." lines; the other styles keep the output of the original tool
+type consoleStyle struct {
+	prefix  string
+	newline bool
+}
+
+var (
+	warningStyle      = consoleStyle{prefix: "Warning: ", newline: true}
+	infoStyle         = consoleStyle{prefix: "Info: ", newline: true}
+	warningErrorStyle = consoleStyle{prefix: "Warning/Error: ", newline: true}
+	errorWarningStyle = consoleStyle{prefix: "Error/Warning: ", newline: true}
+)
+
+// warn records a warning for an event (nil for warnings not caused by a single event) and logs it if enabled
+func (simulator *Simulator) warn(code WarningCode, event *domain.Event, competitorID int, format string, args ...any) {
+	simulator.warnStyled(warningStyle, code, event, competitorID, format, args...)
+}
+
+// warnStyled is warn with the console style of the logged message
+func (simulator *Simulator) warnStyled(style consoleStyle, code WarningCode, event *domain.Event, competitorID int, format string, args ...any) {
+	warning := Warning{
+		Timestamp:    simulator.CurrentTime,
+		CompetitorID: competitorID,
+		Code:         code,
+		Message:      fmt.Sprintf(format, args...),
+	}
+	if event != nil {
+		warning.Timestamp = event.Timestamp
+		warning.EventID = event.ID
+		warning.RawLine = event.RawLine
+	}
+	simulator.warnings = append(simulator.warnings, warning)
+
+	if simulator.PrintWarnings {
+		attrs := []any{"code", string(code), "competitor", competitorID, PrefixKey, style.prefix, NewlineKey, style.newline}
+		if event != nil {
+			attrs = append(attrs, "event_id", int(event.ID), "line", event.LineNumber)
+		}
+		simulator.whenApplied(func(simulator *Simulator) {
+			simulator.logger.Warn(warning.Message, attrs...)
+		})
+	}
+}
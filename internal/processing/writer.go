@@ -0,0 +1,87 @@
+package processing
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// WriteEventsFile writes all processed events, including generated ones, to a file in chronological order
+func (simulator *Simulator) WriteEventsFile(filePath string) error {
+	headerWritten := simulator.RaceInfo == nil
+	return simulator.writeEvents(filePath, func(writer io.Writer, event *domain.Event) error {
+		if !headerWritten {
+			headerWritten = true
+			if _, err := io.WriteString(writer, simulator.RaceInfo.MarshalLine()+"\n"); err != nil {
+				return err
+			}
+		}
+		_, err := io.WriteString(writer, event.MarshalLine()+"\n")
+		return err
+	})
+}
+
+// WriteEventsBinaryFile writes all processed events as length-prefixed binary frames in chronological order
+func (simulator *Simulator) WriteEventsBinaryFile(filePath string) error {
+	return simulator.writeEvents(filePath, domain.WriteEventFrame)
+}
+
+// writeEvents writes the chronologically sorted events to a file using the given encoder
+func (simulator *Simulator) writeEvents(filePath string, writeEvent func(io.Writer, *domain.Event) error) (err error) {
+	events := make([]*domain.Event, len(simulator.Events))
+	copy(events, simulator.Events)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	dir := filepath.Dir(filePath)
+	if mkdirErr := os.MkdirAll(dir, 0755); mkdirErr != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, mkdirErr)
+	}
+
+	var file *os.File
+	file, err = os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create event file %s: %w", filePath, err)
+	}
+	defer func() {
+		closeErr := file.Close()
+		if err == nil && closeErr != nil {
+			err = fmt.Errorf("error closing event file %s: %w", filePath, closeErr)
+		} else if closeErr != nil {
+			simulator.logger.Error(fmt.Sprintf("Additional error while closing event file %s: %v (original error: %v)", filePath, closeErr, err),
+				"file", filePath)
+		}
+	}()
+
+	var output io.Writer = file
+	var gzipWriter *gzip.Writer
+	if strings.HasSuffix(filePath, ".gz") {
+		gzipWriter = gzip.NewWriter(file)
+		output = gzipWriter
+	}
+
+	writer := bufio.NewWriter(output)
+	for _, event := range events {
+		if err = writeEvent(writer, event); err != nil {
+			return fmt.Errorf("error writing event to file %s: %w", filePath, err)
+		}
+	}
+
+	if err = writer.Flush(); err != nil {
+		return fmt.Errorf("error flushing buffer to event file %s: %w", filePath, err)
+	}
+	if gzipWriter != nil {
+		if err = gzipWriter.Close(); err != nil {
+			return fmt.Errorf("error finishing compressed event file %s: %w", filePath, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package processing
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"biathlonPrototype/internal/domain"
+)
+
+func TestWriteEventsFileRoundTrip(t *testing.T) {
+	simulator := newTestSimulator(t)
+	mustProcess(t, simulator, readExample(t))
+	path := filepath.Join(t.TempDir(), "events.log")
+	if err := simulator.WriteEventsFile(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != len(simulator.Events) {
+		t.Fatalf("wrote %d lines for %d events", len(lines), len(simulator.Events))
+	}
+	finished := 0
+	var previous *domain.Event
+	for _, line := range lines {
+		event, err := domain.ParseEventFromString(line)
+		if err != nil {
+			t.Fatalf("written line %q does not parse: %v", line, err)
+		}
+		if event.MarshalLine() != line {
+			t.Errorf("line %q is marshalled as %q", line, event.MarshalLine())
+		}
+		if previous != nil && event.Timestamp.Before(previous.Timestamp) {
+			t.Errorf("line %q is written after the later %q", line, previous.MarshalLine())
+		}
+		if event.ID == domain.Finished {
+			finished++
+		}
+		previous = event
+	}
+	if finished != 5 {
+		t.Errorf("wrote %d Finished events, want 5", finished)
+	}
+}
+
+func TestWriteEventsBinaryFileMatchesTheTextFile(t *testing.T) {
+	simulator := newTestSimulator(t)
+	mustProcess(t, simulator, readExample(t))
+	dir := t.TempDir()
+	textPath, binaryPath := filepath.Join(dir, "events.log"), filepath.Join(dir, "events.pb")
+	if err := simulator.WriteEventsFile(textPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := simulator.WriteEventsBinaryFile(binaryPath); err != nil {
+		t.Fatal(err)
+	}
+	text, err := os.ReadFile(textPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(binaryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var lines []string
+	for {
+		event, err := domain.ReadEventFrame(reader)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, event.MarshalLine())
+	}
+	if got := strings.Join(lines, "\n") + "\n"; got != string(text) {
+		t.Errorf("binary events differ from the text file:\n%s\nwant:\n%s", got, text)
+	}
+}
@@ -0,0 +1,172 @@
+// Package racegen generates synthetic, internally consistent event logs for demos and load tests
+package racegen
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"biathlonPrototype/internal/config"
+	"biathlonPrototype/internal/domain"
+)
+
+// StartInterval is the time between the drawn start times of consecutive competitors
+const StartInterval = 30 * time.Second
+
+// DNFRate is the share of competitors who cannot continue somewhere on the course
+const DNFRate = 0.05
+
+// dnfReasons are the comments of the generated CannotContinue events
+var dnfReasons = []string{"Lost in the forest", "Broken ski", "Broken pole", "Illness", "Fall"}
+
+// athlete holds the drawn abilities of one generated competitor
+type athlete struct {
+	id       int
+	speed    float64
+	accuracy float64
+	dnfOnLap int
+	timeline time.Time
+}
+
+// generator draws the events of a race from a seeded source of randomness
+type generator struct {
+	cfg    *config.Config
+	random *rand.Rand
+	events []*domain.Event
+}
+
+// Generate creates the events of an individual, pursuit or mass start race with the given number of competitors:
+// registrations, drawn start times, laps, shooting, penalty loops and occasional competitors who cannot continue.
+// The same configuration, number and seed always produce the same events, ordered by timestamp
+func Generate(cfg *config.Config, competitors int, seed int64) ([]*domain.Event, error) {
+	if cfg.IsRelay() {
+		return nil, fmt.Errorf("relays cannot be generated")
+	}
+	if competitors <= 0 {
+		return nil, fmt.Errorf("number of competitors should be > 0, got %d", competitors)
+	}
+
+	gen := &generator{cfg: cfg, random: rand.New(rand.NewSource(seed))}
+
+	// Everybody registers within 20 minutes, then the start times are drawn 10 minutes before the start
+	registration := cfg.ParsedStart.Add(-30 * time.Minute)
+	registrationGap := 20 * time.Minute / time.Duration(competitors)
+	draw := cfg.ParsedStart.Add(-10 * time.Minute)
+	order := gen.random.Perm(competitors)
+	for i, id := range gen.random.Perm(competitors) {
+		registration = registration.Add(gen.duration(0, registrationGap))
+		gen.add(registration, domain.Register, id+1)
+
+		start := cfg.ParsedStart
+		if !cfg.IsMassStart() {
+			start = cfg.ParsedStart.Add(time.Duration(order[i]) * StartInterval)
+		}
+		gen.race(&athlete{
+			id:       id + 1,
+			speed:    gen.normal(4.6, 0.25),
+			accuracy: math.Min(gen.normal(0.85, 0.07), 1),
+			dnfOnLap: gen.dnfLap(),
+			timeline: draw,
+		}, start)
+	}
+
+	sort.SliceStable(gen.events, func(i, j int) bool {
+		return gen.events[i].Timestamp.Before(gen.events[j].Timestamp)
+	})
+	return gen.events, nil
+}
+
+// race adds the events of one competitor from the start time draw to the finish
+func (gen *generator) race(competitor *athlete, start time.Time) {
+	if !gen.cfg.IsMassStart() {
+		gen.add(competitor.timeline, domain.SetStartTime, competitor.id, strings.Trim(domain.FormatTime(start), "[]"))
+	}
+	gen.add(start.Add(-gen.duration(20*time.Second, time.Minute)), domain.OnStartLine, competitor.id)
+	competitor.timeline = start.Add(gen.duration(0, time.Second))
+	gen.add(competitor.timeline, domain.Started, competitor.id)
+
+	laps := gen.cfg.TotalLaps()
+	firingRange := 1
+	for lap := 1; lap <= laps; lap++ {
+		lapTime := gen.skiTime(gen.cfg.LapLen, competitor.speed)
+		competitor.timeline = competitor.timeline.Add(lapTime * 3 / 5)
+		if lap == competitor.dnfOnLap {
+			gen.add(competitor.timeline, domain.CannotContinue, competitor.id, strings.Fields(dnfReasons[gen.random.Intn(len(dnfReasons))])...)
+			return
+		}
+		for ; firingRange <= gen.cfg.FiringLines && rangeLap(firingRange, gen.cfg.FiringLines, laps) == lap; firingRange++ {
+			gen.shoot(competitor, firingRange)
+		}
+		competitor.timeline = competitor.timeline.Add(lapTime * 2 / 5)
+		gen.add(competitor.timeline, domain.EndLap, competitor.id)
+	}
+}
+
+// shoot adds the events of one firing range and of the penalty loops for its misses
+func (gen *generator) shoot(competitor *athlete, firingRange int) {
+	gen.add(competitor.timeline, domain.EnterFiringRange, competitor.id, strconv.Itoa(firingRange))
+	competitor.timeline = competitor.timeline.Add(gen.duration(8*time.Second, 15*time.Second))
+	misses := 0
+	for target := 1; target <= 5; target++ {
+		competitor.timeline = competitor.timeline.Add(gen.duration(2*time.Second, 5*time.Second))
+		if gen.random.Float64() < competitor.accuracy {
+			gen.add(competitor.timeline, domain.HitTarget, competitor.id, strconv.Itoa(target))
+		} else {
+			misses++
+		}
+	}
+	competitor.timeline = competitor.timeline.Add(gen.duration(2*time.Second, 6*time.Second))
+	gen.add(competitor.timeline, domain.LeaveFiringRange, competitor.id)
+	if misses == 0 {
+		return
+	}
+
+	competitor.timeline = competitor.timeline.Add(gen.duration(time.Second, 3*time.Second))
+	gen.add(competitor.timeline, domain.EnterPenaltyLaps, competitor.id)
+	competitor.timeline = competitor.timeline.Add(gen.skiTime(float64(misses)*gen.cfg.PenaltyLen, competitor.speed*0.9))
+	gen.add(competitor.timeline, domain.LeavePenaltyLaps, competitor.id)
+}
+
+// rangeLap returns the lap on which a firing range is shot when the ranges are spread evenly over the laps
+func rangeLap(firingRange, firingLines, laps int) int {
+	return (firingRange-1)*laps/firingLines + 1
+}
+
+// dnfLap draws the lap on which a competitor cannot continue, or 0 if the competitor finishes
+func (gen *generator) dnfLap() int {
+	if gen.random.Float64() >= DNFRate {
+		return 0
+	}
+	return gen.random.Intn(gen.cfg.TotalLaps()) + 1
+}
+
+// skiTime returns the time to cover a distance at about the given speed
+func (gen *generator) skiTime(distance, speed float64) time.Duration {
+	seconds := distance / math.Max(gen.normal(speed, speed*0.03), 1)
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond)
+}
+
+// normal draws a normally distributed value
+func (gen *generator) normal(mean, deviation float64) float64 {
+	return mean + gen.random.NormFloat64()*deviation
+}
+
+// duration draws a duration between min and max, rounded to milliseconds
+func (gen *generator) duration(min, max time.Duration) time.Duration {
+	return (min + time.Duration(gen.random.Int63n(int64(max-min)+1))).Round(time.Millisecond)
+}
+
+// add appends an event
+func (gen *generator) add(at time.Time, id domain.EventID, competitorID int, parameters ...string) {
+	gen.events = append(gen.events, &domain.Event{
+		Timestamp:       at,
+		ID:              id,
+		CompetitorID:    competitorID,
+		ExtraParameters: parameters,
+		IsIncoming:      true,
+	})
+}
@@ -0,0 +1,74 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// GenerateAlignedReport creates the final report like GenerateReport, but with a header line and every field padded
+// to the width of its column so it lines up in a monospace view. The lap columns cover the given number of laps or
+// the most laps of any competitor, followed by the average speed of finishers over the course and, if startTimes is
+// set, the scheduled and actual start and the start diff. Times and speeds are written in the given format
+func GenerateAlignedReport(competitors []*domain.Competitor, laps int, format Format, startTimes bool) []string {
+	format = format.resolve(competitors)
+	gaps := format.gaps(competitors)
+	behind := formatBehind(competitors, gaps, format)
+
+	rows := make([][]string, 0, len(competitors))
+	for _, competitor := range competitors {
+		lapCells := formatLaps(competitor.LapDetails, competitor.Status, competitor.CurrentLap, 0, format)
+		laps = max(laps, len(lapCells))
+		row := []string{formatPlace(gaps[competitor.ID].Place), format.result(competitor), behind[competitor.ID], competitorLabel(competitor)}
+		row = append(row, lapCells...)
+		rows = append(rows, row)
+	}
+
+	header := []string{"Place", "Result", "Behind", "Competitor"}
+	for lap := 1; lap <= laps; lap++ {
+		header = append(header, fmt.Sprintf("Lap %d", lap))
+	}
+	header = append(header, "Penalty", "Shooting", "Speed")
+	lapColumns := len(header) - 3
+	if startTimes {
+		header = append(header, "Scheduled start", "Actual start", "Start diff")
+	}
+	for i, competitor := range competitors {
+		for len(rows[i]) < lapColumns {
+			rows[i] = append(rows[i], "")
+		}
+		speed := ""
+		if _, finished := gaps[competitor.ID]; finished {
+			speed = format.speed(competitor.OverallSpeed)
+		}
+		rows[i] = append(rows[i], formatPenaltyDetails(competitor.PenaltyDetails, competitor.TotalPenaltyLaps, format),
+			formatShooting(competitor.TotalHits, competitor.TotalShots, competitor.TotalSpareRounds), speed)
+		if startTimes {
+			scheduledStart, actualStart, startDiff := "", "", ""
+			if diff, ok := competitor.StartDiff(); ok {
+				scheduledStart, actualStart, startDiff = formatOptionalTime(competitor.ScheduledStartTime),
+					domain.FormatTime(competitor.ActualStartTime), formatStartDiff(diff)
+			}
+			rows[i] = append(rows[i], scheduledStart, actualStart, startDiff)
+		}
+	}
+
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	lines := make([]string, 0, len(rows)+1)
+	for _, row := range append([][]string{header}, rows...) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+	return lines
+}
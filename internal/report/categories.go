@@ -0,0 +1,56 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// OpenCategory collects the competitors without an age category
+const OpenCategory = "Open"
+
+// CategoryOf returns the age category of a competitor, or OpenCategory if there is none
+func CategoryOf(competitor *domain.Competitor) string {
+	if competitor.Category == "" {
+		return OpenCategory
+	}
+	return competitor.Category
+}
+
+// GroupByCategory splits competitors sorted by result into their age categories, keeping the order within each.
+// The configured categories are included even without competitors; the names are returned sorted, Open last
+func GroupByCategory(competitors []*domain.Competitor, configured []string) (map[string][]*domain.Competitor, []string) {
+	byCategory := make(map[string][]*domain.Competitor)
+	for _, category := range configured {
+		byCategory[category] = nil
+	}
+	for _, competitor := range competitors {
+		category := CategoryOf(competitor)
+		byCategory[category] = append(byCategory[category], competitor)
+	}
+
+	names := make([]string, 0, len(byCategory))
+	for category := range byCategory {
+		names = append(names, category)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == OpenCategory) != (names[j] == OpenCategory) {
+			return names[j] == OpenCategory
+		}
+		return names[i] < names[j]
+	})
+	return byCategory, names
+}
+
+// GenerateCategories creates a classification per age category with its own places and gaps for the finishers; the
+// other competitors of the category follow without a place
+func GenerateCategories(competitors []*domain.Competitor, configured []string) []string {
+	byCategory, names := GroupByCategory(competitors, configured)
+	categoryLines := make([]string, 0)
+	for _, category := range names {
+		categoryLines = append(categoryLines, "", fmt.Sprintf("Category %s:", category))
+		categoryLines = append(categoryLines, GenerateReport(byCategory[category])...)
+	}
+	return categoryLines
+}
@@ -0,0 +1,162 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// GenerateCSV creates the final report as CSV with a header row and one row per competitor sorted by result. The lap
+// columns are padded to the given number of laps (or the most laps of any competitor) and laps without a recorded end
+// are empty. Place, total time and the average speed over the course are only filled for finishers and the start
+// columns only for competitors who started; the status column tells the others apart. The shooting details, the
+// firing ranges up to the highest one entered, the split times and the status history follow. Places
+// are those of all competitors, even if the options select only some of them
+func GenerateCSV(competitors []*domain.Competitor, laps int, options Options) ([]byte, error) {
+	selected := options.Select(competitors)
+	ranges := 0
+	for _, competitor := range selected {
+		laps = max(laps, len(competitor.LapDetails))
+		for _, detail := range competitor.RangeDetails {
+			ranges = max(ranges, detail.Range)
+		}
+	}
+
+	header := []string{"place", "id", "status", "total_time", "speed", "scheduled_start", "actual_start", "start_diff"}
+	for lap := 1; lap <= laps; lap++ {
+		header = append(header, fmt.Sprintf("lap%d_time", lap), fmt.Sprintf("lap%d_speed", lap))
+	}
+	header = append(header, "penalty_time", "penalty_laps", "hits", "shots", "name", "nation", "team",
+		"prone_hits", "prone_shots", "standing_hits", "standing_shots", "range_time", "shooting_time",
+		"average_shot_interval", "slowest_shot_interval")
+	for rangeNum := 1; rangeNum <= ranges; rangeNum++ {
+		for _, column := range []string{"position", "hits", "shots", "time", "shooting_time", "shot_intervals", "penalty_loops", "penalty_transition"} {
+			header = append(header, fmt.Sprintf("range%d_%s", rangeNum, column))
+		}
+	}
+	for lap := 1; lap <= laps; lap++ {
+		header = append(header, fmt.Sprintf("lap%d_splits", lap))
+	}
+	header = append(header, "history")
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write(header); err != nil {
+		return nil, fmt.Errorf("error writing the CSV header: %w", err)
+	}
+
+	gaps := ComputeGaps(competitors)
+	for _, competitor := range selected {
+		row := make([]string, 0, len(header))
+		placeStr, totalTimeStr, speedStr := "", "", ""
+		if gap, ok := gaps[competitor.ID]; ok {
+			totalTime, _ := competitor.CalculateTotalTime()
+			placeStr, totalTimeStr = strconv.Itoa(gap.Place), domain.FormatDuration(totalTime)
+			speedStr = fmt.Sprintf("%.3f", competitor.OverallSpeed)
+		}
+		row = append(row, placeStr, strconv.Itoa(competitor.ID), string(competitor.Status), totalTimeStr, speedStr)
+		scheduledStartStr, actualStartStr, startDiffStr := "", "", ""
+		if startDiff, ok := competitor.StartDiff(); ok {
+			actualStartStr, startDiffStr = competitor.ActualStartTime.Format(domain.TimeLayout), formatStartDiff(startDiff)
+			if !competitor.ScheduledStartTime.IsZero() {
+				scheduledStartStr = competitor.ScheduledStartTime.Format(domain.TimeLayout)
+			}
+		}
+		row = append(row, scheduledStartStr, actualStartStr, startDiffStr)
+
+		for lap := 0; lap < laps; lap++ {
+			if lap < len(competitor.LapDetails) && competitor.LapDetails[lap].Duration > 0 {
+				detail := competitor.LapDetails[lap]
+				row = append(row, domain.FormatDuration(detail.Duration), fmt.Sprintf("%.3f", detail.Speed))
+			} else {
+				row = append(row, "", "")
+			}
+		}
+
+		penaltyTimeStr := ""
+		if competitor.TotalPenaltyTime > 0 {
+			penaltyTimeStr = domain.FormatDuration(competitor.TotalPenaltyTime)
+		}
+		row = append(row, penaltyTimeStr, strconv.Itoa(competitor.TotalPenaltyLaps),
+			strconv.Itoa(competitor.TotalHits), strconv.Itoa(competitor.TotalShots))
+		row = append(row, competitor.Name, competitor.Nation, competitor.Team)
+		proneHits, proneShots := competitor.ShootingResult(domain.PositionProne)
+		standingHits, standingShots := competitor.ShootingResult(domain.PositionStanding)
+		var shootingTime time.Duration
+		for _, detail := range competitor.RangeDetails {
+			shootingTime += detail.ShootingTime
+		}
+		averageInterval, slowestInterval, _ := competitor.ShotIntervalStats()
+		row = append(row, strconv.Itoa(proneHits), strconv.Itoa(proneShots), strconv.Itoa(standingHits), strconv.Itoa(standingShots),
+			csvDuration(competitor.TotalRangeTime), csvDuration(shootingTime), csvDuration(averageInterval), csvDuration(slowestInterval))
+		row = append(row, csvRanges(competitor, ranges)...)
+		for lap := 1; lap <= laps; lap++ {
+			splits := make([]string, 0, len(competitor.Splits(lap)))
+			for _, split := range competitor.Splits(lap) {
+				splits = append(splits, fmt.Sprintf("%d=%s", split.Index, domain.FormatDuration(split.Duration)))
+			}
+			row = append(row, strings.Join(splits, " "))
+		}
+		history := make([]string, 0, len(competitor.History))
+		for _, change := range competitor.History {
+			history = append(history, fmt.Sprintf("%s %s>%s", change.Time.Format(domain.TimeLayout), change.From, change.To))
+		}
+		row = append(row, strings.Join(history, "; "))
+		if err := writer.Write(row); err != nil {
+			return nil, fmt.Errorf("error writing the CSV row of competitor %d: %w", competitor.ID, err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("error writing the CSV report: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// csvRanges returns the columns of the firing ranges 1 to ranges: the position, hits, shots, time on the range,
+// time from the first to the last shot and the shot intervals of the latest visit of each range, and the loops and
+// transition of the penalty session after it. Ranges the competitor did not shoot are empty
+func csvRanges(competitor *domain.Competitor, ranges int) []string {
+	row := make([]string, 0, ranges*8)
+	for rangeNum := 1; rangeNum <= ranges; rangeNum++ {
+		index := -1
+		for i, detail := range competitor.RangeDetails {
+			if detail.Range == rangeNum {
+				index = i
+			}
+		}
+		if index < 0 {
+			row = append(row, "", "", "", "", "", "", "", "")
+			continue
+		}
+		detail := competitor.RangeDetails[index]
+		intervals := make([]string, 0, len(detail.ShotIntervals))
+		for _, interval := range detail.ShotIntervals {
+			intervals = append(intervals, domain.FormatDuration(interval))
+		}
+		row = append(row, string(detail.Position), strconv.Itoa(detail.Hits), strconv.Itoa(detail.Shots),
+			csvDuration(detail.Duration()), csvDuration(detail.ShootingTime), strings.Join(intervals, " "))
+		loopsStr, transitionStr := "", ""
+		for _, session := range competitor.PenaltySessions {
+			if session.Range == rangeNum {
+				loopsStr, transitionStr = strconv.Itoa(session.Loops), csvDuration(session.Transition)
+			}
+		}
+		row = append(row, loopsStr, transitionStr)
+	}
+	return row
+}
+
+// csvDuration formats a duration for a CSV cell; zero durations are unknown and empty
+func csvDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return domain.FormatDuration(d)
+}
@@ -0,0 +1,51 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+)
+
+func TestCSVGolden(t *testing.T) {
+	data, err := GenerateCSV(exampleCompetitors(t), 2, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "example.csv", data)
+
+	data, err = GenerateCSV(raceCompetitors(t, detailedRace), 2, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "detailed.csv", data)
+}
+
+func TestCSVLeavesResultColumnsOfNonFinishersEmpty(t *testing.T) {
+	data, err := GenerateCSV(raceCompetitors(t, detailedRace), 2, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want a header and two rows", len(records))
+	}
+	header, row := records[0], records[2]
+	columns := make(map[string]string, len(header))
+	for i, name := range header {
+		columns[name] = row[i]
+	}
+	if columns["id"] != "2" || columns["status"] != "NotFinished" {
+		t.Fatalf("last row is competitor %s with status %s, want 2 NotFinished", columns["id"], columns["status"])
+	}
+	for _, name := range []string{"place", "total_time", "speed", "lap1_time", "lap1_speed", "range1_hits"} {
+		if columns[name] != "" {
+			t.Errorf("%s is %q, want an empty cell", name, columns[name])
+		}
+	}
+	if columns["actual_start"] != "10:01:30.000" || columns["start_diff"] != "00:00:00.000" {
+		t.Errorf("actual start %q and start diff %q", columns["actual_start"], columns["start_diff"])
+	}
+}
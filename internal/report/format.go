@@ -0,0 +1,141 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// Format controls how times and speeds are written in the classification. Durations are rounded (or truncated) to
+// TimeDecimals decimals of a second before anything is derived from them, so gaps are the differences of the shown
+// times and equal shown times never show different gaps. DropHours leaves out the hours ("31:49.2" instead of
+// "00:31:49.285") if every time of the report is under an hour after rounding
+type Format struct {
+	SpeedDecimals int
+	TimeDecimals  int
+	Round         bool
+	DropHours     bool
+}
+
+// DefaultFormat writes speeds with three decimals and times rounded to "HH:MM:SS.sss"
+var DefaultFormat = Format{SpeedDecimals: 3, TimeDecimals: 3, Round: true}
+
+// format returns the format of the options, DefaultFormat if unset
+func (options Options) format() Format {
+	if options.Format == nil {
+		return DefaultFormat
+	}
+	return *options.Format
+}
+
+// resolve returns the format to use for the competitors: DropHours is cleared if any of their total, lap or penalty
+// times is an hour or longer after rounding
+func (format Format) resolve(competitors []*domain.Competitor) Format {
+	if !format.DropHours {
+		return format
+	}
+	for _, competitor := range competitors {
+		times := []time.Duration{competitor.PenaltyDetails.TotalDuration}
+		if totalTime, ok := competitor.CalculateTotalTime(); ok {
+			times = append(times, totalTime)
+		}
+		for _, lap := range competitor.LapDetails {
+			times = append(times, lap.Duration)
+		}
+		for _, t := range times {
+			if format.round(t) >= time.Hour {
+				format.DropHours = false
+				return format
+			}
+		}
+	}
+	return format
+}
+
+// unit is the smallest duration shown
+func (format Format) unit() time.Duration {
+	unit := time.Second
+	for i := 0; i < format.TimeDecimals && unit > time.Nanosecond; i++ {
+		unit /= 10
+	}
+	return unit
+}
+
+// round rounds or truncates a duration to the shown precision
+func (format Format) round(d time.Duration) time.Duration {
+	if format.Round {
+		return d.Round(format.unit())
+	}
+	return d.Truncate(format.unit())
+}
+
+// duration formats a duration with the shown precision, as "HH:MM:SS.sss" or "MM:SS.sss" when dropping the hours
+func (format Format) duration(d time.Duration) string {
+	d = format.round(d)
+	h := d / time.Hour
+	d -= h * time.Hour
+	m := d / time.Minute
+	d -= m * time.Minute
+	s := d / time.Second
+	d -= s * time.Second
+
+	var builder strings.Builder
+	if !format.DropHours {
+		fmt.Fprintf(&builder, "%02d:", h)
+	}
+	fmt.Fprintf(&builder, "%02d:%02d", m, s)
+	if format.TimeDecimals > 0 {
+		fmt.Fprintf(&builder, ".%0*d", format.TimeDecimals, d/format.unit())
+	}
+	return builder.String()
+}
+
+// speed formats a speed with the shown number of decimals
+func (format Format) speed(speed float64) string {
+	return fmt.Sprintf("%.*f", format.SpeedDecimals, speed)
+}
+
+// result formats the total time of a finisher, or the final status of the others
+func (format Format) result(competitor *domain.Competitor) string {
+	if competitor.Status == domain.StatusFinished {
+		if totalTime, ok := competitor.CalculateTotalTime(); ok {
+			return format.duration(totalTime)
+		}
+	}
+	return competitor.FinalStatusString()
+}
+
+// totals returns the total times of the competitors who have one by competitor ID, rounded to the shown precision.
+// Places, gaps and the summary are all taken from these times, so they agree with the times shown
+func (format Format) totals(competitors []*domain.Competitor) map[int]time.Duration {
+	totals := make(map[int]time.Duration)
+	for _, competitor := range competitors {
+		if totalTime, ok := competitor.CalculateTotalTime(); ok {
+			totals[competitor.ID] = format.round(totalTime)
+		}
+	}
+	return totals
+}
+
+// gaps returns the places and gaps of ComputeGaps taken from the rounded total times, so competitors whose shown
+// times are equal share a place and never show different gaps
+func (format Format) gaps(competitors []*domain.Competitor) map[int]Gap {
+	totals := format.totals(competitors)
+	places := assignPlaces(competitors, totals)
+	gaps := make(map[int]Gap)
+	var leaderTime, previousTime time.Duration
+	for _, competitor := range competitors {
+		totalTime, ok := totals[competitor.ID]
+		if !ok {
+			continue
+		}
+		if len(gaps) == 0 {
+			leaderTime, previousTime = totalTime, totalTime
+		}
+		gaps[competitor.ID] = Gap{Place: places[competitor.ID], ToLeader: totalTime - leaderTime, ToPrevious: totalTime - previousTime, Winner: len(gaps) == 0}
+		previousTime = totalTime
+	}
+	return gaps
+}
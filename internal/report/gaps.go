@@ -0,0 +1,46 @@
+package report
+
+import (
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// Gap is the place of a finished competitor and the time it is behind the winner and the competitor one place ahead.
+// Winner marks the first finisher; competitors tied with it share place 1 but are not the winner
+type Gap struct {
+	Place      int
+	ToLeader   time.Duration
+	ToPrevious time.Duration
+	Winner     bool
+}
+
+// ComputeGaps returns the places and gaps of the finished competitors by competitor ID, for competitors sorted by
+// result. The winner has place 1 and zero gaps; places are assigned by AssignPlaces and competitors without a total
+// time get no entry
+func ComputeGaps(competitors []*domain.Competitor) map[int]Gap {
+	return DefaultFormat.gaps(competitors)
+}
+
+// formatStartDiff formats the StartDiff of a competitor, with a "-" sign if the competitor started before the
+// common race start
+func formatStartDiff(startDiff time.Duration) string {
+	if startDiff < 0 {
+		return "-" + domain.FormatDuration(-startDiff)
+	}
+	return domain.FormatDuration(startDiff)
+}
+
+// FormatGap formats a gap as "+" followed by the duration, "+00:00:00.000" for a tie. Callers leave out the gap of
+// the winner themselves
+func FormatGap(gap time.Duration) string {
+	return "+" + domain.FormatDuration(gap)
+}
+
+// Behind formats the gap to the winner, or "" for the winner
+func (gap Gap) Behind() string {
+	if gap.Winner {
+		return ""
+	}
+	return FormatGap(gap.ToLeader)
+}
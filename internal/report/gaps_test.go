@@ -0,0 +1,101 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+// tiedRace lets competitors 1 and 2 ski the same course in the same time, 90 seconds apart, and competitor 3 finish
+// ten seconds behind them
+const tiedRace = `
+	[09:00:00.000] 1 1
+	[09:00:00.000] 1 2
+	[09:00:00.000] 1 3
+	[09:00:01.000] 2 1 10:00:00.000
+	[09:00:01.000] 2 2 10:01:30.000
+	[09:00:01.000] 2 3 10:03:00.000
+	[09:59:00.000] 3 1
+	[10:00:00.000] 4 1
+	[10:00:30.000] 3 2
+	[10:01:30.000] 4 2
+	[10:02:30.000] 3 3
+	[10:03:00.000] 4 3
+	[10:10:00.000] 10 1
+	[10:11:30.000] 10 2
+	[10:13:10.000] 10 3
+	[10:20:00.000] 10 1
+	[10:21:30.000] 10 2
+	[10:23:10.000] 10 3`
+
+func TestTieWithTheWinnerKeepsAZeroGap(t *testing.T) {
+	competitors := raceCompetitors(t, tiedRace)
+	gaps := ComputeGaps(competitors)
+	if behind := gaps[1].Behind(); behind != "" {
+		t.Errorf("winner is %q behind, want no gap", behind)
+	}
+	if behind := gaps[2].Behind(); behind != "+00:00:00.000" {
+		t.Errorf("tied competitor is %q behind, want +00:00:00.000", behind)
+	}
+	if gaps[2].Place != 1 {
+		t.Errorf("tied competitor has place %d, want 1", gaps[2].Place)
+	}
+
+	data, err := GenerateJSON(competitors, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var document struct {
+		Competitors []struct {
+			ID       int    `json:"id"`
+			Behind   string `json:"behind"`
+			BehindMs *int64 `json:"behindMs"`
+		} `json:"competitors"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatal(err)
+	}
+	winner, tied := document.Competitors[0], document.Competitors[1]
+	if winner.Behind != "" || winner.BehindMs != nil {
+		t.Errorf("JSON winner is %q (%v) behind, want no gap", winner.Behind, winner.BehindMs)
+	}
+	if tied.Behind != "+00:00:00.000" || tied.BehindMs == nil || *tied.BehindMs != 0 {
+		t.Errorf("JSON tied competitor is %q (%v) behind, want +00:00:00.000 and 0", tied.Behind, tied.BehindMs)
+	}
+
+	data, err = GenerateXML(competitors, RaceParameters{}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var race XMLRace
+	if err := xml.Unmarshal(data, &race); err != nil {
+		t.Fatal(err)
+	}
+	if behind := race.Results.Results[1].Behind; behind != "+00:00:00.000" {
+		t.Errorf("XML tied competitor is %q behind, want +00:00:00.000", behind)
+	}
+
+	html, err := GenerateHTML(competitors, RaceParameters{}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(html), "<td>&#43;00:00:00.000</td>") {
+		t.Errorf("HTML has no zero gap for the tied competitor:\n%s", html)
+	}
+
+	markdown := strings.Join(GenerateMarkdown(competitors, Options{}), "\n")
+	if !strings.Contains(markdown, "+00:00:00.000") {
+		t.Errorf("Markdown has no zero gap for the tied competitor:\n%s", markdown)
+	}
+
+	podium := GeneratePodium(competitors)
+	if len(podium) < 3 || podium[1] != "Gold: 1, 2 00:20:00.000" || !strings.HasSuffix(podium[2], "00:20:10.000 +00:00:10.000") {
+		t.Errorf("podium %q", podium)
+	}
+
+	laps := GenerateLapStandings(competitors, 1)
+	if len(laps) < 3 || !strings.HasSuffix(laps[1], "00:10:00.000") || !strings.HasSuffix(laps[2], "00:10:00.000 +00:00:00.000") {
+		t.Errorf("lap standings %q", laps)
+	}
+}
@@ -2,41 +2,299 @@ package report
 
 import (
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
+	"time"
 
 	"biathlonPrototype/internal/domain"
 )
 
-// GenerateReport creates the final report as a slice of lines
+// GenerateReport creates the final report as a slice of lines, each starting with the place of a finished competitor
+// or "-"; WriteReport streams the same lines
 func GenerateReport(competitors []*domain.Competitor) []string {
-	reportLines := make([]string, 0, len(competitors))
+	var builder strings.Builder
+	// Writing to a strings.Builder cannot fail
+	_ = WriteReport(&builder, competitors, Options{})
+	if builder.Len() == 0 {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(builder.String(), "\n"), "\n")
+}
+
+// WriteReport writes the final report line by line for competitors sorted by result, with the competitors selected
+// and ordered by the options and each line formatted by the options' template (DefaultTemplate if unset); places and
+// gaps are those of all competitors. It stops at the first write error
+func WriteReport(w io.Writer, competitors []*domain.Competitor, options Options) error {
+	reportTemplate := options.Template
+	if reportTemplate == nil {
+		reportTemplate = DefaultTemplate
+	}
+	format := options.format().resolve(competitors)
+	gaps := format.gaps(competitors)
+	behind := formatBehind(competitors, gaps, format)
+	selected := options.Select(competitors)
+	summary := format.summarize(competitors, selected)
+
+	if err := reportTemplate.executeSection(w, "header", summary); err != nil {
+		return err
+	}
+	for _, competitor := range selected {
+		gap, finished := gaps[competitor.ID]
+		data := templateData(competitor, gap, finished, behind[competitor.ID], format)
+		if err := reportTemplate.executeLine(w, data); err != nil {
+			return fmt.Errorf("error writing the result of competitor %d: %w", competitor.ID, err)
+		}
+	}
+	return reportTemplate.executeSection(w, "footer", summary)
+}
+
+// GenerateLegacyReport creates the final report in the original layout parsed by older scripts: no places, no gaps
+// to the winner and no number of penalty loops
+func GenerateLegacyReport(competitors []*domain.Competitor) []string {
+	var builder strings.Builder
+	// Writing to a strings.Builder cannot fail
+	_ = WriteReport(&builder, competitors, Options{Template: LegacyTemplate})
+	if builder.Len() == 0 {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(builder.String(), "\n"), "\n")
+}
+
+// formatBehind formats the gap to the winner of every finisher after the winner by competitor ID. Ties with the
+// winner still show a zero gap, only the winner has none
+func formatBehind(competitors []*domain.Competitor, gaps map[int]Gap, format Format) map[int]string {
+	behind := make(map[int]string)
+	for _, competitor := range competitors {
+		if gap, finished := gaps[competitor.ID]; finished && !gap.Winner {
+			behind[competitor.ID] = "+" + format.duration(gap.ToLeader)
+		}
+	}
+	return behind
+}
+
+// GenerateAnnotations creates an annotations section listing the false starts and equipment incidents of each competitor
+func GenerateAnnotations(competitors []*domain.Competitor) []string {
+	annotationLines := make([]string, 0)
+
+	for _, competitor := range competitors {
+		if competitor.FalseStartMargin > 0 {
+			annotationLines = append(annotationLines, fmt.Sprintf("%s competitor(%d): false start by %.3f s",
+				domain.FormatTime(competitor.ActualStartTime), competitor.ID, competitor.FalseStartMargin.Seconds()))
+		}
+		for _, incident := range competitor.Incidents {
+			annotationLines = append(annotationLines, fmt.Sprintf("%s competitor(%d): %s",
+				domain.FormatTime(incident.Time), competitor.ID, incident.Description))
+		}
+	}
+
+	if len(annotationLines) == 0 {
+		return annotationLines
+	}
+	return append([]string{"", "Annotations:"}, annotationLines...)
+}
+
+// GenerateDetails creates a section with the registration and start times of each competitor
+func GenerateDetails(competitors []*domain.Competitor) []string {
+	detailLines := make([]string, 0, len(competitors))
+
+	for _, competitor := range competitors {
+		bestLap, _, _ := competitor.BestLap()
+		shotIntervals := "-"
+		if average, slowest, ok := competitor.ShotIntervalStats(); ok {
+			shotIntervals = fmt.Sprintf("average %s, slowest %s", domain.FormatDuration(average), domain.FormatDuration(slowest))
+		}
+		detailLines = append(detailLines, fmt.Sprintf("competitor(%d): registered %s, scheduled start %s, actual start %s, accuracy %s, shot interval %s, average speed %.3f, laps %s",
+			competitor.ID, formatOptionalTime(competitor.RegistrationTime), formatOptionalTime(competitor.ScheduledStartTime),
+			formatOptionalTime(competitor.ActualStartTime), domain.FormatAccuracy(competitor.TotalHits, competitor.TotalShots),
+			shotIntervals, competitor.AverageSpeed, formatLapDetails(competitor.LapDetails, competitor.Status, competitor.CurrentLap, bestLap, DefaultFormat)))
+		if competitor.Status == domain.StatusNotFinished {
+			detailLines[len(detailLines)-1] += fmt.Sprintf(", distance %.0f", competitor.Distance)
+		}
+		if !competitor.Disqualification.IsZero() {
+			detailLines[len(detailLines)-1] += formatDisqualification(competitor)
+		}
+	}
+
+	if len(detailLines) == 0 {
+		return detailLines
+	}
+	return append([]string{"", "Competitors:"}, detailLines...)
+}
+
+// formatDisqualification formats the disqualification record of a competitor for the details section
+func formatDisqualification(competitor *domain.Competitor) string {
+	disqualification := competitor.Disqualification
+	line := fmt.Sprintf(", disqualified %s (%s)", domain.FormatTime(disqualification.Time), competitor.DisqualificationReason)
+	if disqualification.Trigger != "" {
+		line += fmt.Sprintf(" by '%s'", disqualification.Trigger)
+	}
+	if disqualification.Rule != "" {
+		line += ", rule " + disqualification.Rule
+	}
+	return line
+}
+
+// GenerateHistory creates a section listing the status changes of each competitor
+func GenerateHistory(competitors []*domain.Competitor) []string {
+	historyLines := make([]string, 0)
+
+	for _, competitor := range competitors {
+		for _, change := range competitor.History {
+			historyLines = append(historyLines, fmt.Sprintf("%s competitor(%d): %s -> %s (event %d)",
+				domain.FormatTime(change.Time), competitor.ID, change.From, change.To, change.EventID))
+		}
+	}
+
+	if len(historyLines) == 0 {
+		return historyLines
+	}
+	return append([]string{"", "Status history:"}, historyLines...)
+}
+
+// formatOptionalTime formats a time, or "-" if it is not set
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return domain.FormatTime(t)
+}
+
+// GenerateSplits creates a section listing the intermediate split times of each competitor per lap
+func GenerateSplits(competitors []*domain.Competitor) []string {
+	splitLines := make([]string, 0)
 
 	for _, competitor := range competitors {
-		reportLines = append(reportLines, formatCompetitorResult(competitor))
+		for lap := 1; lap <= len(competitor.LapSplits); lap++ {
+			splits := competitor.Splits(lap)
+			if len(splits) == 0 {
+				continue
+			}
+			parts := make([]string, 0, len(splits))
+			for _, split := range splits {
+				parts = append(parts, fmt.Sprintf("{%d, %s}", split.Index, domain.FormatDuration(split.Duration)))
+			}
+			splitLines = append(splitLines, fmt.Sprintf("competitor(%d) lap %d: [%s]", competitor.ID, lap, strings.Join(parts, ", ")))
+		}
 	}
 
-	return reportLines
+	if len(splitLines) == 0 {
+		return splitLines
+	}
+	return append([]string{"", "Splits:"}, splitLines...)
 }
 
-// formatCompetitorResult formats the report string for a single competitor
-func formatCompetitorResult(competitor *domain.Competitor) string {
-	finalStatus := competitor.FinalStatusString()
+// GenerateRangeDetails creates a section with the shooting result of every firing range of each competitor
+func GenerateRangeDetails(competitors []*domain.Competitor) []string {
+	rangeLines := make([]string, 0)
 
-	lapDetailsStr := formatLapDetails(competitor.LapDetails, competitor.Status, competitor.CurrentLap)
-	penaltyDetailsStr := formatPenaltyDetails(competitor.PenaltyDetails, competitor.TotalPenaltyLaps > 0)
-	shootingStr := fmt.Sprintf("%d/%d", competitor.TotalHits, competitor.TotalShots)
+	for _, competitor := range competitors {
+		for _, detail := range competitor.RangeDetails {
+			position := ""
+			if detail.Position != domain.PositionUnknown {
+				position = " " + string(detail.Position)
+			}
+			line := fmt.Sprintf("competitor(%d) range %d%s: %s (%s), %d missed, %s-%s %s",
+				competitor.ID, detail.Range, position, formatShooting(detail.Hits, detail.Shots, detail.SpareRounds),
+				domain.FormatAccuracy(detail.Hits, detail.Shots), detail.Misses,
+				formatOptionalTime(detail.EnterTime), domain.FormatTime(detail.LeaveTime), domain.FormatDuration(detail.Duration()))
+			if detail.Incomplete {
+				line += " [Incomplete]"
+			}
+			rangeLines = append(rangeLines, line)
+		}
+		if len(competitor.RangeDetails) > 0 {
+			rangeLines = append(rangeLines, fmt.Sprintf("competitor(%d) total time on the ranges: %s",
+				competitor.ID, domain.FormatDuration(competitor.TotalRangeTime)))
+		}
+	}
 
-	return fmt.Sprintf("%s %d %s %s %s",
-		finalStatus,
-		competitor.ID,
-		lapDetailsStr,
-		penaltyDetailsStr,
-		shootingStr,
-	)
+	if len(rangeLines) == 0 {
+		return rangeLines
+	}
+	return append([]string{"", "Firing ranges:"}, rangeLines...)
 }
 
-// formatLapDetails formats lap details
-func formatLapDetails(lapDetails []domain.LapDetail, status domain.CompetitorStatus, currentLap int) string {
+// GeneratePenaltySessions creates a section listing every pass through the penalty loops of each competitor
+func GeneratePenaltySessions(competitors []*domain.Competitor) []string {
+	sessionLines := make([]string, 0)
+
+	for _, competitor := range competitors {
+		for _, session := range competitor.PenaltySessions {
+			rangeStr := ""
+			if session.Range > 0 {
+				rangeStr = fmt.Sprintf(" range %d", session.Range)
+			}
+			line := fmt.Sprintf("competitor(%d) lap %d%s: %d loops {%s, %.3f}",
+				competitor.ID, session.Lap, rangeStr, session.Loops, domain.FormatDuration(session.Duration()), session.Speed)
+			if session.Transition > 0 {
+				line += ", transition " + domain.FormatDuration(session.Transition)
+			}
+			if session.Incomplete {
+				line += " [Incomplete]"
+			}
+			sessionLines = append(sessionLines, line)
+		}
+	}
+
+	if len(sessionLines) == 0 {
+		return sessionLines
+	}
+	return append([]string{"", "Penalty loops:"}, sessionLines...)
+}
+
+// GenerateLegs creates a section with the per-leg breakdown of each relay team
+func GenerateLegs(competitors []*domain.Competitor, lapsPerLeg int) []string {
+	legLines := make([]string, 0)
+
+	for _, competitor := range competitors {
+		for _, leg := range competitor.Legs {
+			legTime := ""
+			if leg.Duration() > 0 {
+				legTime = domain.FormatDuration(leg.Duration())
+			}
+			firstLap := min((leg.Leg-1)*lapsPerLeg, len(competitor.LapDetails))
+			lastLap := min(leg.Leg*lapsPerLeg, len(competitor.LapDetails))
+			legLines = append(legLines, fmt.Sprintf("team(%d) leg %d: %s %s %s, %d penalty laps",
+				competitor.ID, leg.Leg, legTime,
+				formatLapDetails(competitor.LapDetails[firstLap:lastLap], domain.StatusFinished, 0, 0, DefaultFormat),
+				formatShooting(leg.Hits, leg.Shots, leg.SpareRounds), leg.PenaltyLaps))
+		}
+	}
+
+	if len(legLines) == 0 {
+		return legLines
+	}
+	return append([]string{"", "Relay legs:"}, legLines...)
+}
+
+// competitorLabel returns the competitor ID followed by the nation in parentheses and the name, if known
+func competitorLabel(competitor *domain.Competitor) string {
+	label := strconv.Itoa(competitor.ID)
+	if competitor.Nation != "" {
+		label += " (" + competitor.Nation + ")"
+	}
+	if competitor.Name != "" {
+		label += " " + competitor.Name
+	}
+	return label
+}
+
+// formatShooting formats the shooting result as hits/shots, followed by +spares if spare rounds were used
+func formatShooting(hits, shots, spareRounds int) string {
+	if spareRounds > 0 {
+		return fmt.Sprintf("%d/%d+%d", hits, shots, spareRounds)
+	}
+	return fmt.Sprintf("%d/%d", hits, shots)
+}
+
+// formatLapDetails formats lap details, marking the best lap (numbered from 1, 0 for none) with an asterisk
+func formatLapDetails(lapDetails []domain.LapDetail, status domain.CompetitorStatus, currentLap, bestLap int, format Format) string {
+	return fmt.Sprintf("[%s]", strings.Join(formatLaps(lapDetails, status, currentLap, bestLap, format), ", "))
+}
+
+// formatLaps formats every lap as {time, speed}, with "{,}" for laps started but without a recorded end
+func formatLaps(lapDetails []domain.LapDetail, status domain.CompetitorStatus, currentLap, bestLap int, format Format) []string {
 	var parts []string
 	numLapsCompleted := len(lapDetails)
 	totalExpectedLapEntries := numLapsCompleted
@@ -49,27 +307,43 @@ func formatLapDetails(lapDetails []domain.LapDetail, status domain.CompetitorSta
 
 	for i := 0; i < totalExpectedLapEntries; i++ {
 		if i < len(lapDetails) && lapDetails[i].Duration > 0 {
-			lapTimeStr := domain.FormatDuration(lapDetails[i].Duration)
-			lapSpeedStr := fmt.Sprintf("%.3f", lapDetails[i].Speed)
-			parts = append(parts, fmt.Sprintf("{%s, %s}", lapTimeStr, lapSpeedStr))
+			lapTimeStr := format.duration(lapDetails[i].Duration)
+			lapSpeedStr := format.speed(lapDetails[i].Speed)
+			lapStr := fmt.Sprintf("{%s, %s}", lapTimeStr, lapSpeedStr)
+			if i+1 == bestLap {
+				lapStr += "*"
+			}
+			parts = append(parts, lapStr)
 		} else {
 			parts = append(parts, "{,}")
 		}
 	}
 
-	return fmt.Sprintf("[%s]", strings.Join(parts, ", "))
+	return parts
+}
+
+// formatPenaltyDetails formats the number of penalty loops with their total time and average speed; time spent in
+// the penalty loops without completing one is shown with zero loops
+func formatPenaltyDetails(penalty domain.PenaltyDetail, loops int, format Format) string {
+	if loops == 0 && penalty.TotalDuration <= 0 {
+		return "{,}"
+	}
+	if penalty.TotalDuration <= 0 {
+		return fmt.Sprintf("{%d, %s, %s}", loops, format.duration(0), format.speed(0))
+	}
+	return fmt.Sprintf("{%d, %s, %s}", loops, format.duration(penalty.TotalDuration), format.speed(penalty.AverageSpeed))
 }
 
-// formatPenaltyDetails formats the penalty information
-func formatPenaltyDetails(penalty domain.PenaltyDetail, hadPenalties bool) string {
-	if !hadPenalties {
+// formatLegacyPenaltyDetails formats the penalty information of the legacy layout
+func formatLegacyPenaltyDetails(penalty domain.PenaltyDetail, hadPenalties bool, format Format) string {
+	if !hadPenalties && penalty.TotalDuration <= 0 {
 		return "{,}"
 	}
 	if penalty.TotalDuration <= 0 {
-		return fmt.Sprintf("{%s, %.3f}", domain.FormatDuration(0), 0.0)
+		return fmt.Sprintf("{%s, %s}", format.duration(0), format.speed(0))
 	}
 
-	penaltyTimeStr := domain.FormatDuration(penalty.TotalDuration)
-	penaltySpeedStr := fmt.Sprintf("%.3f", penalty.AverageSpeed)
+	penaltyTimeStr := format.duration(penalty.TotalDuration)
+	penaltySpeedStr := format.speed(penalty.AverageSpeed)
 	return fmt.Sprintf("{%s, %s}", penaltyTimeStr, penaltySpeedStr)
 }
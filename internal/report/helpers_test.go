@@ -0,0 +1,104 @@
+package report
+
+import (
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"biathlonPrototype/internal/config"
+	"biathlonPrototype/internal/domain"
+	"biathlonPrototype/internal/processing"
+)
+
+// update rewrites the golden files with the current output instead of comparing against them
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// detailedRace lets competitor 1 pass a split point, shoot both ranges with reported shots and ski one penalty loop;
+// competitor 2 starts 90 seconds later and cannot continue on the first lap
+const detailedRace = `
+	[09:00:00.000] 1 1
+	[09:00:01.000] 1 2
+	[09:00:02.000] 2 1 10:00:00.000
+	[09:00:03.000] 2 2 10:01:30.000
+	[09:59:00.000] 3 1
+	[10:00:00.000] 4 1
+	[10:00:30.000] 3 2
+	[10:01:30.000] 4 2
+	[10:03:00.000] 14 1 1
+	[10:05:00.000] 5 1 1 P
+	[10:05:05.000] 12 1
+	[10:05:06.000] 6 1 1
+	[10:05:09.000] 12 1
+	[10:05:10.000] 6 1 2
+	[10:05:12.000] 12 1
+	[10:05:13.000] 6 1 3
+	[10:05:16.000] 12 1
+	[10:05:17.000] 6 1 4
+	[10:05:21.000] 12 1
+	[10:05:22.000] 6 1 5
+	[10:05:30.000] 7 1
+	[10:07:00.000] 11 2 fell
+	[10:10:00.000] 10 1
+	[10:15:00.000] 5 1 2 S
+	[10:15:10.000] 6 1 1
+	[10:15:20.000] 6 1 2
+	[10:15:30.000] 6 1 3
+	[10:15:40.000] 6 1 4
+	[10:16:00.000] 7 1
+	[10:16:05.000] 8 1
+	[10:16:35.000] 9 1
+	[10:20:00.000] 10 1`
+
+// exampleCompetitors runs the example race and returns its competitors sorted by result
+func exampleCompetitors(t *testing.T) []*domain.Competitor {
+	t.Helper()
+	simulator := newSimulator(t)
+	if err := simulator.LoadEventsFromFile(filepath.Join("..", "..", "testdata", "events.log")); err != nil {
+		t.Fatal(err)
+	}
+	return simulator.GetSortedCompetitors()
+}
+
+// raceCompetitors runs the event lines of the text in the example configuration and returns the competitors sorted
+// by result
+func raceCompetitors(t *testing.T, text string) []*domain.Competitor {
+	t.Helper()
+	simulator := newSimulator(t)
+	if err := simulator.ProcessEventsFromReader(strings.NewReader(strings.ReplaceAll(text, "\t", ""))); err != nil {
+		t.Fatal(err)
+	}
+	return simulator.GetSortedCompetitors()
+}
+
+// newSimulator creates a simulator for the example configuration that logs nothing
+func newSimulator(t *testing.T) *processing.Simulator {
+	t.Helper()
+	cfg, err := config.LoadConfiguration(filepath.Join("..", "..", "testdata", "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return processing.NewSimulator(cfg, processing.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+}
+
+// checkGolden compares output with the golden file testdata/name, or rewrites it with -update
+func checkGolden(t *testing.T, name string, output []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, output, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if string(output) != string(want) {
+		t.Errorf("output differs from %s:\n%s\nwant:\n%s", path, output, want)
+	}
+}
@@ -0,0 +1,175 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strconv"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// RaceParameters describes the race in the header of the HTML report
+type RaceParameters struct {
+	Title       string
+	Laps        int
+	LapLen      float64
+	PenaltyLen  float64
+	FiringLines int
+	Start       string
+	StartDelta  string
+}
+
+// htmlPage is the data of the HTML report template
+type htmlPage struct {
+	Race   RaceParameters
+	Podium []PodiumPlace
+	Rows   []htmlRow
+}
+
+// htmlRow is one competitor in the results table with the lines of its expandable details
+type htmlRow struct {
+	Place    string
+	ID       int
+	Name     string
+	Nation   string
+	Result   string
+	Behind   string
+	Speed    string
+	Penalty  string
+	Shooting string
+	Laps     []htmlLap
+	Ranges   []htmlRange
+}
+
+// htmlLap is a main lap in the details of a competitor
+type htmlLap struct {
+	Lap      int
+	Duration string
+	Speed    string
+}
+
+// htmlRange is a firing range in the details of a competitor
+type htmlRange struct {
+	Range    int
+	Position string
+	Shooting string
+	Accuracy string
+	Duration string
+}
+
+// htmlTemplate renders a standalone results page; html/template escapes every value taken from the event file
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Race.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+h1 { margin-bottom: 0.2em; }
+.parameters { color: #555; margin-bottom: 1.5em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { padding: 0.4em 0.8em; text-align: left; border-bottom: 1px solid #ddd; vertical-align: top; }
+th { background: #f0f0f0; }
+td.number { text-align: right; font-variant-numeric: tabular-nums; }
+details table { width: auto; margin: 0.5em 0; }
+summary { cursor: pointer; }
+.podium { list-style: none; padding: 0; font-size: 1.2em; }
+</style>
+</head>
+<body>
+<h1>{{.Race.Title}}</h1>
+<p class="parameters">{{.Race.Laps}} laps of {{printf "%.0f" .Race.LapLen}} m, {{.Race.FiringLines}} firing lines, penalty loop {{printf "%.0f" .Race.PenaltyLen}} m{{if .Race.Start}}, start {{.Race.Start}}{{end}}{{if .Race.StartDelta}}, interval {{.Race.StartDelta}}{{end}}</p>
+{{- if .Podium}}
+<ul class="podium">
+{{- range .Podium}}
+<li>{{.Symbol}} {{.Labels}} — {{.Result}}</li>
+{{- end}}
+</ul>
+{{- end}}
+<table>
+<thead>
+<tr><th>Place</th><th>Bib</th><th>Name</th><th>Nation</th><th>Result</th><th>Behind</th><th>Speed</th><th>Penalty</th><th>Shooting</th><th>Details</th></tr>
+</thead>
+<tbody>
+{{- range .Rows}}
+<tr>
+<td class="number">{{.Place}}</td><td class="number">{{.ID}}</td><td>{{.Name}}</td><td>{{.Nation}}</td><td>{{.Result}}</td><td>{{.Behind}}</td><td class="number">{{.Speed}}</td><td>{{.Penalty}}</td><td>{{.Shooting}}</td>
+<td>{{if or .Laps .Ranges}}<details><summary>Laps and shooting</summary>
+{{- if .Laps}}
+<table><tr><th>Lap</th><th>Time</th><th>Speed</th></tr>
+{{- range .Laps}}
+<tr><td class="number">{{.Lap}}</td><td>{{.Duration}}</td><td class="number">{{.Speed}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Ranges}}
+<table><tr><th>Range</th><th>Position</th><th>Shooting</th><th>Accuracy</th><th>Time</th></tr>
+{{- range .Ranges}}
+<tr><td class="number">{{.Range}}</td><td>{{.Position}}</td><td>{{.Shooting}}</td><td>{{.Accuracy}}</td><td>{{.Duration}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</details>{{end}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+</body>
+</html>
+`))
+
+// GenerateHTML creates the final report as a standalone HTML page for competitors sorted by result, with the podium
+// above the results if the options ask for it. Places and gaps
+// are those of all competitors, even if the options select only some of them
+func GenerateHTML(competitors []*domain.Competitor, race RaceParameters, options Options) ([]byte, error) {
+	if race.Title == "" {
+		race.Title = "Race results"
+	}
+	selected := options.Select(competitors)
+	page := htmlPage{Race: race, Rows: make([]htmlRow, 0, len(selected))}
+	if options.Podium {
+		page.Podium = Podium(competitors)
+	}
+	gaps := ComputeGaps(competitors)
+	for _, competitor := range selected {
+		row := htmlRow{
+			ID:       competitor.ID,
+			Name:     competitor.Name,
+			Nation:   competitor.Nation,
+			Result:   competitor.FinalStatusString(),
+			Shooting: formatShooting(competitor.TotalHits, competitor.TotalShots, competitor.TotalSpareRounds),
+		}
+		if competitor.TotalPenaltyLaps > 0 || competitor.TotalPenaltyTime > 0 {
+			row.Penalty = fmt.Sprintf("%d loops, %s", competitor.TotalPenaltyLaps, domain.FormatDuration(competitor.TotalPenaltyTime))
+		}
+		if gap, ok := gaps[competitor.ID]; ok {
+			row.Place = strconv.Itoa(gap.Place)
+			row.Behind = gap.Behind()
+			row.Speed = fmt.Sprintf("%.3f", competitor.OverallSpeed)
+		}
+		for i, lap := range competitor.LapDetails {
+			entry := htmlLap{Lap: i + 1}
+			if lap.Duration > 0 {
+				entry.Duration, entry.Speed = domain.FormatDuration(lap.Duration), fmt.Sprintf("%.3f", lap.Speed)
+			}
+			row.Laps = append(row.Laps, entry)
+		}
+		for _, detail := range competitor.RangeDetails {
+			row.Ranges = append(row.Ranges, htmlRange{
+				Range:    detail.Range,
+				Position: string(detail.Position),
+				Shooting: formatShooting(detail.Hits, detail.Shots, detail.SpareRounds),
+				Accuracy: domain.FormatAccuracy(detail.Hits, detail.Shots),
+				Duration: domain.FormatDuration(detail.Duration()),
+			})
+		}
+		page.Rows = append(page.Rows, row)
+	}
+
+	var buffer bytes.Buffer
+	if err := htmlTemplate.Execute(&buffer, page); err != nil {
+		return nil, fmt.Errorf("error rendering the HTML report: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
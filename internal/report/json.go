@@ -0,0 +1,82 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// JSONReport is the structured final report: a summary of the race followed by the competitors in result order
+type JSONReport struct {
+	Summary     Summary      `json:"summary"`
+	Competitors []JSONResult `json:"competitors"`
+}
+
+// JSONResult is the result of one competitor: the domain encoding of the competitor with the place and gap to the
+// winner, the start diff included in the total time and the durations repeated in milliseconds. Place is only set for
+// finishers and the gap for finishers after the winner, ties included; the start diff only for competitors who started
+type JSONResult struct {
+	Place int `json:"place,omitempty"`
+	domain.CompetitorJSON
+	TotalTimeMs   int64     `json:"totalTimeMs,omitempty"`
+	Behind        string    `json:"behind,omitempty"`
+	BehindMs      *int64    `json:"behindMs,omitempty"`
+	StartDiff     string    `json:"startDiff,omitempty"`
+	StartDiffMs   *int64    `json:"startDiffMs,omitempty"`
+	PenaltyTimeMs int64     `json:"penaltyTimeMs,omitempty"`
+	Laps          []JSONLap `json:"laps"`
+}
+
+// JSONLap is a main lap of the domain encoding with its duration and the race time at its end in milliseconds and
+// the position there; the added fields are omitted for laps without a recorded end
+type JSONLap struct {
+	domain.LapJSON
+	DurationMs int64 `json:"durationMs,omitempty"`
+	ElapsedMs  int64 `json:"elapsedMs,omitempty"`
+	Position   int   `json:"position,omitempty"`
+}
+
+// GenerateJSON creates the final report as an indented JSON document for competitors sorted by result. Places,
+// gaps and positions are those of all competitors, even if the options select only some of them
+func GenerateJSON(competitors []*domain.Competitor, options Options) ([]byte, error) {
+	selected := options.Select(competitors)
+	document := JSONReport{Summary: DefaultFormat.summarize(competitors, selected), Competitors: make([]JSONResult, 0, len(selected))}
+	gaps := ComputeGaps(competitors)
+	lapPositions := LapPositions(competitors)
+	for _, competitor := range selected {
+		result := JSONResult{
+			CompetitorJSON: competitor.JSON(),
+			PenaltyTimeMs:  competitor.TotalPenaltyTime.Milliseconds(),
+		}
+		if startDiff, ok := competitor.StartDiff(); ok {
+			startDiffMs := startDiff.Milliseconds()
+			result.StartDiff, result.StartDiffMs = formatStartDiff(startDiff), &startDiffMs
+		}
+		if gap, ok := gaps[competitor.ID]; ok {
+			totalTime, _ := competitor.CalculateTotalTime()
+			result.Place, result.TotalTimeMs = gap.Place, totalTime.Milliseconds()
+			if !gap.Winner {
+				behindMs := gap.ToLeader.Milliseconds()
+				result.Behind, result.BehindMs = gap.Behind(), &behindMs
+			}
+		}
+		result.Laps = make([]JSONLap, 0, len(result.CompetitorJSON.Laps))
+		for i, lap := range result.CompetitorJSON.Laps {
+			entry := JSONLap{LapJSON: lap, DurationMs: competitor.LapDetails[i].Duration.Milliseconds()}
+			if i < len(lapPositions[competitor.ID]) {
+				if position := lapPositions[competitor.ID][i]; position.Place > 0 {
+					entry.ElapsedMs, entry.Position = position.Elapsed.Milliseconds(), position.Place
+				}
+			}
+			result.Laps = append(result.Laps, entry)
+		}
+		document.Competitors = append(document.Competitors, result)
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding the JSON report: %w", err)
+	}
+	return data, nil
+}
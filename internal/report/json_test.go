@@ -0,0 +1,129 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestJSONReportEmbedsTheDomainEncoding(t *testing.T) {
+	competitors := exampleCompetitors(t)
+	data, err := GenerateJSON(competitors, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var document struct {
+		Competitors []map[string]json.RawMessage `json:"competitors"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatal(err)
+	}
+	if len(document.Competitors) != len(competitors) {
+		t.Fatalf("got %d competitors, want %d", len(document.Competitors), len(competitors))
+	}
+
+	for i, result := range document.Competitors {
+		domainData, err := competitors[i].MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var domainFields map[string]json.RawMessage
+		if err := json.Unmarshal(domainData, &domainFields); err != nil {
+			t.Fatal(err)
+		}
+		for key, value := range domainFields {
+			if key == "laps" {
+				continue
+			}
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, result[key]); err != nil {
+				t.Fatalf("competitor %d: %s: %v", competitors[i].ID, key, err)
+			}
+			if compact.String() != string(value) {
+				t.Errorf("competitor %d: %s is %s, want the domain value %s", competitors[i].ID, key, result[key], value)
+			}
+		}
+		for _, key := range []string{"scheduledStart", "actualStart", "penalty", "shooting"} {
+			if _, ok := result[key]; ok {
+				t.Errorf("competitor %d: unexpected field %s", competitors[i].ID, key)
+			}
+		}
+	}
+}
+
+func TestJSONReportCarriesSplitsPositionsAndPenaltySessions(t *testing.T) {
+	data, err := GenerateJSON(raceCompetitors(t, detailedRace), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var document struct {
+		Competitors []struct {
+			ID                  int    `json:"id"`
+			Place               int    `json:"place"`
+			Status              string `json:"status"`
+			ShootingTime        string `json:"shootingTime"`
+			SlowestShotInterval string `json:"slowestShotInterval"`
+			PenaltyLaps         int    `json:"penaltyLaps"`
+			Prone               *struct {
+				Hits int `json:"hits"`
+			} `json:"prone"`
+			Standing *struct {
+				Hits int `json:"hits"`
+			} `json:"standing"`
+			Ranges []struct {
+				ShotIntervals []string `json:"shotIntervals"`
+			} `json:"ranges"`
+			PenaltySessions []struct {
+				Transition string `json:"transition"`
+			} `json:"penaltySessions"`
+			History []json.RawMessage `json:"history"`
+			Laps    []struct {
+				Duration   string `json:"duration"`
+				DurationMs int64  `json:"durationMs"`
+				Position   int    `json:"position"`
+				Splits     []struct {
+					Split int    `json:"split"`
+					Time  string `json:"time"`
+				} `json:"splits"`
+			} `json:"laps"`
+		} `json:"competitors"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(document.Competitors) != 2 {
+		t.Fatalf("got %d competitors, want 2", len(document.Competitors))
+	}
+	winner := document.Competitors[0]
+	if winner.ID != 1 || winner.Place != 1 {
+		t.Fatalf("first competitor is %d with place %d, want 1 with place 1", winner.ID, winner.Place)
+	}
+	if winner.Prone == nil || winner.Prone.Hits != 5 || winner.Standing == nil || winner.Standing.Hits != 4 {
+		t.Errorf("prone %+v and standing %+v, want 5 and 4 hits", winner.Prone, winner.Standing)
+	}
+	if len(winner.Laps) != 2 || winner.Laps[0].DurationMs != 600000 || winner.Laps[0].Position != 1 {
+		t.Fatalf("laps %+v, want two with the first of 600000 ms in position 1", winner.Laps)
+	}
+	if splits := winner.Laps[0].Splits; len(splits) != 1 || splits[0].Split != 1 || splits[0].Time != "00:03:00.000" {
+		t.Errorf("splits of the first lap %+v, want split 1 at 00:03:00.000", splits)
+	}
+	if len(winner.Ranges) != 2 || !slices.Equal(winner.Ranges[0].ShotIntervals, []string{"00:00:04.000", "00:00:03.000", "00:00:04.000", "00:00:05.000"}) {
+		t.Errorf("ranges %+v, want the shot intervals of the first range", winner.Ranges)
+	}
+	if winner.ShootingTime != "00:00:16.000" || winner.SlowestShotInterval != "00:00:05.000" {
+		t.Errorf("shooting time %q and slowest interval %q", winner.ShootingTime, winner.SlowestShotInterval)
+	}
+	if winner.PenaltyLaps != 1 || len(winner.PenaltySessions) != 1 || winner.PenaltySessions[0].Transition != "00:00:05.000" {
+		t.Errorf("penalty laps %d and sessions %+v, want one with a transition of 00:00:05.000", winner.PenaltyLaps, winner.PenaltySessions)
+	}
+	if len(winner.History) == 0 {
+		t.Error("no status history")
+	}
+
+	other := document.Competitors[1]
+	if other.Place != 0 || other.Status != "NotFinished" {
+		t.Errorf("competitor %d has place %d and status %q, want no place and NotFinished", other.ID, other.Place, other.Status)
+	}
+}
@@ -0,0 +1,184 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// FastestLap is the fastest lap of the day across all competitors
+type FastestLap struct {
+	CompetitorID int
+	Lap          int
+	Detail       domain.LapDetail
+}
+
+// FindFastestLap returns the fastest lap across all competitors, skipping laps without a recorded duration.
+// Ties go to the lower competitor ID, then the earlier lap. False if no lap has a duration
+func FindFastestLap(competitors []*domain.Competitor) (FastestLap, bool) {
+	var fastest FastestLap
+	found := false
+	for _, competitor := range competitors {
+		lap, detail, ok := competitor.BestLap()
+		if !ok {
+			continue
+		}
+		if !found || detail.Duration < fastest.Detail.Duration ||
+			(detail.Duration == fastest.Detail.Duration && competitor.ID < fastest.CompetitorID) {
+			fastest = FastestLap{CompetitorID: competitor.ID, Lap: lap, Detail: detail}
+			found = true
+		}
+	}
+	return fastest, found
+}
+
+// GenerateFastestLap creates a section naming the fastest lap of the day
+func GenerateFastestLap(competitors []*domain.Competitor) []string {
+	fastest, ok := FindFastestLap(competitors)
+	if !ok {
+		return []string{}
+	}
+	return []string{"", "Fastest lap:", fmt.Sprintf("competitor(%d) lap %d: {%s, %.3f}",
+		fastest.CompetitorID, fastest.Lap, domain.FormatDuration(fastest.Detail.Duration), fastest.Detail.Speed)}
+}
+
+// LapPosition is the race time of a competitor at the end of a lap and the position among all competitors there
+type LapPosition struct {
+	Lap     int
+	Elapsed time.Duration
+	// Place is zero for laps without a recorded end
+	Place int
+}
+
+// LapPositions returns the cumulative time and position of every competitor after each lap up to the last lap any
+// competitor completed, by competitor ID. Equal times are ordered by competitor ID; nil if nobody completed a lap
+func LapPositions(competitors []*domain.Competitor) map[int][]LapPosition {
+	lastLap := 0
+	for _, competitor := range competitors {
+		for lap := len(competitor.LapDetails); lap > lastLap; lap-- {
+			if _, ok := competitor.ElapsedAfterLap(lap); ok {
+				lastLap = lap
+				break
+			}
+		}
+	}
+	if lastLap == 0 {
+		return nil
+	}
+
+	positions := make(map[int][]LapPosition, len(competitors))
+	for _, competitor := range competitors {
+		entries := make([]LapPosition, lastLap)
+		for lap := 1; lap <= lastLap; lap++ {
+			entries[lap-1].Lap = lap
+			entries[lap-1].Elapsed, _ = competitor.ElapsedAfterLap(lap)
+		}
+		positions[competitor.ID] = entries
+	}
+
+	for lap := 1; lap <= lastLap; lap++ {
+		ranked := make([]*domain.Competitor, 0, len(competitors))
+		for _, competitor := range competitors {
+			if positions[competitor.ID][lap-1].Elapsed > 0 {
+				ranked = append(ranked, competitor)
+			}
+		}
+		sort.SliceStable(ranked, func(i, j int) bool {
+			elapsed1, elapsed2 := positions[ranked[i].ID][lap-1].Elapsed, positions[ranked[j].ID][lap-1].Elapsed
+			if elapsed1 != elapsed2 {
+				return elapsed1 < elapsed2
+			}
+			return ranked[i].ID < ranked[j].ID
+		})
+		for i, competitor := range ranked {
+			positions[competitor.ID][lap-1].Place = i + 1
+		}
+	}
+	return positions
+}
+
+// GenerateLapPositions creates a section with the cumulative time and position of each competitor after every lap.
+// A change of position since the previous lap is shown as places gained (+) or lost (-); missing laps show "-"
+func GenerateLapPositions(competitors []*domain.Competitor) []string {
+	positions := LapPositions(competitors)
+	if positions == nil {
+		return []string{}
+	}
+
+	positionLines := make([]string, 0, len(competitors))
+	for _, competitor := range competitors {
+		parts := make([]string, 0, len(positions[competitor.ID]))
+		previousPlace := 0
+		for _, position := range positions[competitor.ID] {
+			if position.Place == 0 {
+				parts = append(parts, fmt.Sprintf("lap %d -", position.Lap))
+				previousPlace = 0
+				continue
+			}
+			part := fmt.Sprintf("lap %d %s %d.", position.Lap, domain.FormatDuration(position.Elapsed), position.Place)
+			if previousPlace > 0 && position.Place != previousPlace {
+				part += fmt.Sprintf(" (%+d)", previousPlace-position.Place)
+			}
+			parts = append(parts, part)
+			previousPlace = position.Place
+		}
+		positionLines = append(positionLines, fmt.Sprintf("competitor(%d): %s", competitor.ID, strings.Join(parts, ", ")))
+	}
+	return append([]string{"", "Lap positions:"}, positionLines...)
+}
+
+// GenerateLapStandings creates the standings after the given lap: the competitors who completed it ranked by their
+// race time at its end, with the gap to the first, followed by the others ordered by the laps they completed and
+// their race time at the end of their latest completed lap. Competitors with equal times share a place
+func GenerateLapStandings(competitors []*domain.Competitor, lap int) []string {
+	type lapStanding struct {
+		competitor *domain.Competitor
+		laps       int
+		elapsed    time.Duration
+	}
+	standings := make([]lapStanding, 0, len(competitors))
+	for _, competitor := range competitors {
+		standing := lapStanding{competitor: competitor}
+		for completed := min(lap, len(competitor.LapDetails)); completed >= 1; completed-- {
+			if elapsed, ok := competitor.ElapsedAfterLap(completed); ok {
+				standing.laps, standing.elapsed = completed, elapsed
+				break
+			}
+		}
+		standings = append(standings, standing)
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].laps != standings[j].laps {
+			return standings[i].laps > standings[j].laps
+		}
+		if standings[i].elapsed != standings[j].elapsed {
+			return standings[i].elapsed < standings[j].elapsed
+		}
+		return standings[i].competitor.ID < standings[j].competitor.ID
+	})
+
+	lines := []string{fmt.Sprintf("Standings after lap %d:", lap)}
+	place := 0
+	for i, standing := range standings {
+		if standing.laps < lap {
+			line := fmt.Sprintf("- competitor(%d) %s, %d laps", standing.competitor.ID, standing.competitor.Status, standing.laps)
+			if standing.laps > 0 {
+				line += ", " + domain.FormatDuration(standing.elapsed)
+			}
+			lines = append(lines, line)
+			continue
+		}
+		if i == 0 || standing.elapsed.Milliseconds() != standings[i-1].elapsed.Milliseconds() {
+			place = i + 1
+		}
+		line := fmt.Sprintf("%d. competitor(%d) %s", place, standing.competitor.ID, domain.FormatDuration(standing.elapsed))
+		if i > 0 {
+			line += " " + FormatGap(standing.elapsed-standings[0].elapsed)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,116 @@
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// markdownColumn is a column of the Markdown results table
+type markdownColumn struct {
+	title string
+	right bool
+}
+
+// markdownColumns are the columns of the Markdown results table; numbers and times are right-aligned
+var markdownColumns = []markdownColumn{
+	{title: "Place", right: true},
+	{title: "Competitor"},
+	{title: "Total time", right: true},
+	{title: "Behind", right: true},
+	{title: "Speed", right: true},
+	{title: "Shooting", right: true},
+	{title: "Penalty loops", right: true},
+}
+
+// GenerateMarkdown creates the final report as a Markdown table for competitors sorted by result, followed by the
+// number of starters and finishers and, if the options ask for it, preceded by the podium. Competitors who did not finish show their result instead of a total time.
+// Places and gaps are those of all competitors, even if the options select only some of them
+func GenerateMarkdown(competitors []*domain.Competitor, options Options) []string {
+	selected := options.Select(competitors)
+	format := options.format().resolve(competitors)
+	gaps := format.gaps(competitors)
+	rows := make([][]string, 0, len(selected))
+	for _, competitor := range selected {
+		place, behind, speed := "", "", ""
+		if gap, ok := gaps[competitor.ID]; ok {
+			place, speed = strconv.Itoa(gap.Place), format.speed(competitor.OverallSpeed)
+			if !gap.Winner {
+				behind = "+" + format.duration(gap.ToLeader)
+			}
+		}
+		rows = append(rows, []string{
+			place,
+			competitorLabel(competitor),
+			format.result(competitor),
+			behind,
+			speed,
+			formatShooting(competitor.TotalHits, competitor.TotalShots, competitor.TotalSpareRounds),
+			strconv.Itoa(competitor.TotalPenaltyLaps),
+		})
+	}
+
+	widths := make([]int, len(markdownColumns))
+	header := make([]string, len(markdownColumns))
+	for i, column := range markdownColumns {
+		header[i] = column.title
+		widths[i] = max(utf8.RuneCountInString(column.title), 3)
+	}
+	for _, row := range rows {
+		for i := range row {
+			row[i] = escapeMarkdown(row[i])
+			widths[i] = max(widths[i], utf8.RuneCountInString(row[i]))
+		}
+	}
+
+	delimiter := make([]string, len(markdownColumns))
+	for i, column := range markdownColumns {
+		if column.right {
+			delimiter[i] = strings.Repeat("-", widths[i]-1) + ":"
+		} else {
+			delimiter[i] = strings.Repeat("-", widths[i])
+		}
+	}
+
+	lines := make([]string, 0, len(rows)+4)
+	if options.Podium {
+		for _, place := range Podium(competitors) {
+			lines = append(lines, fmt.Sprintf("- %s %s — %s", place.Symbol(), escapeMarkdown(place.Labels()), place.Result()))
+		}
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+	}
+	lines = append(lines, formatMarkdownRow(header, widths), "| "+strings.Join(delimiter, " | ")+" |")
+	for _, row := range rows {
+		lines = append(lines, formatMarkdownRow(row, widths))
+	}
+	summary := format.summarize(competitors, selected)
+	footer := fmt.Sprintf("Starters: %d, finishers: %d", summary.Started, summary.Finished)
+	if summary.Competitors < summary.Field {
+		footer += fmt.Sprintf(" (%d of %d competitors shown)", summary.Competitors, summary.Field)
+	}
+	return append(lines, "", footer)
+}
+
+// formatMarkdownRow pads the cells of a row to the column widths, right-aligning the columns marked so
+func formatMarkdownRow(cells []string, widths []int) string {
+	padded := make([]string, len(cells))
+	for i, cell := range cells {
+		padding := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+		if markdownColumns[i].right {
+			padded[i] = padding + cell
+		} else {
+			padded[i] = cell + padding
+		}
+	}
+	return "| " + strings.Join(padded, " | ") + " |"
+}
+
+// escapeMarkdown escapes the characters that would end a table cell or start Markdown markup
+func escapeMarkdown(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`").Replace(text)
+}
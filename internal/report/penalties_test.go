@@ -0,0 +1,70 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPenaltyCellCountsTheLoops(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  []string
+	}{
+		{"report", GenerateReport(raceCompetitors(t, detailedRace)), []string{"{1, 00:00:30.000, 5.000}", "{,}"}},
+		{"legacy", GenerateLegacyReport(raceCompetitors(t, detailedRace)), []string{"{00:00:30.000, 5.000}", "{,}"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.lines) != len(tt.want) {
+				t.Fatalf("got %d lines, want %d: %q", len(tt.lines), len(tt.want), tt.lines)
+			}
+			for i, line := range tt.lines {
+				if !strings.Contains(line, "] "+tt.want[i]+" ") {
+					t.Errorf("line %q has no penalty cell %s", line, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPenaltyLoopsOfTheExampleRace(t *testing.T) {
+	want := map[string]string{"1": "{3, ", "2": "{2, ", "3": "{,}", "4": "{2, ", "5": "{3, "}
+	for _, line := range GenerateReport(exampleCompetitors(t)) {
+		fields := strings.Fields(line)
+		id := fields[2]
+		if strings.HasPrefix(fields[2], "+") {
+			id = fields[3]
+		}
+		if !strings.Contains(line, "] "+want[id]) {
+			t.Errorf("competitor %s: line %q has no penalty cell starting with %s", id, line, want[id])
+		}
+	}
+}
+
+func TestStructuredFormatsReportThePenaltyLoops(t *testing.T) {
+	competitors := raceCompetitors(t, detailedRace)
+	data, err := GenerateJSON(competitors, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var document struct {
+		Competitors []struct {
+			ID          int `json:"id"`
+			PenaltyLaps int `json:"penaltyLaps"`
+		} `json:"competitors"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatal(err)
+	}
+	for i, result := range document.Competitors {
+		if result.ID != competitors[i].ID || result.PenaltyLaps != competitors[i].TotalPenaltyLaps {
+			t.Errorf("JSON result %d: competitor %d with %d penalty loops, want %d with %d",
+				i, result.ID, result.PenaltyLaps, competitors[i].ID, competitors[i].TotalPenaltyLaps)
+		}
+	}
+	if document.Competitors[0].PenaltyLaps != 1 {
+		t.Errorf("winner skied %d penalty loops, want 1", document.Competitors[0].PenaltyLaps)
+	}
+}
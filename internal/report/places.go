@@ -0,0 +1,43 @@
+package report
+
+import (
+	"strconv"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// AssignPlaces returns the places of the finished competitors by competitor ID, for competitors sorted by result.
+// Competitors with equal total times share a place and the following place is skipped (1, 2, 2, 4); competitors
+// without a total time get no place
+func AssignPlaces(competitors []*domain.Competitor) map[int]int {
+	return assignPlaces(competitors, DefaultFormat.totals(competitors))
+}
+
+// assignPlaces numbers the competitors with a total time in totals like AssignPlaces, comparing the given totals
+func assignPlaces(competitors []*domain.Competitor, totals map[int]time.Duration) map[int]int {
+	places := make(map[int]int)
+	var previousTime time.Duration
+	previousPlace := 0
+	for _, competitor := range competitors {
+		totalTime, ok := totals[competitor.ID]
+		if !ok {
+			continue
+		}
+		place := len(places) + 1
+		if previousPlace > 0 && totalTime == previousTime {
+			place = previousPlace
+		}
+		places[competitor.ID] = place
+		previousTime, previousPlace = totalTime, place
+	}
+	return places
+}
+
+// formatPlace formats a place as "1.", or "-" for competitors without a place
+func formatPlace(place int) string {
+	if place == 0 {
+		return "-"
+	}
+	return strconv.Itoa(place) + "."
+}
@@ -0,0 +1,90 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// medals are the names and symbols of the podium places 1 to 3
+var medals = []struct {
+	name   string
+	symbol string
+}{
+	{name: "Gold", symbol: "🥇"},
+	{name: "Silver", symbol: "🥈"},
+	{name: "Bronze", symbol: "🥉"},
+}
+
+// PodiumPlace is a podium place with the competitors sharing it in classification order, their total time and the
+// gap to the winner
+type PodiumPlace struct {
+	Place       int
+	Competitors []*domain.Competitor
+	TotalTime   time.Duration
+	Behind      time.Duration
+}
+
+// Podium returns the places 1 to 3 of competitors sorted by result, as numbered by ComputeGaps. Tied competitors
+// share a place, so there are fewer than three places after a tie (1, 1, 3 or 1, 2, 2); nil if nobody finished
+func Podium(competitors []*domain.Competitor) []PodiumPlace {
+	gaps := ComputeGaps(competitors)
+	var podium []PodiumPlace
+	for _, competitor := range competitors {
+		gap, finished := gaps[competitor.ID]
+		if !finished || gap.Place > len(medals) {
+			continue
+		}
+		if len(podium) > 0 && podium[len(podium)-1].Place == gap.Place {
+			podium[len(podium)-1].Competitors = append(podium[len(podium)-1].Competitors, competitor)
+			continue
+		}
+		totalTime, _ := competitor.CalculateTotalTime()
+		podium = append(podium, PodiumPlace{Place: gap.Place, Competitors: []*domain.Competitor{competitor}, TotalTime: totalTime, Behind: gap.ToLeader})
+	}
+	return podium
+}
+
+// Medal returns the name of the medal of the place, e.g. "Gold"
+func (place PodiumPlace) Medal() string {
+	return medals[place.Place-1].name
+}
+
+// Symbol returns the medal emoji of the place
+func (place PodiumPlace) Symbol() string {
+	return medals[place.Place-1].symbol
+}
+
+// Labels returns the labels of the competitors sharing the place, separated by commas
+func (place PodiumPlace) Labels() string {
+	labels := make([]string, 0, len(place.Competitors))
+	for _, competitor := range place.Competitors {
+		labels = append(labels, competitorLabel(competitor))
+	}
+	return strings.Join(labels, ", ")
+}
+
+// Result formats the total time of the place, followed by the gap to the winner for the places after the first
+func (place PodiumPlace) Result() string {
+	result := domain.FormatDuration(place.TotalTime)
+	if place.Place > 1 {
+		result += " " + FormatGap(place.Behind)
+	}
+	return result
+}
+
+// GeneratePodium creates the podium section at the top of the text report, one line per place like
+// "Gold: 2 00:25:18.356", or no lines if nobody finished
+func GeneratePodium(competitors []*domain.Competitor) []string {
+	podium := Podium(competitors)
+	if len(podium) == 0 {
+		return []string{}
+	}
+	lines := []string{"Podium:"}
+	for _, place := range podium {
+		lines = append(lines, fmt.Sprintf("%s: %s %s", place.Medal(), place.Labels(), place.Result()))
+	}
+	return append(lines, "")
+}
@@ -0,0 +1,71 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// RangeStanding is the position of a competitor on arrival at a firing range
+type RangeStanding struct {
+	Place      int
+	Competitor *domain.Competitor
+	// Elapsed is the race time at the range entry and Behind the deficit to the first competitor there
+	Elapsed time.Duration
+	Behind  time.Duration
+}
+
+// RangeStandings ranks the competitors by their race time on entering a firing range. Competitors who never
+// reached the range or whose entry time is unknown are left out; equal times are ordered by competitor ID
+func RangeStandings(competitors []*domain.Competitor, rangeNum int) []RangeStanding {
+	standings := make([]RangeStanding, 0, len(competitors))
+	for _, competitor := range competitors {
+		for _, detail := range competitor.RangeDetails {
+			if detail.Range == rangeNum && !detail.EnterTime.IsZero() {
+				standings = append(standings, RangeStanding{Competitor: competitor, Elapsed: competitor.ElapsedTime(detail.EnterTime)})
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Elapsed != standings[j].Elapsed {
+			return standings[i].Elapsed < standings[j].Elapsed
+		}
+		return standings[i].Competitor.ID < standings[j].Competitor.ID
+	})
+	for i := range standings {
+		standings[i].Place = i + 1
+		standings[i].Behind = standings[i].Elapsed - standings[0].Elapsed
+	}
+	return standings
+}
+
+// GenerateRangeStandings creates a section with the arrival ranking and deficit to the leader at every firing range
+func GenerateRangeStandings(competitors []*domain.Competitor) []string {
+	lastRange := 0
+	for _, competitor := range competitors {
+		for _, detail := range competitor.RangeDetails {
+			lastRange = max(lastRange, detail.Range)
+		}
+	}
+
+	standingLines := make([]string, 0)
+	for rangeNum := 1; rangeNum <= lastRange; rangeNum++ {
+		for _, standing := range RangeStandings(competitors, rangeNum) {
+			line := fmt.Sprintf("range %d: %d. competitor(%d) %s", rangeNum, standing.Place,
+				standing.Competitor.ID, domain.FormatDuration(standing.Elapsed))
+			if standing.Place > 1 {
+				line += " " + FormatGap(standing.Behind)
+			}
+			standingLines = append(standingLines, line)
+		}
+	}
+
+	if len(standingLines) == 0 {
+		return standingLines
+	}
+	return append([]string{"", "Range arrivals:"}, standingLines...)
+}
@@ -0,0 +1,79 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// Result is the total time of a finished competitor read back from a final report
+type Result struct {
+	CompetitorID int
+	TotalTime    time.Duration
+}
+
+// ReadResults reads the finished competitors from a final report in report order. A leading place ("1.") and the
+// gap to the winner after the time are skipped; lines of competitors without a time, headers and the sections following the classification are skipped
+func ReadResults(reader io.Reader) ([]Result, error) {
+	results := make([]Result, 0)
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 && isPlace(fields[0]) {
+			fields = fields[1:]
+		}
+		if len(fields) > 1 && strings.HasPrefix(fields[1], "+") {
+			fields = append(fields[:1], fields[2:]...)
+		}
+		if len(fields) < 2 {
+			continue
+		}
+		totalTime, err := domain.ParseDurationFromString(fields[0])
+		if err != nil {
+			continue
+		}
+		competitorID, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid competitor ID '%s' in report line %d: %w", fields[1], lineNumber, err)
+		}
+		results = append(results, Result{CompetitorID: competitorID, TotalTime: totalTime})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading report: %w", err)
+	}
+	return results, nil
+}
+
+// StartGaps returns the gap of every competitor to the fastest result, as used for the start of a pursuit
+func StartGaps(results []Result) map[int]time.Duration {
+	gaps := make(map[int]time.Duration, len(results))
+	if len(results) == 0 {
+		return gaps
+	}
+
+	winnerTime := results[0].TotalTime
+	for _, result := range results[1:] {
+		winnerTime = min(winnerTime, result.TotalTime)
+	}
+	for _, result := range results {
+		gaps[result.CompetitorID] = result.TotalTime - winnerTime
+	}
+	return gaps
+}
+
+// isPlace reports whether a report field is a place like "1."
+func isPlace(field string) bool {
+	number, found := strings.CutSuffix(field, ".")
+	if !found {
+		return false
+	}
+	_, err := strconv.Atoi(number)
+	return err == nil
+}
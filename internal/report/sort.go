@@ -0,0 +1,174 @@
+package report
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// SortKey names an order of the report lines
+type SortKey string
+
+const (
+	SortByClassification SortKey = "classification"
+	SortByID             SortKey = "id"
+	SortByName           SortKey = "name"
+	SortByAccuracy       SortKey = "accuracy"
+	SortByMisses         SortKey = "misses"
+)
+
+// Compare orders two competitors like cmp.Compare: negative if c1 comes first, zero if the key cannot tell them apart
+type Compare func(c1, c2 *domain.Competitor) int
+
+// Options control the presentation of the classification. SortBy lists the sort keys in order of precedence; the
+// classification order applies when it is empty. Statuses limits the report to competitors with one of the
+// statuses, all competitors are included when it is empty. Template formats the lines of the text report,
+// DefaultTemplate is used when it is nil. Format controls the times and speeds of the text and Markdown reports,
+// DefaultFormat is used when it is nil. Podium adds the podium of the whole field to the Markdown and HTML reports
+type Options struct {
+	SortBy   []SortKey
+	Statuses []domain.CompetitorStatus
+	Template *ReportTemplate
+	Format   *Format
+	Podium   bool
+}
+
+// reportStatuses are the statuses accepted by ParseStatuses
+var reportStatuses = []domain.CompetitorStatus{
+	domain.StatusRegistered, domain.StatusReadyToStart, domain.StatusStarted, domain.StatusFiring, domain.StatusPenalized,
+	domain.StatusFinished, domain.StatusLapped, domain.StatusNotFinished, domain.StatusNotStarted, domain.StatusDisqualified,
+}
+
+// ParseStatuses parses a comma-separated list of competitor statuses like "NotFinished,Disqualified"
+func ParseStatuses(list string) ([]domain.CompetitorStatus, error) {
+	statuses := make([]domain.CompetitorStatus, 0)
+	for _, field := range strings.Split(list, ",") {
+		status := domain.CompetitorStatus(strings.TrimSpace(field))
+		if !slices.Contains(reportStatuses, status) {
+			return nil, fmt.Errorf("unknown competitor status '%s'", status)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// ParseSortKeys parses a comma-separated list of sort keys like "accuracy,name"
+func ParseSortKeys(list string) ([]SortKey, error) {
+	keys := make([]SortKey, 0)
+	for _, field := range strings.Split(list, ",") {
+		key := SortKey(strings.TrimSpace(field))
+		switch key {
+		case SortByClassification, SortByID, SortByName, SortByAccuracy, SortByMisses:
+			keys = append(keys, key)
+		default:
+			return nil, fmt.Errorf("unknown sort key '%s' (expected classification, id, name, accuracy or misses)", key)
+		}
+	}
+	return keys, nil
+}
+
+// Select returns the competitors, given in classification order, with one of the selected statuses sorted by the
+// sort keys. Competitors lacking the value of a key (e.g. no shots for the accuracy) follow the others for that key;
+// remaining ties keep the classification order
+func (options Options) Select(competitors []*domain.Competitor) []*domain.Competitor {
+	if len(options.Statuses) > 0 {
+		competitors = slices.DeleteFunc(slices.Clone(competitors), func(competitor *domain.Competitor) bool {
+			return !slices.Contains(options.Statuses, competitor.Status)
+		})
+	}
+	if len(options.SortBy) == 0 {
+		return competitors
+	}
+
+	classification := make(map[int]int, len(competitors))
+	for i, competitor := range competitors {
+		classification[competitor.ID] = i
+	}
+
+	compares := make([]Compare, 0, len(options.SortBy)+1)
+	for _, key := range options.SortBy {
+		compares = append(compares, compareBy(key, classification))
+	}
+	compares = append(compares, compareBy(SortByClassification, classification))
+
+	ordered := slices.Clone(competitors)
+	slices.SortStableFunc(ordered, ThenBy(compares...))
+	return ordered
+}
+
+// ThenBy combines comparisons: the first one that tells two competitors apart decides
+func ThenBy(compares ...Compare) Compare {
+	return func(c1, c2 *domain.Competitor) int {
+		for _, compare := range compares {
+			if result := compare(c1, c2); result != 0 {
+				return result
+			}
+		}
+		return 0
+	}
+}
+
+// compareBy returns the comparison of a sort key; classification holds the position of every competitor ID in the
+// classification
+func compareBy(key SortKey, classification map[int]int) Compare {
+	switch key {
+	case SortByID:
+		return func(c1, c2 *domain.Competitor) int { return c1.ID - c2.ID }
+	case SortByName:
+		return compareKnown(func(c *domain.Competitor) (string, bool) { return c.Name, c.Name != "" }, false)
+	case SortByAccuracy:
+		return compareKnown(func(c *domain.Competitor) (float64, bool) { return c.Accuracy() }, true)
+	case SortByMisses:
+		return compareKnown(func(c *domain.Competitor) (int, bool) {
+			return c.TotalShots - c.TotalHits, c.TotalShots > 0
+		}, false)
+	default:
+		return func(c1, c2 *domain.Competitor) int { return classification[c1.ID] - classification[c2.ID] }
+	}
+}
+
+// compareKnown compares the values of competitors who have one, ascending or descending, and puts the competitors
+// without a value last
+func compareKnown[T int | float64 | string](value func(*domain.Competitor) (T, bool), descending bool) Compare {
+	return func(c1, c2 *domain.Competitor) int {
+		v1, ok1 := value(c1)
+		v2, ok2 := value(c2)
+		switch {
+		case !ok1 || !ok2:
+			if ok1 == ok2 {
+				return 0
+			}
+			if ok1 {
+				return -1
+			}
+			return 1
+		case v1 == v2:
+			return 0
+		case (v1 < v2) != descending:
+			return -1
+		default:
+			return 1
+		}
+	}
+}
+
+// ReorderLines reorders the result lines of a classification, one per competitor in classification order after
+// any header lines, into the order of ordered, leaving out the lines of competitors not in ordered
+func ReorderLines(lines []string, competitors, ordered []*domain.Competitor) []string {
+	headerLines := len(lines) - len(competitors)
+	if headerLines < 0 {
+		return lines
+	}
+	byID := make(map[int]string, len(competitors))
+	for i, competitor := range competitors {
+		byID[competitor.ID] = lines[headerLines+i]
+	}
+
+	reordered := slices.Clone(lines[:headerLines])
+	for _, competitor := range ordered {
+		reordered = append(reordered, byID[competitor.ID])
+	}
+	return reordered
+}
@@ -0,0 +1,93 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// lateRace is the detailed race with competitor 3, who is drawn to start at 10:03:00 but never starts
+var lateRace = strings.Replace(detailedRace, "[09:00:03.000] 2 2 10:01:30.000",
+	"[09:00:03.000] 2 2 10:01:30.000\n[09:00:04.000] 1 3\n[09:00:05.000] 2 3 10:03:00.000", 1)
+
+func TestStartTimesGolden(t *testing.T) {
+	for golden, competitors := range map[string][]*domain.Competitor{
+		"start-times.txt":      exampleCompetitors(t),
+		"start-times-late.txt": raceCompetitors(t, lateRace),
+	} {
+		var builder strings.Builder
+		if err := WriteReport(&builder, competitors, Options{Template: StartTimesTemplate}); err != nil {
+			t.Fatal(err)
+		}
+		checkGolden(t, golden, []byte(builder.String()))
+	}
+}
+
+func TestStartDiffColumnsAreTheTotalTimeStartDiff(t *testing.T) {
+	for _, competitors := range [][]*domain.Competitor{exampleCompetitors(t), raceCompetitors(t, lateRace)} {
+		data, err := GenerateJSON(competitors, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var document struct {
+			Competitors []struct {
+				ID             int    `json:"id"`
+				ScheduledStart string `json:"scheduledStartTime"`
+				ActualStart    string `json:"actualStartTime"`
+				StartDiff      string `json:"startDiff"`
+				StartDiffMs    *int64 `json:"startDiffMs"`
+				TotalTimeMs    int64  `json:"totalTimeMs"`
+			} `json:"competitors"`
+		}
+		if err := json.Unmarshal(data, &document); err != nil {
+			t.Fatal(err)
+		}
+		data, err = GenerateCSV(competitors, 2, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		columns := make(map[string]int, len(records[0]))
+		for i, name := range records[0] {
+			columns[name] = i
+		}
+
+		for i, competitor := range competitors {
+			result, row := document.Competitors[i], records[i+1]
+			startDiff, started := competitor.StartDiff()
+			if !started {
+				if result.ActualStart != "" || result.StartDiff != "" || result.StartDiffMs != nil {
+					t.Errorf("competitor %d never started but has JSON start %q and start diff %q", competitor.ID, result.ActualStart, result.StartDiff)
+				}
+				if row[columns["actual_start"]] != "" || row[columns["start_diff"]] != "" {
+					t.Errorf("competitor %d never started but has CSV start %q and start diff %q",
+						competitor.ID, row[columns["actual_start"]], row[columns["start_diff"]])
+				}
+				continue
+			}
+			if result.StartDiffMs == nil || *result.StartDiffMs != startDiff.Milliseconds() || result.StartDiff != formatStartDiff(startDiff) {
+				t.Errorf("competitor %d: JSON start diff %q, want %v", competitor.ID, result.StartDiff, startDiff)
+			}
+			if row[columns["start_diff"]] != result.StartDiff || row[columns["actual_start"]] != result.ActualStart ||
+				row[columns["scheduled_start"]] != result.ScheduledStart {
+				t.Errorf("competitor %d: CSV start columns %q, %q, %q differ from JSON %q, %q, %q", competitor.ID,
+					row[columns["scheduled_start"]], row[columns["actual_start"]], row[columns["start_diff"]],
+					result.ScheduledStart, result.ActualStart, result.StartDiff)
+			}
+			if totalTime, ok := competitor.CalculateTotalTime(); ok {
+				raceTime := competitor.FinishTime.Sub(competitor.ActualStartTime) + competitor.TimePenalty
+				if result.TotalTimeMs != totalTime.Milliseconds() || result.TotalTimeMs-*result.StartDiffMs != raceTime.Milliseconds() {
+					t.Errorf("competitor %d: total time %d ms minus start diff %d ms is not the race time %v",
+						competitor.ID, result.TotalTimeMs, *result.StartDiffMs, raceTime)
+				}
+			}
+		}
+	}
+}
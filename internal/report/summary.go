@@ -0,0 +1,119 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// Summary counts the competitors per final status; the winner's time is omitted if nobody finished. Field is the
+// number of competitors in the race, which is more than Competitors in a report filtered by status. Hits and Shots
+// add up the shooting of all counted competitors, Accuracy is the fraction of hits and omitted without shots
+type Summary struct {
+	Field        int         `json:"field"`
+	Competitors  int         `json:"competitors"`
+	Started      int         `json:"started"`
+	Finished     int         `json:"finished"`
+	Lapped       int         `json:"lapped"`
+	NotFinished  int         `json:"notFinished"`
+	NotStarted   int         `json:"notStarted"`
+	Disqualified int         `json:"disqualified"`
+	WinnerTime   string      `json:"winnerTime,omitempty"`
+	WinnerTimeMs int64       `json:"winnerTimeMs,omitempty"`
+	FastestLap   *SummaryLap `json:"fastestLap,omitempty"`
+	Hits         int         `json:"hits"`
+	Shots        int         `json:"shots"`
+	Accuracy     *float64    `json:"accuracy,omitempty"`
+}
+
+// SummaryLap is the fastest lap of the day in the Summary
+type SummaryLap struct {
+	CompetitorID int     `json:"competitorId"`
+	Lap          int     `json:"lap"`
+	Time         string  `json:"time"`
+	TimeMs       int64   `json:"timeMs"`
+	Speed        float64 `json:"speed"`
+}
+
+// Summarize counts the competitors selected by the options per final status and takes the winner's time from the
+// fastest finisher among them and the fastest lap as found by FindFastestLap; all competitors make up the field. The
+// times are written in the format of the options, as in the classification
+func Summarize(competitors []*domain.Competitor, options Options) Summary {
+	return options.format().resolve(competitors).summarize(competitors, options.Select(competitors))
+}
+
+// summarize is Summarize for the selected competitors, with the winner's time taken from the rounded total times
+// like the places and gaps and the times formatted with the precision of the format
+func (format Format) summarize(competitors, selected []*domain.Competitor) Summary {
+	summary := Summary{Field: len(competitors)}
+	totals := format.totals(selected)
+	winnerTime, winnerFound := time.Duration(0), false
+	for _, competitor := range selected {
+		summary.Competitors++
+		if !competitor.ActualStartTime.IsZero() {
+			summary.Started++
+		}
+		switch competitor.Status {
+		case domain.StatusFinished:
+			summary.Finished++
+		case domain.StatusLapped:
+			summary.Lapped++
+		case domain.StatusNotFinished:
+			summary.NotFinished++
+		case domain.StatusNotStarted:
+			summary.NotStarted++
+		case domain.StatusDisqualified:
+			summary.Disqualified++
+		}
+		if totalTime, ok := totals[competitor.ID]; ok && (!winnerFound || totalTime < winnerTime) {
+			winnerTime, winnerFound = totalTime, true
+		}
+		summary.Hits += competitor.TotalHits
+		summary.Shots += competitor.TotalShots
+	}
+	if winnerFound {
+		summary.WinnerTime, summary.WinnerTimeMs = format.duration(winnerTime), winnerTime.Milliseconds()
+	}
+	if fastest, ok := FindFastestLap(selected); ok {
+		lapTime := format.round(fastest.Detail.Duration)
+		summary.FastestLap = &SummaryLap{
+			CompetitorID: fastest.CompetitorID,
+			Lap:          fastest.Lap,
+			Time:         format.duration(lapTime),
+			TimeMs:       lapTime.Milliseconds(),
+			Speed:        fastest.Detail.Speed,
+		}
+	}
+	if summary.Shots > 0 {
+		accuracy := float64(summary.Hits) / float64(summary.Shots)
+		summary.Accuracy = &accuracy
+	}
+	return summary
+}
+
+// GenerateSummary creates the summary section at the bottom of the text report. Every line is always present, with
+// "-" for values that are not known, so the section has the same shape in every report
+func GenerateSummary(summary Summary) []string {
+	winnerTime, fastestLap := "-", "-"
+	if summary.WinnerTime != "" {
+		winnerTime = summary.WinnerTime
+	}
+	if lap := summary.FastestLap; lap != nil {
+		fastestLap = fmt.Sprintf("%s by competitor(%d) on lap %d", lap.Time, lap.CompetitorID, lap.Lap)
+	}
+	return []string{
+		"",
+		"Summary:",
+		fmt.Sprintf("Entered: %d", summary.Competitors),
+		fmt.Sprintf("Started: %d", summary.Started),
+		fmt.Sprintf("Finished: %d", summary.Finished),
+		fmt.Sprintf("Lapped: %d", summary.Lapped),
+		fmt.Sprintf("NotFinished: %d", summary.NotFinished),
+		fmt.Sprintf("NotStarted: %d", summary.NotStarted),
+		fmt.Sprintf("Disqualified: %d", summary.Disqualified),
+		fmt.Sprintf("Winner's time: %s", winnerTime),
+		fmt.Sprintf("Fastest lap: %s", fastestLap),
+		fmt.Sprintf("Shooting: %d/%d (%s)", summary.Hits, summary.Shots, domain.FormatAccuracy(summary.Hits, summary.Shots)),
+	}
+}
@@ -0,0 +1,112 @@
+package report
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"biathlonPrototype/internal/domain"
+)
+
+func TestSummaryGolden(t *testing.T) {
+	tests := []struct {
+		golden string
+		race   string
+	}{
+		{"summary.txt", ""},
+		{"summary-detailed.txt", detailedRace},
+		{"summary-empty.txt", "[09:00:00.000] 1 1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			competitors := exampleCompetitors(t)
+			if tt.race != "" {
+				competitors = raceCompetitors(t, tt.race)
+			}
+			lines := GenerateSummary(Summarize(competitors, Options{}))
+			checkGolden(t, tt.golden, []byte(strings.Join(lines, "\n")+"\n"))
+		})
+	}
+}
+
+func TestSummarizeCountsTheField(t *testing.T) {
+	competitors := raceCompetitors(t, detailedRace)
+	summary := Summarize(competitors, Options{})
+	accuracy := 0.9
+	want := Summary{
+		Field:        2,
+		Competitors:  2,
+		Started:      2,
+		Finished:     1,
+		NotFinished:  1,
+		WinnerTime:   "00:20:00.000",
+		WinnerTimeMs: 1200000,
+		FastestLap:   &SummaryLap{CompetitorID: 1, Lap: 1, Time: "00:10:00.000", TimeMs: 600000, Speed: competitors[0].LapDetails[0].Speed},
+		Hits:         9,
+		Shots:        10,
+		Accuracy:     &accuracy,
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("summary %+v, want %+v", summary, want)
+	}
+
+	filtered := Summarize(competitors, Options{Statuses: []domain.CompetitorStatus{domain.StatusNotFinished}})
+	if filtered.Field != 2 || filtered.Competitors != 1 || filtered.WinnerTime != "" || filtered.FastestLap != nil {
+		t.Errorf("summary of the non-finishers %+v", filtered)
+	}
+}
+
+func TestJSONReportEmbedsTheSummary(t *testing.T) {
+	competitors := exampleCompetitors(t)
+	data, err := GenerateJSON(competitors, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var document JSONReport
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatal(err)
+	}
+	if want := Summarize(competitors, Options{}); !reflect.DeepEqual(document.Summary, want) {
+		t.Errorf("JSON summary %+v, want %+v", document.Summary, want)
+	}
+}
+
+func TestRoundedTotalsDecidePlacesGapsAndSummary(t *testing.T) {
+	// Competitor 2 is 30 ms behind competitor 1, which rounds away at a tenth of a second
+	competitors := raceCompetitors(t, strings.Replace(tiedRace, "[10:21:30.000] 10 2", "[10:21:30.030] 10 2", 1))
+	options := Options{Format: &Format{SpeedDecimals: 3, TimeDecimals: 1, Round: true, DropHours: true}}
+
+	format := options.format().resolve(competitors)
+	gaps := format.gaps(competitors)
+	if gaps[1].Place != 1 || gaps[2].Place != 1 || gaps[3].Place != 3 {
+		t.Errorf("places %d, %d, %d, want 1, 1, 3", gaps[1].Place, gaps[2].Place, gaps[3].Place)
+	}
+	if gaps[2].ToLeader != 0 {
+		t.Errorf("tied competitor is %v behind, want 0", gaps[2].ToLeader)
+	}
+	if places := AssignPlaces(competitors); places[2] != 2 {
+		t.Errorf("at full precision competitor 2 has place %d, want 2", places[2])
+	}
+
+	report := GenerateReport(competitors)
+	if !strings.HasPrefix(report[1], "2. 00:20:00.030 +00:00:00.030 2 ") {
+		t.Errorf("full precision line %q", report[1])
+	}
+	var builder strings.Builder
+	if err := WriteReport(&builder, competitors, options); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(builder.String(), "\n")
+	if !strings.HasPrefix(lines[1], "1. 20:00.0 +00:00.0 2 ") {
+		t.Errorf("rounded line %q", lines[1])
+	}
+
+	summary := Summarize(competitors, options)
+	if summary.WinnerTime != "20:00.0" || summary.FastestLap == nil || summary.FastestLap.Time != "10:00.0" {
+		t.Errorf("summary winner %q and fastest lap %+v, want 20:00.0 and 10:00.0", summary.WinnerTime, summary.FastestLap)
+	}
+	if lines := GenerateSummary(summary); lines[len(lines)-3] != "Winner's time: 20:00.0" {
+		t.Errorf("summary lines %q", lines)
+	}
+}
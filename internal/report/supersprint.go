@@ -0,0 +1,103 @@
+package report
+
+import (
+	"fmt"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// SuperSprintEntry combines the qualification and final results of one super-sprint competitor
+type SuperSprintEntry struct {
+	// Place is the overall placing; zero for competitors who did not finish the qualification
+	Place              int
+	CompetitorID       int
+	Qualification      *domain.Competitor
+	QualificationPlace int
+	Advanced           bool
+	// Final is nil for competitors without events in the final
+	Final *domain.Competitor
+}
+
+// StartedFinal reports whether an advanced competitor started the final
+func (entry SuperSprintEntry) StartedFinal() bool {
+	return entry.Final != nil && entry.Final.Status != domain.StatusNotStarted && !entry.Final.ActualStartTime.IsZero()
+}
+
+// RankSuperSprint combines the sorted classifications of the qualification and the final. The best qualifiers
+// finishers advance; the overall placing lists the finalists in final order, then the qualified competitors
+// who did not start the final, then the other qualification finishers in qualification order
+func RankSuperSprint(qualification, final []*domain.Competitor, qualifiers int) []SuperSprintEntry {
+	finalByID := make(map[int]*domain.Competitor, len(final))
+	for _, competitor := range final {
+		finalByID[competitor.ID] = competitor
+	}
+
+	entries := make([]SuperSprintEntry, 0, len(qualification))
+	byID := make(map[int]int, len(qualification))
+	qualificationPlace := 0
+	for _, competitor := range qualification {
+		entry := SuperSprintEntry{CompetitorID: competitor.ID, Qualification: competitor}
+		if competitor.Status == domain.StatusFinished {
+			qualificationPlace++
+			entry.QualificationPlace = qualificationPlace
+			entry.Advanced = qualificationPlace <= qualifiers
+			if entry.Advanced {
+				entry.Final = finalByID[competitor.ID]
+			}
+		}
+		byID[competitor.ID] = len(entries)
+		entries = append(entries, entry)
+	}
+
+	ranked := make([]SuperSprintEntry, 0, len(entries))
+	for _, competitor := range final {
+		if index, found := byID[competitor.ID]; found && entries[index].StartedFinal() {
+			ranked = append(ranked, entries[index])
+		}
+	}
+	for _, entry := range entries {
+		if entry.Advanced && !entry.StartedFinal() {
+			ranked = append(ranked, entry)
+		}
+	}
+	for _, entry := range entries {
+		if !entry.Advanced {
+			ranked = append(ranked, entry)
+		}
+	}
+	for i := range ranked {
+		if ranked[i].QualificationPlace > 0 {
+			ranked[i].Place = i + 1
+		}
+	}
+	return ranked
+}
+
+// GenerateSuperSprint creates a section with the overall placing, qualification and final result of every competitor
+func GenerateSuperSprint(entries []SuperSprintEntry) []string {
+	lines := []string{"", "Super sprint:"}
+	for _, entry := range entries {
+		place := "-"
+		if entry.Place > 0 {
+			place = fmt.Sprintf("%d", entry.Place)
+		}
+
+		qualification := entry.Qualification.FinalStatusString()
+		if entry.QualificationPlace > 0 {
+			qualification = fmt.Sprintf("Q%d %s", entry.QualificationPlace, qualification)
+		}
+
+		var finalResult string
+		switch {
+		case !entry.Advanced:
+			finalResult = "[NotQualified]"
+		case !entry.StartedFinal():
+			finalResult = "[QualifiedNotStarted]"
+		default:
+			finalResult = entry.Final.FinalStatusString()
+		}
+
+		lines = append(lines, fmt.Sprintf("%s %d %s %s", place, entry.CompetitorID, qualification, finalResult))
+	}
+	return lines
+}
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// TeamStanding is the result of one team in the team classification
+type TeamStanding struct {
+	// Place is the team placing; zero for teams with fewer finishers than the team size
+	Place int
+	Team  string
+	// Scorers are the best finishers of the team whose times are summed, fastest first
+	Scorers []*domain.Competitor
+	Total   time.Duration
+}
+
+// Complete reports whether the team has enough finishers to be ranked
+func (standing TeamStanding) Complete(teamSize int) bool {
+	return len(standing.Scorers) >= teamSize
+}
+
+// RankTeams sums the total times of the best teamSize finishers of every team. Complete teams are ranked by the
+// sum, ties broken by the best individual time; teams with fewer finishers follow, most finishers first.
+// Competitors without a team and competitors who did not finish are not counted
+func RankTeams(competitors []*domain.Competitor, teamSize int) []TeamStanding {
+	finishers := make(map[string][]*domain.Competitor)
+	times := make(map[int]time.Duration)
+	for _, competitor := range competitors {
+		if competitor.Team == "" {
+			continue
+		}
+		if _, found := finishers[competitor.Team]; !found {
+			finishers[competitor.Team] = nil
+		}
+		if totalTime, ok := competitor.CalculateTotalTime(); ok {
+			finishers[competitor.Team] = append(finishers[competitor.Team], competitor)
+			times[competitor.ID] = totalTime
+		}
+	}
+
+	standings := make([]TeamStanding, 0, len(finishers))
+	for team, members := range finishers {
+		sort.SliceStable(members, func(i, j int) bool {
+			if times[members[i].ID] != times[members[j].ID] {
+				return times[members[i].ID] < times[members[j].ID]
+			}
+			return members[i].ID < members[j].ID
+		})
+		standing := TeamStanding{Team: team, Scorers: members[:min(teamSize, len(members))]}
+		for _, scorer := range standing.Scorers {
+			standing.Total += times[scorer.ID]
+		}
+		standings = append(standings, standing)
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		s1, s2 := standings[i], standings[j]
+		if len(s1.Scorers) != len(s2.Scorers) && (!s1.Complete(teamSize) || !s2.Complete(teamSize)) {
+			return len(s1.Scorers) > len(s2.Scorers)
+		}
+		if s1.Total != s2.Total {
+			return s1.Total < s2.Total
+		}
+		if len(s1.Scorers) > 0 && times[s1.Scorers[0].ID] != times[s2.Scorers[0].ID] {
+			return times[s1.Scorers[0].ID] < times[s2.Scorers[0].ID]
+		}
+		return s1.Team < s2.Team
+	})
+	for i := range standings {
+		if standings[i].Complete(teamSize) {
+			standings[i].Place = i + 1
+		}
+	}
+	return standings
+}
+
+// GenerateTeamStandings creates a section with the team classification by the best teamSize finishers of every team
+func GenerateTeamStandings(competitors []*domain.Competitor, teamSize int) []string {
+	standings := RankTeams(competitors, teamSize)
+	if len(standings) == 0 {
+		return nil
+	}
+
+	lines := []string{"", "Team standings:"}
+	for _, standing := range standings {
+		result := domain.FormatDuration(standing.Total)
+		place := fmt.Sprintf("%d", standing.Place)
+		if !standing.Complete(teamSize) {
+			result = fmt.Sprintf("[Incomplete: %d of %d finished]", len(standing.Scorers), teamSize)
+			place = "-"
+		}
+
+		ids := make([]string, 0, len(standing.Scorers))
+		for _, scorer := range standing.Scorers {
+			ids = append(ids, fmt.Sprintf("%d", scorer.ID))
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %s [%s]", place, standing.Team, result, strings.Join(ids, ", ")))
+	}
+	return lines
+}
@@ -0,0 +1,219 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// TemplateData is the data of one competitor's line in a report template. Place is 0 and TotalTime, Behind and
+// ToPrevious and the average Speed over the course are empty for competitors who did not finish; Behind and
+// ToPrevious are also empty for the winner. The start times and the StartDiff added to the total time are empty for
+// competitors who never started.
+// The *Details and *Text fields hold the values as formatted in the default report
+type TemplateData struct {
+	Place          int
+	PlaceText      string
+	ID             int
+	Name           string
+	Nation         string
+	Competitor     string
+	Status         string
+	Result         string
+	TotalTime      string
+	Behind         string
+	ToPrevious     string
+	Speed          string
+	ScheduledStart string
+	ActualStart    string
+	StartDiff      string
+	Laps           []TemplateLap
+	LapDetails     string
+	Penalty        TemplatePenalty
+	PenaltyDetails string
+	Shooting       TemplateShooting
+	ShootingText   string
+}
+
+// TemplateLap is a main lap in TemplateData; Duration and Speed are empty for laps without a recorded end
+type TemplateLap struct {
+	Lap      int
+	Duration string
+	Speed    string
+}
+
+// TemplatePenalty holds the penalty loops of a competitor in TemplateData; TimeAndSpeed is the legacy report cell
+type TemplatePenalty struct {
+	Loops        int
+	Time         string
+	Speed        string
+	TimeAndSpeed string
+}
+
+// TemplateShooting holds the shooting totals of a competitor in TemplateData
+type TemplateShooting struct {
+	Hits        int
+	Shots       int
+	SpareRounds int
+	Accuracy    string
+}
+
+// ReportTemplate formats the lines of the classification. The template itself is executed once per competitor and
+// its output, without a trailing newline, makes up the line; optional templates named "header" and "footer" are
+// executed once before and after the competitors with the Summary of the report
+type ReportTemplate struct {
+	template *template.Template
+}
+
+// defaultLayout is the line of a competitor in the final report
+const defaultLayout = `{{.PlaceText}} {{.Result}}{{with .Behind}} {{.}}{{end}} {{.Competitor}} {{.LapDetails}} {{.PenaltyDetails}} {{.ShootingText}}{{with .Speed}} {{.}}{{end}}`
+
+// DefaultTemplate is the layout of the final report
+var DefaultTemplate = mustParseTemplate("default", defaultLayout)
+
+// StartTimesTemplate is the layout of the final report followed by the scheduled start ("-" if unknown), the actual
+// start and the start diff of competitors who started
+var StartTimesTemplate = mustParseTemplate("start-times",
+	defaultLayout+`{{with .ActualStart}} {{or $.ScheduledStart "-"}} {{.}} {{$.StartDiff}}{{end}}`)
+
+// LegacyTemplate is the original layout parsed by older scripts: no places, no gaps to the winner and no number of
+// penalty loops
+var LegacyTemplate = mustParseTemplate("legacy",
+	`{{.Result}} {{.Competitor}} {{.LapDetails}} {{.Penalty.TimeAndSpeed}} {{.ShootingText}}`)
+
+// ParseTemplate parses a report template and checks it against sample data, so that references to unknown fields
+// are reported with the available fields before any report is written
+func ParseTemplate(name, text string) (*ReportTemplate, error) {
+	parsed, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template %s: %w", name, err)
+	}
+	reportTemplate := &ReportTemplate{template: parsed}
+
+	sample := TemplateData{Laps: []TemplateLap{{Lap: 1}}}
+	if err = reportTemplate.executeLine(io.Discard, sample); err != nil {
+		return nil, fmt.Errorf("%w (available fields: %s)", err, strings.Join(templateFields(reflect.TypeOf(sample), ""), ", "))
+	}
+	for _, section := range []string{"header", "footer"} {
+		if err = reportTemplate.executeSection(io.Discard, section, Summary{}); err != nil {
+			return nil, fmt.Errorf("%w (available fields: %s)", err, strings.Join(templateFields(reflect.TypeOf(Summary{}), ""), ", "))
+		}
+	}
+	return reportTemplate, nil
+}
+
+// mustParseTemplate parses a built-in report template
+func mustParseTemplate(name, text string) *ReportTemplate {
+	reportTemplate, err := ParseTemplate(name, text)
+	if err != nil {
+		panic(err)
+	}
+	return reportTemplate
+}
+
+// executeLine writes the line of one competitor
+func (reportTemplate *ReportTemplate) executeLine(w io.Writer, data TemplateData) error {
+	var buffer bytes.Buffer
+	if err := reportTemplate.template.Execute(&buffer, data); err != nil {
+		return fmt.Errorf("error executing template %s: %w", reportTemplate.template.Name(), err)
+	}
+	_, err := io.WriteString(w, strings.TrimSuffix(buffer.String(), "\n")+"\n")
+	return err
+}
+
+// executeSection writes the header or footer, if the template defines it
+func (reportTemplate *ReportTemplate) executeSection(w io.Writer, name string, summary Summary) error {
+	section := reportTemplate.template.Lookup(name)
+	if section == nil {
+		return nil
+	}
+	var buffer bytes.Buffer
+	if err := section.Execute(&buffer, summary); err != nil {
+		return fmt.Errorf("error executing template %s: %w", name, err)
+	}
+	if buffer.Len() == 0 {
+		return nil
+	}
+	_, err := io.WriteString(w, strings.TrimSuffix(buffer.String(), "\n")+"\n")
+	return err
+}
+
+// templateFields lists the field paths of a template data type, e.g. "Penalty.Loops"
+func templateFields(dataType reflect.Type, prefix string) []string {
+	fields := make([]string, 0, dataType.NumField())
+	for i := 0; i < dataType.NumField(); i++ {
+		field := dataType.Field(i)
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Slice {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct && fieldType.PkgPath() == reflect.TypeOf(TemplateData{}).PkgPath() {
+			for _, nested := range templateFields(fieldType, "") {
+				fields = append(fields, prefix+field.Name+"."+nested)
+			}
+			continue
+		}
+		fields = append(fields, prefix+field.Name)
+	}
+	return fields
+}
+
+// templateData collects the values of a competitor for the report template
+func templateData(competitor *domain.Competitor, gap Gap, finished bool, behind string, format Format) TemplateData {
+	data := TemplateData{
+		Place:          gap.Place,
+		PlaceText:      formatPlace(gap.Place),
+		ID:             competitor.ID,
+		Name:           competitor.Name,
+		Nation:         competitor.Nation,
+		Competitor:     competitorLabel(competitor),
+		Status:         string(competitor.Status),
+		Result:         format.result(competitor),
+		Behind:         behind,
+		Laps:           make([]TemplateLap, 0, len(competitor.LapDetails)),
+		LapDetails:     formatLapDetails(competitor.LapDetails, competitor.Status, competitor.CurrentLap, 0, format),
+		PenaltyDetails: formatPenaltyDetails(competitor.PenaltyDetails, competitor.TotalPenaltyLaps, format),
+		Penalty: TemplatePenalty{
+			Loops:        competitor.TotalPenaltyLaps,
+			TimeAndSpeed: formatLegacyPenaltyDetails(competitor.PenaltyDetails, competitor.TotalPenaltyLaps > 0, format),
+		},
+		Shooting: TemplateShooting{
+			Hits:        competitor.TotalHits,
+			Shots:       competitor.TotalShots,
+			SpareRounds: competitor.TotalSpareRounds,
+			Accuracy:    domain.FormatAccuracy(competitor.TotalHits, competitor.TotalShots),
+		},
+		ShootingText: formatShooting(competitor.TotalHits, competitor.TotalShots, competitor.TotalSpareRounds),
+	}
+	if startDiff, ok := competitor.StartDiff(); ok {
+		data.ActualStart, data.StartDiff = domain.FormatTime(competitor.ActualStartTime), formatStartDiff(startDiff)
+		if !competitor.ScheduledStartTime.IsZero() {
+			data.ScheduledStart = domain.FormatTime(competitor.ScheduledStartTime)
+		}
+	}
+	if finished {
+		totalTime, _ := competitor.CalculateTotalTime()
+		data.TotalTime = format.duration(totalTime)
+		data.Speed = format.speed(competitor.OverallSpeed)
+		if behind != "" {
+			data.ToPrevious = "+" + format.duration(gap.ToPrevious)
+		}
+	}
+	for i, lap := range competitor.LapDetails {
+		entry := TemplateLap{Lap: i + 1}
+		if lap.Duration > 0 {
+			entry.Duration, entry.Speed = format.duration(lap.Duration), format.speed(lap.Speed)
+		}
+		data.Laps = append(data.Laps, entry)
+	}
+	if competitor.TotalPenaltyLaps > 0 || competitor.PenaltyDetails.TotalDuration > 0 {
+		data.Penalty.Time = format.duration(competitor.PenaltyDetails.TotalDuration)
+		data.Penalty.Speed = format.speed(competitor.PenaltyDetails.AverageSpeed)
+	}
+	return data
+}
@@ -0,0 +1,78 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplateGolden(t *testing.T) {
+	text, err := os.ReadFile(filepath.Join("testdata", "custom.tmpl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom, err := ParseTemplate("custom", string(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		golden   string
+		template *ReportTemplate
+		race     string
+	}{
+		{"default.txt", DefaultTemplate, ""},
+		{"legacy.txt", LegacyTemplate, ""},
+		{"custom.txt", custom, ""},
+		{"custom-detailed.txt", custom, detailedRace},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			competitors := exampleCompetitors(t)
+			if tt.race != "" {
+				competitors = raceCompetitors(t, tt.race)
+			}
+			var builder strings.Builder
+			if err := WriteReport(&builder, competitors, Options{Template: tt.template}); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, tt.golden, []byte(builder.String()))
+		})
+	}
+}
+
+func TestDefaultTemplateIsTheReport(t *testing.T) {
+	competitors := exampleCompetitors(t)
+	var builder strings.Builder
+	if err := WriteReport(&builder, competitors, Options{Template: DefaultTemplate}); err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.Join(GenerateReport(competitors), "\n") + "\n"; builder.String() != want {
+		t.Errorf("default template wrote:\n%s\nwant the report:\n%s", builder.String(), want)
+	}
+}
+
+func TestParseTemplateRejectsUnknownFields(t *testing.T) {
+	tests := []struct {
+		name, text, field, available string
+	}{
+		{"line", "{{.ID}} {{.Bib}}", "Bib", "Penalty.Loops"},
+		{"lap", "{{range .Laps}}{{.Split}}{{end}}", "Split", "Laps.Duration"},
+		{"summary", `{{define "header"}}{{.Winner}}{{end}}{{.ID}}`, "Winner", "WinnerTime"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTemplate(tt.name, tt.text)
+			if err == nil {
+				t.Fatal("unknown field was accepted")
+			}
+			if !strings.Contains(err.Error(), tt.field) || !strings.Contains(err.Error(), "available fields: ") ||
+				!strings.Contains(err.Error(), tt.available) {
+				t.Errorf("error %q does not name %s and the available field %s", err, tt.field, tt.available)
+			}
+		})
+	}
+	if _, err := ParseTemplate("broken", "{{.ID"); err == nil || !strings.Contains(err.Error(), "error parsing template broken") {
+		t.Errorf("syntax error reported as %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// XMLRace is the root element of the XML report: the race with its course as attributes and the results in result
+// order. The structure is
+//
+//	<Race name laps lapLength penaltyLength firingLines start>
+//	  <Results>
+//	    <Result rank bib status>
+//	      <Name/> <Nation/> <TotalTime/> <Behind/> <Reason/>
+//	      <Laps><Lap number time speed/>...</Laps>
+//	      <Shooting hits shots spareRounds accuracy/>
+//	      <Penalty loops time speed/>
+//	    </Result>
+//	  </Results>
+//	</Race>
+//
+// Missing values leave out their attribute or element: rank, total time and gap for competitors who did not
+// finish, the time and speed of laps without a recorded end
+type XMLRace struct {
+	XMLName       xml.Name   `xml:"Race"`
+	Name          string     `xml:"name,attr,omitempty"`
+	Laps          int        `xml:"laps,attr"`
+	LapLength     float64    `xml:"lapLength,attr"`
+	PenaltyLength float64    `xml:"penaltyLength,attr"`
+	FiringLines   int        `xml:"firingLines,attr"`
+	Start         string     `xml:"start,attr,omitempty"`
+	Results       XMLResults `xml:"Results"`
+}
+
+// XMLResults holds the results of the competitors
+type XMLResults struct {
+	Results []XMLResult `xml:"Result"`
+}
+
+// XMLResult is the result of one competitor; the rank is only set for finishers and Reason only for competitors
+// with a disqualification or non-start reason
+type XMLResult struct {
+	Rank      int         `xml:"rank,attr,omitempty"`
+	Bib       int         `xml:"bib,attr"`
+	Status    string      `xml:"status,attr"`
+	Name      string      `xml:"Name,omitempty"`
+	Nation    string      `xml:"Nation,omitempty"`
+	TotalTime string      `xml:"TotalTime,omitempty"`
+	Behind    string      `xml:"Behind,omitempty"`
+	Reason    string      `xml:"Reason,omitempty"`
+	Laps      []XMLLap    `xml:"Laps>Lap"`
+	Shooting  XMLShooting `xml:"Shooting"`
+	Penalty   XMLPenalty  `xml:"Penalty"`
+}
+
+// XMLLap is a main lap; time and speed are omitted for laps without a recorded end
+type XMLLap struct {
+	Number int    `xml:"number,attr"`
+	Time   string `xml:"time,attr,omitempty"`
+	Speed  string `xml:"speed,attr,omitempty"`
+}
+
+// XMLShooting holds the shooting totals; accuracy is the fraction of hits with three decimals, omitted without shots
+type XMLShooting struct {
+	Hits        int    `xml:"hits,attr"`
+	Shots       int    `xml:"shots,attr"`
+	SpareRounds int    `xml:"spareRounds,attr,omitempty"`
+	Accuracy    string `xml:"accuracy,attr,omitempty"`
+}
+
+// XMLPenalty holds the penalty loop totals; time and speed are omitted without penalty loops
+type XMLPenalty struct {
+	Loops int    `xml:"loops,attr"`
+	Time  string `xml:"time,attr,omitempty"`
+	Speed string `xml:"speed,attr,omitempty"`
+}
+
+// GenerateXML creates the final report as an indented XML document with an XML declaration for competitors sorted
+// by result. Ranks and gaps are those of all competitors, even if the options select only some of them
+func GenerateXML(competitors []*domain.Competitor, race RaceParameters, options Options) ([]byte, error) {
+	selected := options.Select(competitors)
+	document := XMLRace{
+		Name:          race.Title,
+		Laps:          race.Laps,
+		LapLength:     race.LapLen,
+		PenaltyLength: race.PenaltyLen,
+		FiringLines:   race.FiringLines,
+		Start:         race.Start,
+		Results:       XMLResults{Results: make([]XMLResult, 0, len(selected))},
+	}
+	gaps := ComputeGaps(competitors)
+	for _, competitor := range selected {
+		result := XMLResult{
+			Bib:      competitor.ID,
+			Status:   string(competitor.Status),
+			Name:     competitor.Name,
+			Nation:   competitor.Nation,
+			Laps:     make([]XMLLap, 0, len(competitor.LapDetails)),
+			Shooting: XMLShooting{Hits: competitor.TotalHits, Shots: competitor.TotalShots, SpareRounds: competitor.TotalSpareRounds},
+			Penalty:  XMLPenalty{Loops: competitor.TotalPenaltyLaps},
+		}
+		if gap, ok := gaps[competitor.ID]; ok {
+			totalTime, _ := competitor.CalculateTotalTime()
+			result.Rank, result.TotalTime, result.Behind = gap.Place, domain.FormatDuration(totalTime), gap.Behind()
+		}
+		if !competitor.DisqualificationReason.IsZero() {
+			result.Reason = competitor.DisqualificationReason.String()
+		}
+		for i, lap := range competitor.LapDetails {
+			entry := XMLLap{Number: i + 1}
+			if lap.Duration > 0 {
+				entry.Time, entry.Speed = domain.FormatDuration(lap.Duration), fmt.Sprintf("%.3f", lap.Speed)
+			}
+			result.Laps = append(result.Laps, entry)
+		}
+		if accuracy, ok := competitor.Accuracy(); ok {
+			result.Shooting.Accuracy = fmt.Sprintf("%.3f", accuracy)
+		}
+		if penaltyTime := competitor.TotalPenaltyTime; penaltyTime > 0 {
+			result.Penalty.Time = domain.FormatDuration(penaltyTime)
+			result.Penalty.Speed = fmt.Sprintf("%.3f", competitor.PenaltyDetails.AverageSpeed)
+		}
+		document.Results.Results = append(document.Results.Results, result)
+	}
+
+	data, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding the XML report: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,120 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"biathlonPrototype/internal/domain"
+)
+
+// exampleRace are the race parameters of the example configuration
+var exampleRace = RaceParameters{Title: "Sprint", Laps: 2, LapLen: 3500, PenaltyLen: 150, FiringLines: 2, Start: "10:00:00.000"}
+
+func TestXMLGolden(t *testing.T) {
+	data, err := GenerateXML(exampleCompetitors(t), exampleRace, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "example.xml", data)
+
+	data, err = GenerateXML(raceCompetitors(t, detailedRace), RaceParameters{}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "detailed.xml", data)
+}
+
+func TestXMLRoundTrip(t *testing.T) {
+	for _, race := range []string{"", detailedRace} {
+		competitors := exampleCompetitors(t)
+		if race != "" {
+			competitors = raceCompetitors(t, race)
+		}
+		data, err := GenerateXML(competitors, exampleRace, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var document XMLRace
+		if err := xml.Unmarshal(data, &document); err != nil {
+			t.Fatal(err)
+		}
+		if document.Name != exampleRace.Title || document.Laps != exampleRace.Laps || document.LapLength != exampleRace.LapLen ||
+			document.PenaltyLength != exampleRace.PenaltyLen || document.FiringLines != exampleRace.FiringLines ||
+			document.Start != exampleRace.Start {
+			t.Errorf("race attributes %+v, want %+v", document, exampleRace)
+		}
+		results := document.Results.Results
+		if len(results) != len(competitors) {
+			t.Fatalf("got %d results, want %d", len(results), len(competitors))
+		}
+		places := AssignPlaces(competitors)
+		for i, competitor := range competitors {
+			if err := compareXMLResult(results[i], competitor, places[competitor.ID]); err != nil {
+				t.Errorf("result %d: %v", i, err)
+			}
+		}
+	}
+}
+
+// compareXMLResult checks a decoded XML result against the competitor it was generated from
+func compareXMLResult(result XMLResult, competitor *domain.Competitor, place int) error {
+	if result.Bib != competitor.ID || result.Status != string(competitor.Status) || result.Rank != place {
+		return fmt.Errorf("bib %d, status %s and rank %d, want %d, %s and %d",
+			result.Bib, result.Status, result.Rank, competitor.ID, competitor.Status, place)
+	}
+	if result.Name != competitor.Name || result.Nation != competitor.Nation {
+		return fmt.Errorf("name %q and nation %q, want %q and %q", result.Name, result.Nation, competitor.Name, competitor.Nation)
+	}
+	if totalTime, ok := competitor.CalculateTotalTime(); ok && competitor.Status == domain.StatusFinished {
+		decoded, err := domain.ParseDurationFromString(result.TotalTime)
+		if err != nil || decoded != totalTime {
+			return fmt.Errorf("total time %q, want %v", result.TotalTime, totalTime)
+		}
+	} else if result.TotalTime != "" || result.Behind != "" {
+		return fmt.Errorf("total time %q and gap %q of a non-finisher", result.TotalTime, result.Behind)
+	}
+	if len(result.Laps) != len(competitor.LapDetails) {
+		return fmt.Errorf("%d laps, want %d", len(result.Laps), len(competitor.LapDetails))
+	}
+	for i, lap := range competitor.LapDetails {
+		if result.Laps[i].Number != i+1 {
+			return fmt.Errorf("lap %d has number %d", i+1, result.Laps[i].Number)
+		}
+		if lap.Duration <= 0 {
+			if result.Laps[i].Time != "" || result.Laps[i].Speed != "" {
+				return fmt.Errorf("lap %d without an end has time %q and speed %q", i+1, result.Laps[i].Time, result.Laps[i].Speed)
+			}
+			continue
+		}
+		decoded, err := domain.ParseDurationFromString(result.Laps[i].Time)
+		if err != nil || decoded != lap.Duration {
+			return fmt.Errorf("lap %d time %q, want %v", i+1, result.Laps[i].Time, lap.Duration)
+		}
+		if speed, err := strconv.ParseFloat(result.Laps[i].Speed, 64); err != nil || fmt.Sprintf("%.3f", speed) != fmt.Sprintf("%.3f", lap.Speed) {
+			return fmt.Errorf("lap %d speed %q, want %.3f", i+1, result.Laps[i].Speed, lap.Speed)
+		}
+	}
+	shooting := result.Shooting
+	if shooting.Hits != competitor.TotalHits || shooting.Shots != competitor.TotalShots || shooting.SpareRounds != competitor.TotalSpareRounds {
+		return fmt.Errorf("shooting %+v, want %d/%d with %d spare rounds",
+			shooting, competitor.TotalHits, competitor.TotalShots, competitor.TotalSpareRounds)
+	}
+	if accuracy, ok := competitor.Accuracy(); ok != (shooting.Accuracy != "") || ok && shooting.Accuracy != fmt.Sprintf("%.3f", accuracy) {
+		return fmt.Errorf("accuracy %q, want %.3f", shooting.Accuracy, accuracy)
+	}
+	penalty := result.Penalty
+	if penalty.Loops != competitor.TotalPenaltyLaps {
+		return fmt.Errorf("%d penalty loops, want %d", penalty.Loops, competitor.TotalPenaltyLaps)
+	}
+	if competitor.TotalPenaltyTime > 0 {
+		decoded, err := domain.ParseDurationFromString(penalty.Time)
+		if err != nil || decoded != competitor.TotalPenaltyTime {
+			return fmt.Errorf("penalty time %q, want %v", penalty.Time, competitor.TotalPenaltyTime)
+		}
+	} else if penalty.Time != "" || penalty.Speed != "" {
+		return fmt.Errorf("penalty time %q and speed %q without penalty loops", penalty.Time, penalty.Speed)
+	}
+	return nil
+}